@@ -0,0 +1,308 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/constants"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+const (
+	// DefaultBatchSize is the number of transactions per batch of a
+	// BatchBuilder created with size 0
+	DefaultBatchSize = 100
+	// DefaultBatchesPerList is the number of batches submitted per request by
+	// a BatchBuilder created with batchesPerList 0
+	DefaultBatchesPerList = 10
+)
+
+// Batch statuses reported by the validator
+const (
+	StatusCommitted = "COMMITTED"
+	StatusInvalid   = "INVALID"
+	StatusPending   = "PENDING"
+	StatusUnknown   = "UNKNOWN"
+)
+
+// TransactionStatus is the outcome of one transaction submitted by a
+// BatchBuilder. A batch is committed as a whole, so the Status is the status
+// of its batch: the transactions of a batch holding an invalid transaction are
+// all INVALID, and only the invalid one has a Message.
+type TransactionStatus struct {
+	Id      string `json:"id"`
+	Action  string `json:"action"`
+	Gtin    string `json:"gtin"`
+	BatchId string `json:"batch_id"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// BatchBuilder collects signed product transactions, one per operation, and
+// submits them in batches of up to batchSize transactions, batchesPerList
+// batches per request. It is meant for imports too large to send one request
+// per product.
+type BatchBuilder struct {
+	client         MdataClient
+	batchSize      int
+	batchesPerList int
+
+	transactions []*transaction_pb2.Transaction
+	statuses     []*TransactionStatus
+
+	// The product schema, loaded by the first operation with attributes
+	schema       *data.Schema
+	schemaLoaded bool
+}
+
+// NewBatchBuilder returns an empty BatchBuilder signing with the client's key.
+// A size or batchesPerList of 0 uses the default.
+func (mdataClient MdataClient) NewBatchBuilder(size int, batchesPerList int) *BatchBuilder {
+	if size < 1 {
+		size = DefaultBatchSize
+	}
+	if batchesPerList < 1 {
+		batchesPerList = DefaultBatchesPerList
+	}
+	return &BatchBuilder{client: mdataClient, batchSize: size, batchesPerList: batchesPerList}
+}
+
+// Len returns the number of transactions waiting to be submitted
+func (b *BatchBuilder) Len() int {
+	return len(b.transactions)
+}
+
+// Add signs the transaction of an operation and holds it until Submit
+func (b *BatchBuilder) Add(op Op) error {
+	c, err := op.clientAction()
+	if err != nil {
+		return err
+	}
+	if op.Action == constants.VERB_CREATE || op.Action == constants.VERB_UPDATE {
+		if err := b.checkAttributes(c.attrs); err != nil {
+			return err
+		}
+	}
+	if _, err := c.normalize(); err != nil {
+		return err
+	}
+
+	transaction, err := b.client.newTransaction(c)
+	if err != nil {
+		return err
+	}
+	b.transactions = append(b.transactions, transaction)
+	b.statuses = append(b.statuses, &TransactionStatus{
+		Id:     transaction.HeaderSignature,
+		Action: c.action,
+		Gtin:   c.gtin,
+	})
+	return nil
+}
+
+func (b *BatchBuilder) checkAttributes(attrs map[string]string) error {
+	if !b.schemaLoaded {
+		schema, err := b.client.GetSchema(data.ProductSchemaName)
+		if err != nil {
+			return err
+		}
+		b.schema = schema
+		b.schemaLoaded = true
+	}
+	if b.schema == nil {
+		return nil
+	}
+	return checkSchema(b.schema, attrs)
+}
+
+// Submit sends every transaction added since the last Submit and returns their
+// statuses in the order they were added. It waits up to wait seconds for each
+// request's batches to commit; with no wait the statuses are the ones reported
+// right after submission. If a request fails, the statuses of the transactions
+// already submitted are returned with the error, and the transactions of the
+// failed request and after it stay in the builder for the next Submit.
+func (b *BatchBuilder) Submit(wait uint) ([]*TransactionStatus, error) {
+	submitted := []*TransactionStatus{}
+	for len(b.transactions) > 0 {
+		n := len(b.transactions)
+		if n > b.batchSize*b.batchesPerList {
+			n = b.batchSize * b.batchesPerList
+		}
+		transactions, statuses := b.transactions[:n], b.statuses[:n]
+
+		batchList := batch_pb2.BatchList{}
+		batchIds := []string{}
+		for _, span := range chunk(n, b.batchSize) {
+			batch, err := b.client.createBatch(transactions[span[0]:span[1]])
+			if err != nil {
+				return submitted, fmt.Errorf("Unable to construct batch: %v", err)
+			}
+			batchList.Batches = append(batchList.Batches, batch)
+			batchIds = append(batchIds, batch.HeaderSignature)
+			for _, status := range statuses[span[0]:span[1]] {
+				status.BatchId = batch.HeaderSignature
+			}
+		}
+
+		body, err := proto.Marshal(&batchList)
+		if err != nil {
+			return submitted, fmt.Errorf("Unable to serialize batch list: %v", err)
+		}
+		if _, err := b.client.sendRequest(
+			constants.BATCH_SUBMIT_API, body, constants.CONTENT_TYPE_OCTET_STREAM, "batches"); err != nil {
+			return submitted, err
+		}
+
+		b.transactions, b.statuses = b.transactions[n:], b.statuses[n:]
+		for _, status := range statuses {
+			status.Status = StatusPending
+		}
+		submitted = append(submitted, statuses...)
+		b.logBatches(batchIds, statuses, false)
+		err = b.client.updateStatuses(batchIds, statuses, wait)
+		b.logBatches(batchIds, statuses, true)
+		if err != nil {
+			return submitted, err
+		}
+	}
+	b.transactions, b.statuses = nil, nil
+	return submitted, nil
+}
+
+// logBatches records the batches of a request in the client's submission log,
+// or, when final, the ones whose status is no longer PENDING. A batch is
+// recorded with the action and GTIN its transactions share, or with the batch
+// action and no GTIN when they differ.
+func (b *BatchBuilder) logBatches(batchIds []string, statuses []*TransactionStatus, final bool) {
+	for _, batchId := range batchIds {
+		var action, gtin string
+		result := &BatchResult{BatchId: batchId}
+		for _, status := range statuses {
+			if status.BatchId != batchId {
+				continue
+			}
+			if result.Status == "" {
+				action, gtin = status.Action, status.Gtin
+			}
+			if status.Action != action {
+				action = constants.VERB_BATCH
+			}
+			if status.Gtin != gtin {
+				gtin = ""
+			}
+			result.Status = status.Status
+			if status.Message != "" {
+				result.TransactionId = status.Id
+				result.Message = status.Message
+			}
+		}
+		if final && result.Status == StatusPending {
+			continue
+		}
+		b.client.logBatch(action, gtin, result)
+	}
+}
+
+// chunk splits n items into consecutive [start, end) spans of up to size items
+func chunk(n int, size int) [][2]int {
+	spans := [][2]int{}
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		spans = append(spans, [2]int{start, end})
+	}
+	return spans
+}
+
+type batchStatusResponse struct {
+	Data []batchStatus `json:"data"`
+}
+
+type batchStatus struct {
+	Id                  string               `json:"id"`
+	Status              string               `json:"status"`
+	InvalidTransactions []invalidTransaction `json:"invalid_transactions"`
+}
+
+type invalidTransaction struct {
+	Id      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// err returns ErrInvalidTransaction for an invalid batch, ErrUnknownBatch
+// for a batch the validator does not know, and nil otherwise
+func (b *batchStatus) err() error {
+	switch b.Status {
+	case StatusInvalid:
+		invalid := &ErrInvalidTransaction{BatchId: b.Id}
+		if len(b.InvalidTransactions) > 0 {
+			invalid.TransactionId = b.InvalidTransactions[0].Id
+			invalid.Message = b.InvalidTransactions[0].Message
+		}
+		return invalid
+	case StatusUnknown:
+		return &ErrUnknownBatch{b.Id}
+	}
+	return nil
+}
+
+// updateStatuses sets the status of every transaction of the given batches,
+// polling for up to wait seconds while any batch is pending
+func (mdataClient MdataClient) updateStatuses(batchIds []string, statuses []*TransactionStatus, wait uint) error {
+	deadline := time.Now().Add(time.Duration(wait) * time.Second)
+	for {
+		remaining := uint(0)
+		if left := time.Until(deadline); left > 0 {
+			remaining = uint(left.Seconds() + 0.5)
+		}
+		batchStatuses, err := mdataClient.getStatuses(batchIds, remaining)
+		if err != nil {
+			return err
+		}
+
+		batchStatus := make(map[string]string)
+		messages := make(map[string]string)
+		for _, entry := range batchStatuses {
+			batchStatus[entry.Id] = entry.Status
+			for _, invalid := range entry.InvalidTransactions {
+				messages[invalid.Id] = invalid.Message
+			}
+		}
+		pending := false
+		for _, status := range statuses {
+			if s, ok := batchStatus[status.BatchId]; ok {
+				status.Status = s
+			}
+			status.Message = messages[status.Id]
+			if status.Status == StatusPending {
+				pending = true
+			}
+		}
+
+		if !pending || remaining == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunk(t *testing.T) {
+	tests := map[string]struct {
+		inCount  int
+		inSize   int
+		outSpans [][2]int
+	}{
+		"empty": {
+			inCount:  0,
+			inSize:   100,
+			outSpans: [][2]int{},
+		},
+		"lessThanSize": {
+			inCount:  3,
+			inSize:   100,
+			outSpans: [][2]int{{0, 3}},
+		},
+		"exactMultiple": {
+			inCount:  4,
+			inSize:   2,
+			outSpans: [][2]int{{0, 2}, {2, 4}},
+		},
+		"remainder": {
+			inCount:  5,
+			inSize:   2,
+			outSpans: [][2]int{{0, 2}, {2, 4}, {4, 5}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, test.outSpans, chunk(test.inCount, test.inSize))
+	}
+}
+
+// validator serves /batches and /batch_statuses. It records the batches of
+// every batch list posted, answers the request numbered failRequest, counting
+// from 1, with a 503, and reports every batch COMMITTED, or PENDING while
+// pending is set, but for the one numbered invalidBatch, whose first
+// transaction is INVALID.
+type validator struct {
+	failRequest  int
+	invalidBatch int
+	pending      bool
+
+	requests int
+	lists    [][]*batch_pb2.Batch
+	batches  map[string]*batch_pb2.Batch
+	invalid  string
+}
+
+func (v *validator) serve() *httptest.Server {
+	v.batches = make(map[string]*batch_pb2.Batch)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/batches":
+			v.requests++
+			if v.requests == v.failRequest {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			batchList := &batch_pb2.BatchList{}
+			if err := proto.Unmarshal(body, batchList); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			v.lists = append(v.lists, batchList.Batches)
+			for _, batch := range batchList.Batches {
+				v.batches[batch.HeaderSignature] = batch
+				if len(v.batches) == v.invalidBatch {
+					v.invalid = batch.HeaderSignature
+				}
+			}
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, `{"link": "/batch_statuses"}`)
+		case "/batch_statuses":
+			response := batchStatusResponse{}
+			for _, id := range strings.Split(r.URL.Query().Get("id"), ",") {
+				status := batchStatus{Id: id, Status: StatusCommitted}
+				if v.pending {
+					status.Status = StatusPending
+				}
+				if id == v.invalid {
+					status.Status = StatusInvalid
+					status.InvalidTransactions = []invalidTransaction{{Id: v.batches[id].Transactions[0].HeaderSignature, Message: "Product does not exist"}}
+				}
+				response.Data = append(response.Data, status)
+			}
+			json.NewEncoder(w).Encode(response)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// sizes returns the number of transactions of every batch, by request
+func (v *validator) sizes() [][]int {
+	sizes := [][]int{}
+	for _, list := range v.lists {
+		batches := []int{}
+		for _, batch := range list {
+			batches = append(batches, len(batch.Transactions))
+		}
+		sizes = append(sizes, batches)
+	}
+	return sizes
+}
+
+var builderGtins = []string{"00012345600012", "00012345600029", "00012345600036", "00098765400012", "00012345678905"}
+
+func newTestBuilder(t *testing.T, url string, size int, batchesPerList int, count int) *BatchBuilder {
+	mdataClient, err := NewMdataClient(url, "")
+	assert.Nil(t, err)
+	return addTestOps(t, mdataClient, size, batchesPerList, count)
+}
+
+func addTestOps(t *testing.T, mdataClient MdataClient, size int, batchesPerList int, count int) *BatchBuilder {
+	builder := mdataClient.NewBatchBuilder(size, batchesPerList)
+	for _, gtin := range builderGtins[:count] {
+		assert.Nil(t, builder.Add(Op{Action: "delete", Gtin: gtin}))
+	}
+	return builder
+}
+
+func TestBatchBuilderSubmit(t *testing.T) {
+	tests := map[string]struct {
+		inSize           int
+		inBatchesPerList int
+		inCount          int
+		inInvalidBatch   int
+		outSizes         [][]int
+		outStatuses      []string
+	}{
+		"oneBatch": {
+			inCount:     3,
+			outSizes:    [][]int{{3}},
+			outStatuses: []string{StatusCommitted, StatusCommitted, StatusCommitted},
+		},
+		"splitIntoBatches": {
+			inSize:      2,
+			inCount:     5,
+			outSizes:    [][]int{{2, 2, 1}},
+			outStatuses: []string{StatusCommitted, StatusCommitted, StatusCommitted, StatusCommitted, StatusCommitted},
+		},
+		"batchesPerList": {
+			inSize:           2,
+			inBatchesPerList: 2,
+			inCount:          5,
+			outSizes:         [][]int{{2, 2}, {1}},
+			outStatuses:      []string{StatusCommitted, StatusCommitted, StatusCommitted, StatusCommitted, StatusCommitted},
+		},
+		"invalidBatch": {
+			inSize:           2,
+			inBatchesPerList: 2,
+			inCount:          5,
+			inInvalidBatch:   2,
+			outSizes:         [][]int{{2, 2}, {1}},
+			outStatuses:      []string{StatusCommitted, StatusCommitted, StatusInvalid, StatusInvalid, StatusCommitted},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		v := &validator{invalidBatch: test.inInvalidBatch}
+		server := v.serve()
+		builder := newTestBuilder(t, server.URL, test.inSize, test.inBatchesPerList, test.inCount)
+
+		statuses, err := builder.Submit(0)
+		server.Close()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, builder.Len())
+		assert.Equal(t, test.outSizes, v.sizes())
+
+		// Statuses are in the order the operations were added, and name the
+		// batch that carried their transaction
+		sent := map[string]string{}
+		for _, list := range v.lists {
+			for _, batch := range list {
+				for _, transaction := range batch.Transactions {
+					sent[transaction.HeaderSignature] = batch.HeaderSignature
+				}
+			}
+		}
+		gtins, got := []string{}, []string{}
+		for _, status := range statuses {
+			gtins = append(gtins, status.Gtin)
+			got = append(got, status.Status)
+			assert.Equal(t, sent[status.Id], status.BatchId)
+			assert.Equal(t, status.Status == StatusInvalid && status.Gtin == builderGtins[2], status.Message != "")
+		}
+		assert.Equal(t, builderGtins[:test.inCount], gtins)
+		assert.Equal(t, test.outStatuses, got)
+	}
+}
+
+// The transactions of a failed request stay in the builder for the next Submit
+func TestBatchBuilderPartialFailure(t *testing.T) {
+	v := &validator{failRequest: 2}
+	server := v.serve()
+	defer server.Close()
+	builder := newTestBuilder(t, server.URL, 2, 2, 5)
+
+	statuses, err := builder.Submit(0)
+	assert.Equal(t, &ErrUnavailable{"Error 503: 503 Service Unavailable"}, err)
+	assert.Equal(t, 4, len(statuses))
+	assert.Equal(t, 1, builder.Len())
+
+	statuses, err = builder.Submit(0)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(statuses)) {
+		assert.Equal(t, builderGtins[4], statuses[0].Gtin)
+		assert.Equal(t, StatusCommitted, statuses[0].Status)
+	}
+	assert.Equal(t, 0, builder.Len())
+	assert.Equal(t, [][]int{{2, 2}, {1}}, v.sizes())
+}
+
+// Builder batches are logged as PENDING when sent, and again once committed
+func TestBatchBuilderSubmissionLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "submissions")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	log := NewSubmissionLog(path.Join(dir, "submissions.log"))
+
+	v := &validator{pending: true}
+	server := v.serve()
+	defer server.Close()
+	mdataClient, err := NewMdataClient(server.URL, "")
+	assert.Nil(t, err)
+	mdataClient = mdataClient.WithSubmissionLog(log)
+
+	builder := addTestOps(t, mdataClient, 2, 1, 3)
+	assert.Nil(t, builder.Add(Op{Action: "set", Gtin: builderGtins[3], State: "INACTIVE"}))
+	assert.Nil(t, builder.Add(Op{Action: "delete", Gtin: builderGtins[4]}))
+	statuses, err := builder.Submit(0)
+	assert.Nil(t, err)
+
+	pending, err := log.Pending()
+	assert.Nil(t, err)
+	logged := []Submission{}
+	for _, submission := range pending {
+		submission.Time = time.Time{}
+		logged = append(logged, *submission)
+	}
+	assert.Equal(t, []Submission{
+		{BatchId: statuses[0].BatchId, Action: "delete", Status: StatusPending},
+		{BatchId: statuses[2].BatchId, Action: "batch", Status: StatusPending},
+		{BatchId: statuses[4].BatchId, Action: "delete", Gtin: builderGtins[4], Status: StatusPending},
+	}, logged)
+
+	v.pending = false
+	assert.Nil(t, builder.Add(Op{Action: "delete", Gtin: builderGtins[0]}))
+	statuses, err = builder.Submit(0)
+	assert.Nil(t, err)
+	submissions, err := log.Submissions()
+	assert.Nil(t, err)
+	if assert.Equal(t, 4, len(submissions)) {
+		assert.Equal(t, statuses[0].BatchId, submissions[3].BatchId)
+		assert.Equal(t, builderGtins[0], submissions[3].Gtin)
+		assert.Equal(t, StatusCommitted, submissions[3].Status)
+	}
+	pending, err = log.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(pending))
+}
@@ -0,0 +1,127 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/constants"
+)
+
+// AtHead returns a client reading state as of the block with the given id,
+// using the head parameter of the REST API. "" reads the current head.
+// Transactions are still sent to the current head.
+func (mdataClient MdataClient) AtHead(blockId string) MdataClient {
+	mdataClient.head = blockId
+	return mdataClient
+}
+
+// ReadAt returns a client reading state as of the block the options select
+func (mdataClient MdataClient) ReadAt(options commands.ReadOptions) (MdataClient, error) {
+	if options.At == nil {
+		return mdataClient.AtHead(options.Head), nil
+	}
+	if options.Head != "" {
+		return MdataClient{}, errors.New("Use either --head or --at, not both")
+	}
+	blockId, err := mdataClient.BlockId(*options.At)
+	if err != nil {
+		return MdataClient{}, err
+	}
+	return mdataClient.AtHead(blockId), nil
+}
+
+// blockNum reads the block_num of a block header, which the REST API encodes
+// as a string
+type blockNum uint64
+
+func (n *blockNum) UnmarshalJSON(b []byte) error {
+	num, err := strconv.ParseUint(strings.Trim(string(b), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Malformed block number %s: %v", b, err)
+	}
+	*n = blockNum(num)
+	return nil
+}
+
+type blocksResponse struct {
+	Data []struct {
+		HeaderSignature string `json:"header_signature"`
+		Header          struct {
+			BlockNum blockNum `json:"block_num"`
+		} `json:"header"`
+	} `json:"data"`
+	Paging struct {
+		Next string `json:"next"`
+	} `json:"paging"`
+}
+
+// BlockId returns the id of the block with the given number on the current
+// chain. Blocks are listed newest first, starting at the paging position of
+// the block number. A REST API that does not take block numbers as paging
+// positions, or a block number past the head, is read from the head down
+// instead.
+func (mdataClient MdataClient) BlockId(num uint64) (string, error) {
+	resource := fmt.Sprintf("block: %d", num)
+	query := url.Values{"start": {fmt.Sprintf("0x%016x", num)}, "limit": {"100"}}.Encode()
+	blockId, err := mdataClient.findBlock(num, query, resource)
+	if err == nil {
+		return blockId, nil
+	}
+	logger.Debugf("Reading blocks from the head, as the REST API did not take start %#x: %v", num, err)
+	return mdataClient.findBlock(num, url.Values{"limit": {"100"}}.Encode(), resource)
+}
+
+// findBlock walks the pages of /blocks from query until it reaches the block
+// with the given number, or one below it
+func (mdataClient MdataClient) findBlock(num uint64, query string, resource string) (string, error) {
+	for query != "" {
+		response, err := mdataClient.sendRequest(constants.BLOCKS_API+"?"+query, []byte{}, "", resource)
+		if err != nil {
+			return "", err
+		}
+		page := blocksResponse{}
+		if err := json.Unmarshal([]byte(response), &page); err != nil {
+			return "", fmt.Errorf("Error reading response: %v", err)
+		}
+		for _, block := range page.Data {
+			if uint64(block.Header.BlockNum) == num {
+				return block.HeaderSignature, nil
+			}
+			if uint64(block.Header.BlockNum) < num {
+				return "", &ErrNotFound{resource}
+			}
+		}
+
+		query = ""
+		if page.Paging.Next != "" {
+			nextUrl, err := url.Parse(page.Paging.Next)
+			if err != nil {
+				return "", fmt.Errorf("Error reading paging: %v", err)
+			}
+			query = nextUrl.RawQuery
+		}
+	}
+	return "", &ErrNotFound{resource}
+}
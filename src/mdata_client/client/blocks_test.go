@@ -0,0 +1,105 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
+)
+
+// chain serves /blocks for a chain of blocks 0 to head, newest first, two per
+// page. Paging positions are block numbers unless byNumber is false, in which
+// case a start is rejected like an unknown block id.
+func chain(head uint64, byNumber bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		num := head
+		if start := r.URL.Query().Get("start"); start != "" {
+			if !byNumber || !strings.HasPrefix(start, "0x") {
+				http.Error(w, `{"error": {"code": 54}}`, http.StatusBadRequest)
+				return
+			}
+			num, _ = strconv.ParseUint(start[2:], 16, 64)
+			if num > head {
+				http.NotFound(w, r)
+				return
+			}
+		}
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			num, _ = strconv.ParseUint(cursor, 10, 64)
+		}
+
+		blocks := []string{}
+		for i := 0; i < 2 && num+1 > uint64(i); i++ {
+			blocks = append(blocks, fmt.Sprintf(`{"header_signature": "block%d", "header": {"block_num": "%d"}}`, num-uint64(i), num-uint64(i)))
+		}
+		next := ""
+		if num >= 2 {
+			next = fmt.Sprintf("http://rest-api:8008/blocks?limit=2&cursor=%d", num-2)
+		}
+		fmt.Fprintf(w, `{"data": [%s], "paging": {"next": "%s"}}`, strings.Join(blocks, ", "), next)
+	}))
+}
+
+func TestBlockId(t *testing.T) {
+	tests := map[string]struct {
+		inByNumber bool
+		inNum      uint64
+		outBlockId string
+		outErr     string
+	}{
+		"byNumber": {
+			inByNumber: true,
+			inNum:      3,
+			outBlockId: "block3",
+		},
+		"fromHead": {
+			inNum:      3,
+			outBlockId: "block3",
+		},
+		"genesis": {
+			inNum:      0,
+			outBlockId: "block0",
+		},
+		"pastHead": {
+			inByNumber: true,
+			inNum:      8,
+			outErr:     "No such block: 8",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		server := chain(6, test.inByNumber)
+		blockId, err := MdataClient{url: server.URL}.BlockId(test.inNum)
+		server.Close()
+		if test.outErr != "" {
+			assert.EqualError(t, err, test.outErr)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.outBlockId, blockId)
+	}
+}
+
+func TestReadAt(t *testing.T) {
+	server := chain(6, true)
+	defer server.Close()
+	mdataClient := MdataClient{url: server.URL}
+	at := uint64(4)
+
+	reader, err := mdataClient.ReadAt(commands.ReadOptions{At: &at})
+	assert.Nil(t, err)
+	assert.Equal(t, "block4", reader.head)
+
+	reader, err = mdataClient.ReadAt(commands.ReadOptions{Head: "block2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "block2", reader.head)
+
+	_, err = mdataClient.ReadAt(commands.ReadOptions{Head: "block2", At: &at})
+	assert.EqualError(t, err, "Use either --head or --at, not both")
+}
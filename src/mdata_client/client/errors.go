@@ -0,0 +1,119 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// ErrNotFound is returned when a product, or another resource, is not in
+// state
+type ErrNotFound struct {
+	Resource string
+}
+
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("No such %s", e.Resource)
+}
+
+// ErrInvalidTransaction is returned when the validator rejects a transaction
+// the client waited for. Message is the reason the transaction processor gave.
+type ErrInvalidTransaction struct {
+	BatchId       string
+	TransactionId string
+	Message       string
+}
+
+func (e *ErrInvalidTransaction) Error() string {
+	return fmt.Sprintf("Invalid transaction: %s", e.Message)
+}
+
+// ErrTimeout is returned when a transaction is still pending once the wait is
+// over. It may still be committed later.
+type ErrTimeout struct {
+	BatchId string
+	Wait    uint
+}
+
+func (e *ErrTimeout) Error() string {
+	return fmt.Sprintf("Batch %s is still pending after waiting %d seconds", e.BatchId, e.Wait)
+}
+
+// ErrUnknownBatch is returned when the validator does not know a submitted
+// batch, e.g. because it was dropped from a full queue
+type ErrUnknownBatch struct {
+	BatchId string
+}
+
+func (e *ErrUnknownBatch) Error() string {
+	return fmt.Sprintf("Batch %s is unknown to the validator", e.BatchId)
+}
+
+// ErrUnavailable is returned when the REST API can not be reached, or answers
+// that it can not reach the validator
+type ErrUnavailable struct {
+	Message string
+}
+
+func (e *ErrUnavailable) Error() string {
+	return e.Message
+}
+
+// ErrBusy is returned when the validator is too busy to take a batch, i.e.
+// its queue is full. The batch can be sent again later.
+type ErrBusy struct {
+	Message string
+}
+
+func (e *ErrBusy) Error() string {
+	return e.Message
+}
+
+// ErrRejected is returned when the REST API refuses a request, e.g. reading
+// state at a head it does not know. Sawtooth is the error it answered with.
+type ErrRejected struct {
+	Status   int
+	Sawtooth SawtoothError
+}
+
+func (e *ErrRejected) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Status, e.Sawtooth.Message)
+}
+
+// SawtoothError is the error body of the Sawtooth REST API
+type SawtoothError struct {
+	Code    int    `json:"code"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// responseError reads the error of a failed response. Responses without a
+// Sawtooth error body, e.g. from a proxy, are described by their status.
+func responseError(response *http.Response) SawtoothError {
+	body := struct {
+		Error SawtoothError `json:"error"`
+	}{}
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil || json.Unmarshal(b, &body) != nil || body.Error.Message == "" {
+		return SawtoothError{Title: response.Status, Message: response.Status}
+	}
+	return body.Error
+}
@@ -0,0 +1,225 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tross-tyson/mdata_go/src/mdata_client/constants"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// ListOptions selects the products to list. Limit and Start are the paging
+// parameters of the REST API: Limit is the number of state entries read per
+// page, and Start the paging position to begin at. The other fields filter
+// the products as they are read, so a page can hold fewer than Limit products.
+type ListOptions struct {
+	Limit      uint
+	Start      string
+	GtinPrefix string
+	States     []string
+	Attributes map[string]string
+}
+
+// Match reports whether a product passes the filters of the options. Attribute
+// values are compared as the strings Attributes.Strings writes them out as, so
+// a number or boolean read from state matches its text.
+func (o ListOptions) Match(product *data.Product) bool {
+	if !strings.HasPrefix(product.Gtin, o.GtinPrefix) {
+		return false
+	}
+	if len(o.States) > 0 && !contains(o.States, product.State) {
+		return false
+	}
+	for k, v := range o.Attributes {
+		value, ok := product.Attributes[k]
+		if !ok {
+			return false
+		}
+		if str, ok := data.AttributeString(value); !ok || str != v {
+			return false
+		}
+	}
+	return true
+}
+
+// query returns the query string of the first page of products to read
+func (o ListOptions) query() string {
+	query := url.Values{}
+	query.Set("address", address.Namespace)
+	if o.Limit > 0 {
+		query.Set("limit", strconv.FormatUint(uint64(o.Limit), 10))
+	}
+	if o.Start != "" {
+		query.Set("start", o.Start)
+	}
+	return query.Encode()
+}
+
+// ProductIterator streams the products of every page of state, reading the
+// next page once the products of the last one have been returned
+type ProductIterator struct {
+	client   MdataClient
+	options  ListOptions
+	query    string
+	products []*data.Product
+	product  *data.Product
+	err      error
+}
+
+// Products returns an iterator over the products matching options, in address
+// order
+func (mdataClient MdataClient) Products(options ListOptions) *ProductIterator {
+	return &ProductIterator{client: mdataClient, options: options, query: options.query()}
+}
+
+// Next advances to the next product. It returns false after the last product,
+// or when a page can not be read.
+func (it *ProductIterator) Next() bool {
+	for len(it.products) == 0 {
+		if it.err != nil || it.query == "" {
+			it.product = nil
+			return false
+		}
+		it.products, it.query, it.err = it.client.productPage(it.query, it.options)
+	}
+	it.product = it.products[0]
+	it.products = it.products[1:]
+	return true
+}
+
+// Product returns the current product
+func (it *ProductIterator) Product() *data.Product {
+	return it.product
+}
+
+// Err returns the error that stopped the iterator, if any
+func (it *ProductIterator) Err() error {
+	return it.err
+}
+
+// ListPage returns the matching products of the page of state options select,
+// and the paging position of the next page, "" after the last page
+func (mdataClient MdataClient) ListPage(options ListOptions) ([]*data.Product, string, error) {
+	products, next, err := mdataClient.productPage(options.query(), options)
+	if err != nil {
+		return nil, "", err
+	}
+	return products, pagingStart(next), nil
+}
+
+// productPage returns the matching products of a page of state, and the query
+// string of the page after it
+func (mdataClient MdataClient) productPage(query string, options ListOptions) ([]*data.Product, string, error) {
+	entries, next, err := mdataClient.statePage(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	products := []*data.Product{}
+	for _, entry := range entries {
+		// Each address holds its own encoded product container. Organization
+		// and company prefix records share the namespace and are skipped.
+		entryProducts, err := data.Deserialize(entry.data)
+		if err != nil {
+			if isProductAddress(entry.address) {
+				return nil, "", err
+			}
+			continue
+		}
+		for _, product := range entryProducts {
+			if address.MakeProductAddress(product.Gtin) == entry.address && options.Match(product) {
+				products = append(products, product)
+			}
+		}
+	}
+	return products, next, nil
+}
+
+type stateEntry struct {
+	address string
+	data    []byte
+}
+
+type stateResponse struct {
+	Data []struct {
+		Address string `json:"address"`
+		Data    string `json:"data"`
+	} `json:"data"`
+	Paging struct {
+		Next string `json:"next"`
+	} `json:"paging"`
+}
+
+// statePage reads one page of state entries. It returns the query string of
+// the page paging.next links to, or "" for the last page. Only the query of
+// the link is kept, as its host is the one the REST API sees, which may not
+// be reachable from here.
+func (mdataClient MdataClient) statePage(query string) ([]stateEntry, string, error) {
+	if mdataClient.head != "" {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, "", err
+		}
+		values.Set("head", mdataClient.head)
+		query = values.Encode()
+	}
+	response, err := mdataClient.sendRequest(constants.STATE_API+"?"+query, []byte{}, "", "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := stateResponse{}
+	if err := json.Unmarshal([]byte(response), &page); err != nil {
+		return nil, "", fmt.Errorf("Error reading response: %v", err)
+	}
+
+	entries := []stateEntry{}
+	for _, entry := range page.Data {
+		decodedBytes, err := base64.StdEncoding.DecodeString(entry.Data)
+		if err != nil {
+			return nil, "", fmt.Errorf("Error decoding: %v", err)
+		}
+		entries = append(entries, stateEntry{entry.Address, decodedBytes})
+	}
+
+	next := ""
+	if page.Paging.Next != "" {
+		nextUrl, err := url.Parse(page.Paging.Next)
+		if err != nil {
+			return nil, "", fmt.Errorf("Error reading paging: %v", err)
+		}
+		next = nextUrl.RawQuery
+	}
+	return entries, next, nil
+}
+
+// pagingStart returns the paging position of a page's query string
+func pagingStart(query string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	return values.Get("start")
+}
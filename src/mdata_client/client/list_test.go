@@ -0,0 +1,139 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// pagedState serves products from /state one per page, linking each page to
+// the next with an absolute paging.next URL on another host
+func pagedState(products []*data.Product) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := 0
+		for i, product := range products {
+			if address.MakeProductAddress(product.Gtin) == r.URL.Query().Get("start") {
+				start = i
+			}
+		}
+		page := stateResponse{}
+		product := products[start]
+		page.Data = append(page.Data, struct {
+			Address string `json:"address"`
+			Data    string `json:"data"`
+		}{
+			address.MakeProductAddress(product.Gtin),
+			base64.StdEncoding.EncodeToString(data.Serialize([]*data.Product{product})),
+		})
+		if start+1 < len(products) {
+			page.Paging.Next = "http://rest-api:8008/state?address=" + address.Namespace +
+				"&limit=1&start=" + address.MakeProductAddress(products[start+1].Gtin)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+}
+
+func TestProducts(t *testing.T) {
+	products := []*data.Product{
+		{Gtin: "00012345600012", Attributes: data.Attributes{"uom": "cases"}, State: "ACTIVE"},
+		{Gtin: "00012345600029", Attributes: data.Attributes{"uom": "lbs"}, State: "ACTIVE"},
+		{Gtin: "00061414100003", Attributes: data.Attributes{"uom": "cases"}, State: "INACTIVE"},
+	}
+	server := pagedState(products)
+	defer server.Close()
+	mdataClient := MdataClient{url: server.URL}
+
+	tests := map[string]struct {
+		inOptions   ListOptions
+		outProducts []*data.Product
+	}{
+		"all": {
+			outProducts: products,
+		},
+		"gtinPrefix": {
+			inOptions:   ListOptions{GtinPrefix: "000123456"},
+			outProducts: products[:2],
+		},
+		"state": {
+			inOptions:   ListOptions{States: []string{"INACTIVE", "DISCONTINUED"}},
+			outProducts: products[2:],
+		},
+		"attribute": {
+			inOptions:   ListOptions{Attributes: map[string]string{"uom": "cases"}},
+			outProducts: []*data.Product{products[0], products[2]},
+		},
+		"missingAttribute": {
+			inOptions:   ListOptions{Attributes: map[string]string{"color": "red"}},
+			outProducts: []*data.Product{},
+		},
+		"start": {
+			inOptions:   ListOptions{Start: address.MakeProductAddress(products[1].Gtin)},
+			outProducts: products[1:],
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		listed := []*data.Product{}
+		it := mdataClient.Products(test.inOptions)
+		for it.Next() {
+			listed = append(listed, it.Product())
+		}
+		assert.Nil(t, it.Err())
+		assert.Equal(t, test.outProducts, listed)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	product := &data.Product{
+		Gtin:       "00012345600012",
+		Attributes: data.Attributes{"uom": "cases", "weight": int64(300), "price": float64(2.5), "organic": true, "size": map[string]interface{}{"width": "3"}},
+		State:      "ACTIVE",
+	}
+
+	tests := map[string]struct {
+		inAttributes map[string]string
+		outMatch     bool
+	}{
+		"string":           {inAttributes: map[string]string{"uom": "cases"}, outMatch: true},
+		"integer":          {inAttributes: map[string]string{"weight": "300"}, outMatch: true},
+		"float":            {inAttributes: map[string]string{"price": "2.5"}, outMatch: true},
+		"boolean":          {inAttributes: map[string]string{"organic": "true"}, outMatch: true},
+		"all":              {inAttributes: map[string]string{"uom": "cases", "weight": "300", "organic": "true"}, outMatch: true},
+		"otherInteger":     {inAttributes: map[string]string{"weight": "301"}, outMatch: false},
+		"otherBoolean":     {inAttributes: map[string]string{"organic": "false"}, outMatch: false},
+		"objectNever":      {inAttributes: map[string]string{"size": "map[width:3]"}, outMatch: false},
+		"missingAttribute": {inAttributes: map[string]string{"color": "red"}, outMatch: false},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, test.outMatch, ListOptions{Attributes: test.inAttributes}.Match(product))
+	}
+}
+
+func TestListPage(t *testing.T) {
+	products := []*data.Product{
+		{Gtin: "00012345600012", Attributes: data.Attributes{}, State: "ACTIVE"},
+		{Gtin: "00012345600029", Attributes: data.Attributes{}, State: "INACTIVE"},
+	}
+	server := pagedState(products)
+	defer server.Close()
+	mdataClient := MdataClient{url: server.URL}
+
+	listed, next, err := mdataClient.ListPage(ListOptions{Limit: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, products[:1], listed)
+	assert.Equal(t, address.MakeProductAddress(products[1].Gtin), next)
+
+	listed, next, err = mdataClient.ListPage(ListOptions{Limit: 1, Start: next, States: []string{"ACTIVE"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []*data.Product{}, listed)
+	assert.Equal(t, "", next)
+}
@@ -31,15 +31,20 @@ import (
 	"github.com/hyperledger/sawtooth-sdk-go/signing"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"  //mdata_client/commands
 	"github.com/tross-tyson/mdata_go/src/mdata_client/constants" //mdata_client/constants
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+	"github.com/tross-tyson/mdata_go/src/shared/gs1"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/payload_pb2"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"os/user"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
-	"time"
 )
 
 var logger *logging.Logger = logging.Get()
@@ -57,7 +62,18 @@ func GetClient(args commands.Command, readFile bool) (MdataClient, error) {
 			return MdataClient{}, err
 		}
 	}
-	return NewMdataClient(url, keyfile)
+	mdataClient, err := NewMdataClient(url, keyfile)
+	if err != nil {
+		return MdataClient{}, err
+	}
+	// Batches sent by commands are recorded so mdata status --pending can
+	// track them after the command returned
+	submissions, err := DefaultSubmissionLog()
+	if err != nil {
+		logger.Warnf("No submission log: %v", err)
+		return mdataClient, nil
+	}
+	return mdataClient.WithSubmissionLog(submissions), nil
 }
 
 func GetKeyfile(keyfile string) (string, error) {
@@ -73,31 +89,323 @@ func GetKeyfile(keyfile string) (string, error) {
 	}
 }
 
+// Client reads and changes products. MdataClient implements it against the
+// REST API, MockClient stands in for it in tests.
+//
+// Reads return ErrNotFound for missing products. Writes that wait return
+// ErrInvalidTransaction when the validator rejects the transaction, and
+// ErrTimeout when it is still pending once the wait is over. Either returns
+// ErrUnavailable when the REST API or the validator can not be reached,
+// ErrBusy when the validator's queue is full, and ErrRejected when the REST
+// API refuses the request.
+type Client interface {
+	Show(gtin string) (*data.Product, error)
+	List() ([]*data.Product, error)
+	ListPage(options ListOptions) ([]*data.Product, string, error)
+	History(gtin string) ([]*data.ProductRevision, error)
+	Create(gtin string, attrs map[string]string, wait uint) (*BatchResult, error)
+	Update(gtin string, attrs map[string]string, expectedRevision uint64, wait uint) (*BatchResult, error)
+	Patch(gtin string, attrs map[string]string, unset []string, expectedRevision uint64, wait uint) (*BatchResult, error)
+	Delete(gtin string, expectedRevision uint64, wait uint) (*BatchResult, error)
+	Set(gtin string, state string, reason string, expectedRevision uint64, wait uint) (*BatchResult, error)
+	Transfer(gtin string, newOwner string, expectedRevision uint64, wait uint) (*BatchResult, error)
+	Apply(ops []Op, wait uint) (*BatchResult, error)
+	Status(batchIds []string, wait uint) ([]*BatchResult, error)
+}
+
+var _ Client = MdataClient{}
+
 type MdataClient struct {
-	url    string
-	signer *signing.Signer
+	url         string
+	signer      *signing.Signer
+	submissions *SubmissionLog
+	head        string
+}
+
+// WithSubmissionLog returns a client recording the batches it sends in log
+func (mdataClient MdataClient) WithSubmissionLog(log *SubmissionLog) MdataClient {
+	mdataClient.submissions = log
+	return mdataClient
 }
 
 type MdataClientAction struct {
-	action string
-	gtin   string
-	wait   uint
-	attrs  map[string]string
-	state  string
+	action   string
+	gtin     string
+	wait     uint
+	attrs    map[string]string
+	state    string
+	reason   string
+	newOwner string
+	unset    []string
+
+	// Revision the product must be at, 0 if any
+	expectedRevision uint64
+
+	orgId     string
+	orgName   string
+	prefixes  []string
+	publicKey string
+
+	active bool
+	roles  []string
+
+	schema *data.Schema
+
+	ops []MdataClientAction
+}
+
+// Op is one product operation of an Apply. Action is one of create, update,
+// set or delete, and the fields it does not use are ignored. Attribute values
+// are strings, numbers or booleans.
+type Op struct {
+	Action           string          `json:"action"`
+	Gtin             string          `json:"gtin"`
+	Attributes       data.Attributes `json:"attributes,omitempty"`
+	State            string          `json:"state,omitempty"`
+	Reason           string          `json:"reason,omitempty"`
+	ExpectedRevision uint64          `json:"expected_revision,omitempty"`
+}
+
+func (c *MdataClientAction) isProductAction() bool {
+	switch c.action {
+	case constants.VERB_CREATE, constants.VERB_UPDATE, constants.VERB_PATCH, constants.VERB_SET_STATE, constants.VERB_DELETE, constants.VERB_TRANSFER:
+		return true
+	}
+	return false
+}
+
+// addresses returns the state the transaction reads and writes. Changing a
+// product reads the company prefix, organization and agent records to check
+// the signer's roles and appends to its history, creating it or changing its
+// state reads the lifecycle setting, and changing an organization's company
+// prefixes writes their records.
+func (c *MdataClientAction) addresses() ([]string, []string) {
+	switch c.action {
+	case constants.VERB_CREATE:
+		product := address.MakeProductAddress(c.gtin)
+		history := address.MakeHistoryAddress(c.gtin)
+		schema := address.MakeSchemaAddress(data.ProductSchemaName)
+		lifecycle := address.MakeSettingAddress(data.LifecycleSetting)
+		return []string{product, history, address.OrganizationSpace, address.CompanyPrefixSpace, address.AgentSpace, schema, lifecycle}, []string{product, history}
+	case constants.VERB_UPDATE, constants.VERB_PATCH:
+		product := address.MakeProductAddress(c.gtin)
+		history := address.MakeHistoryAddress(c.gtin)
+		schema := address.MakeSchemaAddress(data.ProductSchemaName)
+		return []string{product, history, address.OrganizationSpace, address.CompanyPrefixSpace, address.AgentSpace, schema}, []string{product, history}
+	case constants.VERB_SET_STATE, constants.VERB_DELETE:
+		product := address.MakeProductAddress(c.gtin)
+		history := address.MakeHistoryAddress(c.gtin)
+		lifecycle := address.MakeSettingAddress(data.LifecycleSetting)
+		return []string{product, history, address.OrganizationSpace, address.CompanyPrefixSpace, address.AgentSpace, lifecycle}, []string{product, history}
+	case constants.VERB_TRANSFER:
+		product := address.MakeProductAddress(c.gtin)
+		history := address.MakeHistoryAddress(c.gtin)
+		return []string{product, history, address.OrganizationSpace, address.CompanyPrefixSpace, address.AgentSpace}, []string{product, history}
+	case constants.VERB_ORG_CREATE, constants.VERB_ORG_UPDATE:
+		organization := address.MakeOrganizationAddress(c.orgId)
+		return []string{organization, address.CompanyPrefixSpace}, []string{organization, address.CompanyPrefixSpace}
+	case constants.VERB_ORG_ADD_KEY, constants.VERB_ORG_REMOVE_KEY:
+		organization := address.MakeOrganizationAddress(c.orgId)
+		return []string{organization}, []string{organization}
+	case constants.VERB_AGENT_CREATE, constants.VERB_AGENT_UPDATE:
+		agent := address.MakeAgentAddress(c.publicKey)
+		return []string{agent, address.OrganizationSpace}, []string{agent}
+	case constants.VERB_SCHEMA_CREATE, constants.VERB_SCHEMA_UPDATE:
+		schema := address.MakeSchemaAddress(c.schema.Name)
+		return []string{schema, address.OrganizationSpace}, []string{schema}
+	case constants.VERB_BATCH:
+		// Every address any of the operations reads or writes
+		inputs := make(map[string]bool)
+		outputs := make(map[string]bool)
+		for _, op := range c.ops {
+			opInputs, opOutputs := op.addresses()
+			for _, input := range opInputs {
+				inputs[input] = true
+			}
+			for _, output := range opOutputs {
+				outputs[output] = true
+			}
+		}
+		return sortedKeys(inputs), sortedKeys(outputs)
+	default:
+		product := address.MakeProductAddress(c.gtin)
+		return []string{product}, []string{product}
+	}
+}
+
+// clientAction returns the action of an operation
+func (op Op) clientAction() (MdataClientAction, error) {
+	switch op.Action {
+	case constants.VERB_CREATE, constants.VERB_UPDATE, constants.VERB_SET_STATE, constants.VERB_DELETE:
+	default:
+		return MdataClientAction{}, fmt.Errorf("Invalid action '%v' (must be create, update, set or delete)", op.Action)
+	}
+	attrs, err := op.Attributes.Strings()
+	if err != nil {
+		return MdataClientAction{}, err
+	}
+	return MdataClientAction{
+		action:           op.Action,
+		gtin:             op.Gtin,
+		attrs:            attrs,
+		state:            op.State,
+		reason:           op.Reason,
+		expectedRevision: op.ExpectedRevision,
+	}, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
-func (c *MdataClientAction) serializePayload() string {
-	//Convert map[string]string to []string{key=value key=value...} because that is what is expected by the processor payload
-	attributes := []string{}
+// operation returns the product action as one operation of a batch
+func (c *MdataClientAction) operation() (*payload_pb2.ProductOperation, error) {
+	attributes := data.Attributes{}
 	for k, v := range c.attrs {
-		attributes = append(attributes, fmt.Sprintf("%v=%v", k, v))
+		attributes[k] = v
+	}
+
+	operation := &payload_pb2.ProductOperation{}
+	switch c.action {
+	case constants.VERB_CREATE:
+		operation.Action = &payload_pb2.ProductOperation_Create{Create: &payload_pb2.CreateProductAction{
+			Gtin:       c.gtin,
+			Attributes: attributes.ToProto(),
+		}}
+	case constants.VERB_UPDATE:
+		operation.Action = &payload_pb2.ProductOperation_Update{Update: &payload_pb2.UpdateProductAction{
+			Gtin:             c.gtin,
+			Attributes:       attributes.ToProto(),
+			ExpectedRevision: c.expectedRevision,
+		}}
+	case constants.VERB_SET_STATE:
+		operation.Action = &payload_pb2.ProductOperation_Set{Set: &payload_pb2.SetProductStateAction{
+			Gtin:             c.gtin,
+			State:            c.state,
+			Reason:           c.reason,
+			ExpectedRevision: c.expectedRevision,
+		}}
+	case constants.VERB_DELETE:
+		operation.Action = &payload_pb2.ProductOperation_Delete{Delete: &payload_pb2.DeleteProductAction{
+			Gtin:             c.gtin,
+			ExpectedRevision: c.expectedRevision,
+		}}
+	default:
+		return nil, fmt.Errorf("Unknown batch operation: %v", c.action)
+	}
+	return operation, nil
+}
+
+func (c *MdataClientAction) serializePayload() ([]byte, error) {
+	attributes := data.Attributes{}
+	for k, v := range c.attrs {
+		attributes[k] = v
+	}
+
+	payload := &payload_pb2.MdataPayload{}
+	switch c.action {
+	case constants.VERB_CREATE:
+		payload.Action = &payload_pb2.MdataPayload_Create{Create: &payload_pb2.CreateProductAction{
+			Gtin:       c.gtin,
+			Attributes: attributes.ToProto(),
+		}}
+	case constants.VERB_UPDATE:
+		payload.Action = &payload_pb2.MdataPayload_Update{Update: &payload_pb2.UpdateProductAction{
+			Gtin:             c.gtin,
+			Attributes:       attributes.ToProto(),
+			ExpectedRevision: c.expectedRevision,
+		}}
+	case constants.VERB_PATCH:
+		payload.Action = &payload_pb2.MdataPayload_Patch{Patch: &payload_pb2.PatchProductAction{
+			Gtin:             c.gtin,
+			Attributes:       attributes.ToProto(),
+			UnsetKeys:        c.unset,
+			ExpectedRevision: c.expectedRevision,
+		}}
+	case constants.VERB_SET_STATE:
+		payload.Action = &payload_pb2.MdataPayload_Set{Set: &payload_pb2.SetProductStateAction{
+			Gtin:             c.gtin,
+			State:            c.state,
+			Reason:           c.reason,
+			ExpectedRevision: c.expectedRevision,
+		}}
+	case constants.VERB_DELETE:
+		payload.Action = &payload_pb2.MdataPayload_Delete{Delete: &payload_pb2.DeleteProductAction{
+			Gtin:             c.gtin,
+			ExpectedRevision: c.expectedRevision,
+		}}
+	case constants.VERB_TRANSFER:
+		payload.Action = &payload_pb2.MdataPayload_Transfer{Transfer: &payload_pb2.TransferProductAction{
+			Gtin:             c.gtin,
+			NewOwner:         c.newOwner,
+			ExpectedRevision: c.expectedRevision,
+		}}
+	case constants.VERB_ORG_CREATE:
+		payload.Action = &payload_pb2.MdataPayload_CreateOrganization{CreateOrganization: &payload_pb2.CreateOrganizationAction{
+			Id:              c.orgId,
+			Name:            c.orgName,
+			CompanyPrefixes: c.prefixes,
+		}}
+	case constants.VERB_ORG_UPDATE:
+		payload.Action = &payload_pb2.MdataPayload_UpdateOrganization{UpdateOrganization: &payload_pb2.UpdateOrganizationAction{
+			Id:              c.orgId,
+			Name:            c.orgName,
+			CompanyPrefixes: c.prefixes,
+		}}
+	case constants.VERB_ORG_ADD_KEY:
+		payload.Action = &payload_pb2.MdataPayload_AddOrganizationKey{AddOrganizationKey: &payload_pb2.AddOrganizationKeyAction{
+			Id:        c.orgId,
+			PublicKey: c.publicKey,
+		}}
+	case constants.VERB_ORG_REMOVE_KEY:
+		payload.Action = &payload_pb2.MdataPayload_RemoveOrganizationKey{RemoveOrganizationKey: &payload_pb2.RemoveOrganizationKeyAction{
+			Id:        c.orgId,
+			PublicKey: c.publicKey,
+		}}
+	case constants.VERB_AGENT_CREATE:
+		payload.Action = &payload_pb2.MdataPayload_CreateAgent{CreateAgent: &payload_pb2.CreateAgentAction{
+			PublicKey:      c.publicKey,
+			OrganizationId: c.orgId,
+			Active:         c.active,
+			Roles:          c.roles,
+		}}
+	case constants.VERB_AGENT_UPDATE:
+		payload.Action = &payload_pb2.MdataPayload_UpdateAgent{UpdateAgent: &payload_pb2.UpdateAgentAction{
+			PublicKey: c.publicKey,
+			Active:    c.active,
+			Roles:     c.roles,
+		}}
+	case constants.VERB_SCHEMA_CREATE:
+		payload.Action = &payload_pb2.MdataPayload_CreateSchema{CreateSchema: &payload_pb2.CreateSchemaAction{
+			Name:           c.schema.Name,
+			OrganizationId: c.schema.Owner,
+			Properties:     data.PropertiesToProto(c.schema.Properties),
+		}}
+	case constants.VERB_SCHEMA_UPDATE:
+		payload.Action = &payload_pb2.MdataPayload_UpdateSchema{UpdateSchema: &payload_pb2.UpdateSchemaAction{
+			Name:       c.schema.Name,
+			Properties: data.PropertiesToProto(c.schema.Properties),
+		}}
+	case constants.VERB_BATCH:
+		batch := &payload_pb2.BatchProductAction{}
+		for _, op := range c.ops {
+			operation, err := op.operation()
+			if err != nil {
+				return nil, err
+			}
+			batch.Operations = append(batch.Operations, operation)
+		}
+		payload.Action = &payload_pb2.MdataPayload_Batch{Batch: batch}
+	default:
+		return nil, fmt.Errorf("Unknown action: %v", c.action)
 	}
 
-	return fmt.Sprintf("%v,%v,%v,%v",
-		c.action,
-		c.gtin,
-		strings.Join(attributes, ","),
-		c.state)
+	return proto.Marshal(payload)
 }
 
 func NewMdataClient(url string, keyfile string) (MdataClient, error) {
@@ -117,12 +425,20 @@ func NewMdataClient(url string, keyfile string) (MdataClient, error) {
 	}
 	cryptoFactory := signing.NewCryptoFactory(signing.NewSecp256k1Context())
 	signer := cryptoFactory.NewSigner(privateKey)
-	return MdataClient{url, signer}, nil
+	return MdataClient{url: url, signer: signer}, nil
+}
+
+// PublicKey returns the public key transactions are signed with, as hex
+func (mdataClient MdataClient) PublicKey() string {
+	return mdataClient.signer.GetPublicKey().AsHex()
 }
 
 func (mdataClient MdataClient) Create(
-	// Requires gtin, sets state to ACTIVE, attributes are optional
-	gtin string, attrs map[string]string, wait uint) (string, error) {
+	// Requires gtin, sets the initial state of the lifecycle, attributes are optional
+	gtin string, attrs map[string]string, wait uint) (*BatchResult, error) {
+	if err := mdataClient.checkAttributes(attrs); err != nil {
+		return nil, err
+	}
 	c := MdataClientAction{}
 	c.action = constants.VERB_CREATE
 	c.gtin = gtin
@@ -137,141 +453,434 @@ func (mdataClient MdataClient) Create(
 }
 
 func (mdataClient MdataClient) Update(
-	// Requires gtin and attributes
-	gtin string, attrs map[string]string, wait uint) (string, error) {
+	// Requires gtin and attributes, expectedRevision 0 applies to any revision
+	gtin string, attrs map[string]string, expectedRevision uint64, wait uint) (*BatchResult, error) {
+	if err := mdataClient.checkAttributes(attrs); err != nil {
+		return nil, err
+	}
 	c := MdataClientAction{}
 	c.action = constants.VERB_UPDATE
 	c.gtin = gtin
 	c.wait = wait
 	c.attrs = attrs
 	c.state = ""
+	c.expectedRevision = expectedRevision
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) Patch(
+	// Requires gtin and attributes to set or keys to unset, other attributes are kept
+	gtin string, attrs map[string]string, unset []string, expectedRevision uint64, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_PATCH
+	c.gtin = gtin
+	c.wait = wait
+	c.attrs = attrs
+	c.unset = unset
+	c.expectedRevision = expectedRevision
 	return mdataClient.sendTransaction(c, wait)
 }
 
 func (mdataClient MdataClient) Delete(
 	// Requires gtin
-	gtin string, wait uint) (string, error) {
+	gtin string, expectedRevision uint64, wait uint) (*BatchResult, error) {
 	c := MdataClientAction{}
 	c.action = constants.VERB_DELETE
 	c.gtin = gtin
 	c.wait = wait
 	c.attrs = make(map[string]string)
 	c.state = ""
+	c.expectedRevision = expectedRevision
 	return mdataClient.sendTransaction(c, wait)
 }
 
 func (mdataClient MdataClient) Set(
-	// Requires gtin and state to change to
-	gtin string, state string, wait uint) (string, error) {
+	// Requires gtin and state to change to, the reason code is optional
+	gtin string, state string, reason string, expectedRevision uint64, wait uint) (*BatchResult, error) {
 	c := MdataClientAction{}
 	c.action = constants.VERB_SET_STATE
 	c.gtin = gtin
 	c.wait = wait
 	c.attrs = make(map[string]string)
 	c.state = state
+	c.reason = reason
+	c.expectedRevision = expectedRevision
 	return mdataClient.sendTransaction(c, wait)
 }
 
-func (mdataClient MdataClient) List() ([]byte, error) {
+func (mdataClient MdataClient) Transfer(
+	// Requires gtin and the public key of the new owner
+	gtin string, newOwner string, expectedRevision uint64, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_TRANSFER
+	c.gtin = gtin
+	c.wait = wait
+	c.attrs = make(map[string]string)
+	c.newOwner = newOwner
+	c.expectedRevision = expectedRevision
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) Apply(
+	// Requires at least one operation, applied in order, all of them or none
+	ops []Op, wait uint) (*BatchResult, error) {
+	if len(ops) < 1 {
+		return nil, errors.New("At least one operation is required")
+	}
+	attrs := []map[string]string{}
+	c := MdataClientAction{}
+	c.action = constants.VERB_BATCH
+	c.wait = wait
+	for i, op := range ops {
+		action, err := op.clientAction()
+		if err != nil {
+			return nil, fmt.Errorf("Operation %v: %v", i+1, err)
+		}
+		if op.Action == constants.VERB_CREATE || op.Action == constants.VERB_UPDATE {
+			attrs = append(attrs, action.attrs)
+		}
+		c.ops = append(c.ops, action)
+	}
+	if err := mdataClient.checkAttributes(attrs...); err != nil {
+		return nil, err
+	}
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) CreateOrganization(
+	// Requires an id and name, the signer becomes the organization's first admin
+	id string, name string, prefixes []string, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_ORG_CREATE
+	c.orgId = id
+	c.orgName = name
+	c.prefixes = prefixes
+	c.wait = wait
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) UpdateOrganization(
+	// Requires an id and name, replaces the organization's company prefixes
+	id string, name string, prefixes []string, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_ORG_UPDATE
+	c.orgId = id
+	c.orgName = name
+	c.prefixes = prefixes
+	c.wait = wait
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) AddOrganizationKey(
+	// Requires an id and the public key of the new admin
+	id string, publicKey string, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_ORG_ADD_KEY
+	c.orgId = id
+	c.publicKey = publicKey
+	c.wait = wait
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) RemoveOrganizationKey(
+	// Requires an id and the public key of the admin to remove
+	id string, publicKey string, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_ORG_REMOVE_KEY
+	c.orgId = id
+	c.publicKey = publicKey
+	c.wait = wait
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) CreateAgent(
+	// Requires the agent's public key and organization id, roles are optional
+	publicKey string, orgId string, active bool, roles []string, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_AGENT_CREATE
+	c.publicKey = publicKey
+	c.orgId = orgId
+	c.active = active
+	c.roles = roles
+	c.wait = wait
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) UpdateAgent(
+	// Requires the agent's public key, replaces its active flag and roles
+	publicKey string, active bool, roles []string, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_AGENT_UPDATE
+	c.publicKey = publicKey
+	c.active = active
+	c.roles = roles
+	c.wait = wait
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) CreateSchema(
+	// Requires the schema's name, owning organization and properties
+	schema *data.Schema, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_SCHEMA_CREATE
+	c.schema = schema
+	c.wait = wait
+	return mdataClient.sendTransaction(c, wait)
+}
+
+func (mdataClient MdataClient) UpdateSchema(
+	// Requires the schema's name, replaces its properties
+	schema *data.Schema, wait uint) (*BatchResult, error) {
+	c := MdataClientAction{}
+	c.action = constants.VERB_SCHEMA_UPDATE
+	c.schema = schema
+	c.wait = wait
+	return mdataClient.sendTransaction(c, wait)
+}
+
+// checkAttributes reports schema violations before products are submitted, so
+// they are shown without waiting for the transaction to be rejected
+func (mdataClient MdataClient) checkAttributes(attrs ...map[string]string) error {
+	schema, err := mdataClient.GetSchema(data.ProductSchemaName)
+	if err != nil || schema == nil {
+		return err
+	}
+	for _, productAttrs := range attrs {
+		if err := checkSchema(schema, productAttrs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkSchema(schema *data.Schema, attrs map[string]string) error {
+	attributes := data.Attributes{}
+	for k, v := range attrs {
+		attributes[k] = v
+	}
+	_, err := schema.Validate(attributes)
+	return err
+}
 
-	// API to call
-	apiSuffix := fmt.Sprintf("%s?address=%s",
-		constants.STATE_API, mdataClient.getPrefix())
-	response, err := mdataClient.sendRequest(apiSuffix, []byte{}, "", "")
+// GetSchema returns the named schema, or nil if it does not exist
+func (mdataClient MdataClient) GetSchema(name string) (*data.Schema, error) {
+	schemas, err := mdataClient.ShowSchema(name)
+	if _, ok := err.(*ErrNotFound); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	schemaMap, err := data.DeserializeSchemas([]byte(schemas))
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
+	return schemaMap[name], nil
+}
 
-	var toReturn bytes2.Buffer
+// List returns every product, sorted by GTIN
+func (mdataClient MdataClient) List() ([]*data.Product, error) {
+	products := []*data.Product{}
+	it := mdataClient.Products(ListOptions{})
+	for it.Next() {
+		products = append(products, it.Product())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
 
-	responseMap := make(map[interface{}]interface{})
-	err = yaml.Unmarshal([]byte(response), &responseMap)
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].Gtin < products[j].Gtin
+	})
+	return products, nil
+}
+
+func (mdataClient MdataClient) ListOrganizations() ([]byte, error) {
+	entries, err := mdataClient.listState(address.OrganizationSpace)
 	if err != nil {
-		return nil,
-			fmt.Errorf("Error reading response: %v", err)
+		return nil, err
 	}
-	encodedEntries := responseMap["data"].([]interface{})
 
-	for index, entry := range encodedEntries {
-		entryData, ok := entry.(map[interface{}]interface{})
-		if !ok {
-			return nil,
-				errors.New("Error reading entry data")
+	organizations := []*data.Organization{}
+	for entryAddress, entryData := range entries {
+		// Product addresses can share the organization prefix
+		entryOrganizations, err := data.DeserializeOrganizations(entryData)
+		if err != nil {
+			continue
 		}
-
-		stringData, ok := entryData["data"].(string)
-		if !ok {
-			return nil,
-				errors.New("Error reading string data")
+		for _, organization := range entryOrganizations {
+			if address.MakeOrganizationAddress(organization.Id) == entryAddress {
+				organizations = append(organizations, organization)
+			}
 		}
+	}
+
+	return data.SerializeOrganizations(organizations), nil
+}
+
+func (mdataClient MdataClient) ListAgents() ([]byte, error) {
+	entries, err := mdataClient.listState(address.AgentSpace)
+	if err != nil {
+		return nil, err
+	}
 
-		decodedBytes, err := base64.StdEncoding.DecodeString(stringData)
+	agents := []*data.Agent{}
+	for entryAddress, entryData := range entries {
+		// Product addresses can share the agent prefix
+		entryAgents, err := data.DeserializeAgents(entryData)
 		if err != nil {
-			return nil,
-				fmt.Errorf("Error decoding: %v", err)
+			continue
+		}
+		for _, agent := range entryAgents {
+			if address.MakeAgentAddress(agent.PublicKey) == entryAddress {
+				agents = append(agents, agent)
+			}
 		}
+	}
+
+	return data.SerializeAgents(agents), nil
+}
 
-		toReturn.Write(decodedBytes)
-		if index < len(encodedEntries)-1 {
-			toReturn.WriteString("|")
+func (mdataClient MdataClient) ListSchemas() ([]byte, error) {
+	entries, err := mdataClient.listState(address.SchemaSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := []*data.Schema{}
+	for entryAddress, entryData := range entries {
+		// Product addresses can share the schema prefix
+		entrySchemas, err := data.DeserializeSchemas(entryData)
+		if err != nil {
+			continue
+		}
+		for _, schema := range entrySchemas {
+			if address.MakeSchemaAddress(schema.Name) == entryAddress {
+				schemas = append(schemas, schema)
+			}
 		}
 	}
 
-	return toReturn.Bytes(), nil
+	return data.SerializeSchemas(schemas), nil
 }
 
-func (mdataClient MdataClient) Show(gtin string) (string, error) {
+// isProductAddress reports whether an address can only hold products
+func isProductAddress(entryAddress string) bool {
+	return !strings.HasPrefix(entryAddress, address.OrganizationSpace) &&
+		!strings.HasPrefix(entryAddress, address.CompanyPrefixSpace) &&
+		!strings.HasPrefix(entryAddress, address.AgentSpace) &&
+		!strings.HasPrefix(entryAddress, address.SchemaSpace) &&
+		!strings.HasPrefix(entryAddress, address.HistorySpace)
+}
 
-	apiSuffix := fmt.Sprintf("%s/%s", constants.STATE_API, mdataClient.getAddress(gtin))
-	response, err := mdataClient.sendRequest(apiSuffix, []byte{}, "", gtin)
+// listState returns the decoded data of every address under prefix, reading
+// every page of state
+func (mdataClient MdataClient) listState(prefix string) (map[string][]byte, error) {
+	entries := make(map[string][]byte)
+	query := url.Values{"address": {prefix}}.Encode()
+	for query != "" {
+		page, next, err := mdataClient.statePage(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range page {
+			entries[entry.address] = entry.data
+		}
+		query = next
+	}
+	return entries, nil
+}
+
+// Show returns the product of a GTIN, or ErrNotFound
+func (mdataClient MdataClient) Show(gtin string) (*data.Product, error) {
+	gtin, err := gs1.NormalizeGtin(gtin)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	responseMap := make(map[interface{}]interface{})
-	err = yaml.Unmarshal([]byte(response), &responseMap)
+
+	resource := fmt.Sprintf("product: %s", gtin)
+	products, err := mdataClient.getState(address.MakeProductAddress(gtin), resource)
 	if err != nil {
-		return "", fmt.Errorf("Error reading response: %v", err)
+		return nil, err
 	}
-	data, ok := responseMap["data"].(string)
+	productMap, err := data.Deserialize([]byte(products))
+	if err != nil {
+		return nil, err
+	}
+	// The address can hold other products whose address collides
+	product, ok := productMap[gtin]
 	if !ok {
-		return "", errors.New("Error reading as string")
+		return nil, &ErrNotFound{resource}
 	}
-	responseData, err := base64.StdEncoding.DecodeString(data)
+	return product, nil
+}
+
+// History returns the revisions of a GTIN, oldest first, including those of a
+// deleted product
+func (mdataClient MdataClient) History(gtin string) ([]*data.ProductRevision, error) {
+	gtin, err := gs1.NormalizeGtin(gtin)
 	if err != nil {
-		return "", fmt.Errorf("Error decoding response: %v", err)
+		return nil, err
 	}
 
-	strData := string(responseData)
+	histories, err := mdataClient.getState(address.MakeHistoryAddress(gtin), fmt.Sprintf("history: %s", gtin))
+	if err != nil {
+		return nil, err
+	}
+	historyMap, err := data.DeserializeHistories([]byte(histories))
+	if err != nil {
+		return nil, err
+	}
+	revisions, ok := historyMap[gtin]
+	if !ok {
+		return nil, &ErrNotFound{fmt.Sprintf("history: %s", gtin)}
+	}
+	return revisions, nil
+}
 
-	return fmt.Sprintf("%v", strData), nil
+func (mdataClient MdataClient) ShowOrganization(id string) (string, error) {
+	return mdataClient.getState(address.MakeOrganizationAddress(id), fmt.Sprintf("organization: %s", id))
 }
 
-func (mdataClient MdataClient) getStatus(
-	batchId string, wait uint) (string, error) {
+func (mdataClient MdataClient) ShowSchema(name string) (string, error) {
+	return mdataClient.getState(address.MakeSchemaAddress(name), fmt.Sprintf("schema: %s", name))
+}
 
-	// API to call
-	apiSuffix := fmt.Sprintf("%s?id=%s&wait=%d",
-		constants.BATCH_STATUS_API, batchId, wait)
-	response, err := mdataClient.sendRequest(apiSuffix, []byte{}, "", "")
+// getState returns the decoded data at an address, resource names what is
+// stored there for the not found error
+func (mdataClient MdataClient) getState(stateAddress string, resource string) (string, error) {
+	apiSuffix := fmt.Sprintf("%s/%s", constants.STATE_API, stateAddress)
+	if mdataClient.head != "" {
+		apiSuffix += "?" + url.Values{"head": {mdataClient.head}}.Encode()
+	}
+	response, err := mdataClient.sendRequest(apiSuffix, []byte{}, "", resource)
 	if err != nil {
 		return "", err
 	}
-
 	responseMap := make(map[interface{}]interface{})
 	err = yaml.Unmarshal([]byte(response), &responseMap)
 	if err != nil {
 		return "", fmt.Errorf("Error reading response: %v", err)
 	}
-	entry :=
-		responseMap["data"].([]interface{})[0].(map[interface{}]interface{})
-	return fmt.Sprint(entry["status"]), nil
+	data, ok := responseMap["data"].(string)
+	if !ok {
+		return "", errors.New("Error reading as string")
+	}
+	responseData, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("Error decoding response: %v", err)
+	}
+
+	strData := string(responseData)
+
+	return fmt.Sprintf("%v", strData), nil
 }
 
 func (mdataClient MdataClient) sendRequest(
 	apiSuffix string,
 	data []byte,
 	contentType string,
-	gtin string) (string, error) {
+	resource string) (string, error) {
 
 	// Construct URL
 	var url string
@@ -290,15 +899,19 @@ func (mdataClient MdataClient) sendRequest(
 		response, err = http.Get(url)
 	}
 	if err != nil {
-		return "", fmt.Errorf("Failed to connect to REST API: %v", err)
+		return "", &ErrUnavailable{fmt.Sprintf("Failed to connect to REST API: %v", err)}
 	}
+	defer response.Body.Close()
 	if response.StatusCode == 404 {
 		logger.Debug(fmt.Sprintf("%v", response))
-		return "", fmt.Errorf("No such product: %s", gtin)
+		return "", &ErrNotFound{resource}
+	} else if response.StatusCode == 429 {
+		return "", &ErrBusy{fmt.Sprintf("Error %d: %s", response.StatusCode, responseError(response).Message)}
+	} else if response.StatusCode >= 500 {
+		return "", &ErrUnavailable{fmt.Sprintf("Error %d: %s", response.StatusCode, responseError(response).Message)}
 	} else if response.StatusCode >= 400 {
-		return "", fmt.Errorf("Error %d: %s", response.StatusCode, response.Status)
+		return "", &ErrRejected{response.StatusCode, responseError(response)}
 	}
-	defer response.Body.Close()
 	reponseBody, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		return "", fmt.Errorf("Error reading response: %v", err)
@@ -306,11 +919,43 @@ func (mdataClient MdataClient) sendRequest(
 	return string(reponseBody), nil
 }
 
-func (mdataClient MdataClient) sendTransaction(c MdataClientAction, wait uint) (string, error) {
-	payload := c.serializePayload()
-	gtin := c.gtin
-	// construct the address
-	address := mdataClient.getAddress(gtin)
+// normalize pads the GTINs of a product action to GTIN-14, as products are
+// keyed by their GTIN-14, and returns the resource named when it is not found
+func (c *MdataClientAction) normalize() (string, error) {
+	switch {
+	case c.isProductAction():
+		gtin, err := gs1.NormalizeGtin(c.gtin)
+		if err != nil {
+			return "", err
+		}
+		c.gtin = gtin
+		return fmt.Sprintf("product: %s", gtin), nil
+	case c.action == constants.VERB_BATCH:
+		for i := range c.ops {
+			gtin, err := gs1.NormalizeGtin(c.ops[i].gtin)
+			if err != nil {
+				return "", fmt.Errorf("Operation %v: %v", i+1, err)
+			}
+			c.ops[i].gtin = gtin
+		}
+		return "products", nil
+	case c.action == constants.VERB_AGENT_CREATE || c.action == constants.VERB_AGENT_UPDATE:
+		return fmt.Sprintf("agent: %s", c.publicKey), nil
+	case c.action == constants.VERB_SCHEMA_CREATE || c.action == constants.VERB_SCHEMA_UPDATE:
+		return fmt.Sprintf("schema: %s", c.schema.Name), nil
+	default:
+		return fmt.Sprintf("organization: %s", c.orgId), nil
+	}
+}
+
+// newTransaction builds and signs the transaction of a normalized action
+func (mdataClient MdataClient) newTransaction(c MdataClientAction) (*transaction_pb2.Transaction, error) {
+	payload, err := c.serializePayload()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to serialize payload: %v", err)
+	}
+	// construct the addresses
+	inputs, outputs := c.addresses()
 
 	// Construct TransactionHeader
 	rawTransactionHeader := transaction_pb2.TransactionHeader{
@@ -320,13 +965,13 @@ func (mdataClient MdataClient) sendTransaction(c MdataClientAction, wait uint) (
 		Dependencies:     []string{}, // empty dependency list
 		Nonce:            strconv.Itoa(rand.Int()),
 		BatcherPublicKey: mdataClient.signer.GetPublicKey().AsHex(),
-		Inputs:           []string{address},
-		Outputs:          []string{address},
-		PayloadSha512:    Sha512HashValue(payload),
+		Inputs:           inputs,
+		Outputs:          outputs,
+		PayloadSha512:    Sha512HashValue(string(payload)),
 	}
 	transactionHeader, err := proto.Marshal(&rawTransactionHeader)
 	if err != nil {
-		return "", fmt.Errorf("Unable to serialize transaction header: %v", err)
+		return nil, fmt.Errorf("Unable to serialize transaction header: %v", err)
 	}
 
 	// Signature of TransactionHeader
@@ -334,61 +979,70 @@ func (mdataClient MdataClient) sendTransaction(c MdataClientAction, wait uint) (
 		mdataClient.signer.Sign(transactionHeader))
 
 	// Construct Transaction
-	transaction := transaction_pb2.Transaction{
+	return &transaction_pb2.Transaction{
 		Header:          transactionHeader,
 		HeaderSignature: transactionHeaderSignature,
-		Payload:         []byte(payload),
+		Payload:         payload,
+	}, nil
+}
+
+func (mdataClient MdataClient) sendTransaction(c MdataClientAction, wait uint) (*BatchResult, error) {
+	resource, err := c.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := mdataClient.newTransaction(c)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get BatchList
 	rawBatchList, err := mdataClient.createBatchList(
-		[]*transaction_pb2.Transaction{&transaction})
+		[]*transaction_pb2.Transaction{transaction})
 	if err != nil {
-		return "", fmt.Errorf("Unable to construct batch list: %v", err)
+		return nil, fmt.Errorf("Unable to construct batch list: %v", err)
 	}
 	batchId := rawBatchList.Batches[0].HeaderSignature
 	batchList, err := proto.Marshal(&rawBatchList)
 	if err != nil {
-		return "", fmt.Errorf("Unable to serialize batch list: %v", err)
+		return nil, fmt.Errorf("Unable to serialize batch list: %v", err)
+	}
+
+	if _, err := mdataClient.sendRequest(
+		constants.BATCH_SUBMIT_API, batchList, constants.CONTENT_TYPE_OCTET_STREAM, resource); err != nil {
+		return nil, err
 	}
 
+	submitted := &BatchResult{BatchId: batchId, Status: StatusPending}
+	mdataClient.logSubmission(c, submitted)
 	if wait > 0 {
-		waitTime := uint(0)
-		startTime := time.Now()
-		response, err := mdataClient.sendRequest(
-			constants.BATCH_SUBMIT_API, batchList, constants.CONTENT_TYPE_OCTET_STREAM, gtin)
-		if err != nil {
-			return "", err
+		result, err := mdataClient.waitForBatch(batchId, wait)
+		if result.Status != StatusPending {
+			mdataClient.logSubmission(c, result)
 		}
-		for waitTime < wait {
-			status, err := mdataClient.getStatus(batchId, wait-waitTime)
-			if err != nil {
-				return "", err
-			}
-			waitTime = uint(time.Since(startTime))
-			if status != "PENDING" {
-				return response, nil
-			}
-		}
-		return response, nil
+		return result, err
 	}
-
-	return mdataClient.sendRequest(
-		constants.BATCH_SUBMIT_API, batchList, constants.CONTENT_TYPE_OCTET_STREAM, gtin)
+	return submitted, nil
 }
 
-func (mdataClient MdataClient) getPrefix() string {
-	return Sha512HashValue(constants.FAMILY_NAME)[:constants.FAMILY_NAMESPACE_ADDRESS_LENGTH]
-}
+func (mdataClient MdataClient) createBatchList(
+	transactions []*transaction_pb2.Transaction) (batch_pb2.BatchList, error) {
+	batch, err := mdataClient.createBatch(transactions)
+	if err != nil {
+		return batch_pb2.BatchList{}, err
+	}
 
-func (mdataClient MdataClient) getAddress(gtin string) string {
-	prefix := mdataClient.getPrefix()
-	productAddress := Sha512HashValue(gtin)[:constants.FAMILY_VERB_ADDRESS_LENGTH]
-	return prefix + productAddress
+	// Construct BatchList
+	return batch_pb2.BatchList{
+		Batches: []*batch_pb2.Batch{batch},
+	}, nil
 }
 
-func (mdataClient MdataClient) createBatchList(
-	transactions []*transaction_pb2.Transaction) (batch_pb2.BatchList, error) {
+// createBatch signs a batch of transactions, which the validator commits
+// together or not at all
+func (mdataClient MdataClient) createBatch(
+	transactions []*transaction_pb2.Transaction) (*batch_pb2.Batch, error) {
 
 	// Get list of TransactionHeader signatures
 	transactionSignatures := []string{}
@@ -404,7 +1058,7 @@ func (mdataClient MdataClient) createBatchList(
 	}
 	batchHeader, err := proto.Marshal(&rawBatchHeader)
 	if err != nil {
-		return batch_pb2.BatchList{}, fmt.Errorf("Unable to serialize batch header: %v", err)
+		return nil, fmt.Errorf("Unable to serialize batch header: %v", err)
 	}
 
 	// Signature of BatchHeader
@@ -412,15 +1066,10 @@ func (mdataClient MdataClient) createBatchList(
 		mdataClient.signer.Sign(batchHeader))
 
 	// Construct Batch
-	batch := batch_pb2.Batch{
+	return &batch_pb2.Batch{
 		Header:          batchHeader,
 		Transactions:    transactions,
 		HeaderSignature: batchHeaderSignature,
-	}
-
-	// Construct BatchList
-	return batch_pb2.BatchList{
-		Batches: []*batch_pb2.Batch{&batch},
 	}, nil
 }
 
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+func TestShow(t *testing.T) {
+	product := &data.Product{
+		Gtin:       "00012345600012",
+		Attributes: data.Attributes{"uom": "cases"},
+		State:      "ACTIVE",
+	}
+	other := &data.Product{Gtin: "00012345600029", State: "ACTIVE"}
+	state := map[string][]byte{
+		address.MakeProductAddress(product.Gtin): data.Serialize([]*data.Product{product}),
+		address.MakeProductAddress(other.Gtin):   data.Serialize([]*data.Product{product}),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry, ok := state[r.URL.Path[len("/state/"):]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"data": "%s"}`, base64.StdEncoding.EncodeToString(entry))
+	}))
+	defer server.Close()
+	mdataClient := MdataClient{url: server.URL}
+
+	tests := map[string]struct {
+		inGtin     string
+		outProduct *data.Product
+		outErr     string
+	}{
+		"found": {
+			inGtin:     "012345600012",
+			outProduct: product,
+		},
+		"missing": {
+			inGtin: "00012345600036",
+			outErr: "No such product: 00012345600036",
+		},
+		"addressCollision": {
+			inGtin: other.Gtin,
+			outErr: "No such product: 00012345600029",
+		},
+		"invalidGtin": {
+			inGtin: "abc",
+			outErr: "Invalid GTIN 'abc': must contain only digits",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		product, err := mdataClient.Show(test.inGtin)
+		if test.outErr != "" {
+			assert.EqualError(t, err, test.outErr)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.outProduct, product)
+	}
+}
+
+func TestBatchStatusErr(t *testing.T) {
+	tests := map[string]struct {
+		inStatus batchStatus
+		outErr   error
+	}{
+		"committed": {
+			inStatus: batchStatus{Id: "b1", Status: StatusCommitted},
+		},
+		"pending": {
+			inStatus: batchStatus{Id: "b1", Status: StatusPending},
+		},
+		"unknown": {
+			inStatus: batchStatus{Id: "b1", Status: StatusUnknown},
+			outErr:   &ErrUnknownBatch{"b1"},
+		},
+		"invalid": {
+			inStatus: batchStatus{
+				Id:                  "b1",
+				Status:              StatusInvalid,
+				InvalidTransactions: []invalidTransaction{{Id: "t1", Message: "Product already exists"}},
+			},
+			outErr: &ErrInvalidTransaction{BatchId: "b1", TransactionId: "t1", Message: "Product already exists"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, test.outErr, test.inStatus.err())
+	}
+}
+
+func TestSendRequestErr(t *testing.T) {
+	sawtoothError := `{"error": {"code": 50, "title": "Invalid Resource Id", "message": "Head id is not a valid block id"}}`
+
+	tests := map[string]struct {
+		inStatus int
+		inBody   string
+		outErr   error
+	}{
+		"notFound": {
+			inStatus: http.StatusNotFound,
+			outErr:   &ErrNotFound{"product: 00012345600012"},
+		},
+		"queueFull": {
+			inStatus: http.StatusTooManyRequests,
+			inBody:   `{"error": {"code": 31, "title": "Unable to Accept Batches", "message": "The validator cannot currently accept more batches"}}`,
+			outErr:   &ErrBusy{"Error 429: The validator cannot currently accept more batches"},
+		},
+		"badRequest": {
+			inStatus: http.StatusBadRequest,
+			inBody:   sawtoothError,
+			outErr:   &ErrRejected{http.StatusBadRequest, SawtoothError{50, "Invalid Resource Id", "Head id is not a valid block id"}},
+		},
+		"badRequestWithoutBody": {
+			inStatus: http.StatusRequestEntityTooLarge,
+			inBody:   "<html>Too large</html>",
+			outErr:   &ErrRejected{http.StatusRequestEntityTooLarge, SawtoothError{0, "413 Request Entity Too Large", "413 Request Entity Too Large"}},
+		},
+		"validatorNotReady": {
+			inStatus: http.StatusServiceUnavailable,
+			inBody:   `{"error": {"code": 15, "title": "Validator Not Ready", "message": "The validator has no genesis block"}}`,
+			outErr:   &ErrUnavailable{"Error 503: The validator has no genesis block"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.inStatus)
+			fmt.Fprint(w, test.inBody)
+		}))
+		mdataClient := MdataClient{url: server.URL}
+		_, err := mdataClient.sendRequest("state", []byte{}, "", "product: 00012345600012")
+		server.Close()
+		assert.Equal(t, test.outErr, err)
+	}
+}
@@ -0,0 +1,162 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tross-tyson/mdata_go/src/mdata_client/constants"
+)
+
+// BatchResult is the outcome of a submitted batch. Status is PENDING when the
+// client did not wait, or the wait ran out before the batch was committed.
+// TransactionId and Message name the invalid transaction of an INVALID batch,
+// and the reason the validator gave.
+type BatchResult struct {
+	BatchId       string `json:"batch_id"`
+	Status        string `json:"status"`
+	TransactionId string `json:"transaction_id,omitempty"`
+	Message       string `json:"message,omitempty"`
+}
+
+func (r *BatchResult) GetJson() []byte {
+	b, err := json.Marshal(r)
+	if err != nil {
+		fmt.Printf("Error marshalling batch result json, %v", err)
+		return nil
+	}
+	return b
+}
+
+// waitForBatch polls the status of a batch until it is COMMITTED, INVALID or
+// UNKNOWN, or wait seconds have passed. The result is returned with the error
+// of an invalid or unknown batch, or ErrTimeout once the wait is over.
+func (mdataClient MdataClient) waitForBatch(batchId string, wait uint) (*BatchResult, error) {
+	result := &BatchResult{BatchId: batchId, Status: StatusPending}
+	deadline := time.Now().Add(time.Duration(wait) * time.Second)
+	for {
+		remaining := uint(0)
+		if left := time.Until(deadline); left > 0 {
+			remaining = uint(left.Seconds() + 0.5)
+		}
+		status, err := mdataClient.getStatus(batchId, remaining)
+		if err != nil {
+			return result, err
+		}
+
+		if status.Status != StatusPending {
+			return status.result(), status.err()
+		}
+		if remaining == 0 {
+			return result, &ErrTimeout{BatchId: batchId, Wait: wait}
+		}
+	}
+}
+
+// Status returns the results of batches, waiting up to wait seconds for all of
+// them to leave PENDING. Batches in the submission log are updated.
+func (mdataClient MdataClient) Status(batchIds []string, wait uint) ([]*BatchResult, error) {
+	if len(batchIds) < 1 {
+		return nil, errors.New("At least one batch id is required")
+	}
+	statuses, err := mdataClient.getStatuses(batchIds, wait)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*BatchResult{}
+	for _, status := range statuses {
+		results = append(results, status.result())
+	}
+	if mdataClient.submissions != nil {
+		if err := mdataClient.submissions.Update(results); err != nil {
+			logger.Warnf("Failed to update submission log: %v", err)
+		}
+	}
+	return results, nil
+}
+
+// result returns the BatchResult of a batch status
+func (b *batchStatus) result() *BatchResult {
+	result := &BatchResult{BatchId: b.Id, Status: b.Status}
+	if len(b.InvalidTransactions) > 0 {
+		result.TransactionId = b.InvalidTransactions[0].Id
+		result.Message = b.InvalidTransactions[0].Message
+	}
+	return result
+}
+
+// logSubmission records the result of a batch sent for an action in the
+// submission log, if the client keeps one. The batch is already sent, so a
+// failure to record it is only logged.
+func (mdataClient MdataClient) logSubmission(c MdataClientAction, result *BatchResult) {
+	mdataClient.logBatch(c.action, c.gtin, result)
+}
+
+// logBatch records the result of a batch sent for an action on a GTIN, which
+// is empty when the batch changes several products
+func (mdataClient MdataClient) logBatch(action string, gtin string, result *BatchResult) {
+	if mdataClient.submissions == nil {
+		return
+	}
+	err := mdataClient.submissions.Append(&Submission{
+		BatchId: result.BatchId,
+		Action:  action,
+		Gtin:    gtin,
+		Time:    time.Now().UTC(),
+		Status:  result.Status,
+		Message: result.Message,
+	})
+	if err != nil {
+		logger.Warnf("Failed to record batch %v in submission log: %v", result.BatchId, err)
+	}
+}
+
+// getStatus returns the status of a batch, waiting up to wait seconds for it
+// to leave PENDING
+func (mdataClient MdataClient) getStatus(batchId string, wait uint) (*batchStatus, error) {
+	statuses, err := mdataClient.getStatuses([]string{batchId}, wait)
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("Error reading response: no status for batch %s", batchId)
+	}
+	return &statuses[0], nil
+}
+
+// getStatuses returns the statuses of batches, waiting up to wait seconds for
+// all of them to leave PENDING
+func (mdataClient MdataClient) getStatuses(batchIds []string, wait uint) ([]batchStatus, error) {
+	apiSuffix := fmt.Sprintf("%s?id=%s&wait=%d",
+		constants.BATCH_STATUS_API, strings.Join(batchIds, ","), wait)
+	response, err := mdataClient.sendRequest(apiSuffix, []byte{}, "", "batch statuses")
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := batchStatusResponse{}
+	if err := json.Unmarshal([]byte(response), &statuses); err != nil {
+		return nil, fmt.Errorf("Error reading response: %v", err)
+	}
+	return statuses.Data, nil
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchStatuses serves /batch_statuses, answering PENDING until pending polls
+// have been made, and status after. A PENDING answer takes the requested wait.
+func batchStatuses(pending int, status batchStatus) *httptest.Server {
+	polls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := batchStatusResponse{Data: []batchStatus{status}}
+		if polls < pending {
+			wait, _ := strconv.Atoi(r.URL.Query().Get("wait"))
+			time.Sleep(time.Duration(wait) * time.Second)
+			response.Data[0] = batchStatus{Id: status.Id, Status: StatusPending}
+		}
+		polls++
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestWaitForBatch(t *testing.T) {
+	tests := map[string]struct {
+		inPending int
+		inStatus  batchStatus
+		outResult *BatchResult
+		outErr    error
+	}{
+		"committed": {
+			inStatus:  batchStatus{Id: "b1", Status: StatusCommitted},
+			outResult: &BatchResult{BatchId: "b1", Status: StatusCommitted},
+		},
+		"invalid": {
+			inStatus: batchStatus{
+				Id:                  "b1",
+				Status:              StatusInvalid,
+				InvalidTransactions: []invalidTransaction{{Id: "t1", Message: "Product already exists"}},
+			},
+			outResult: &BatchResult{BatchId: "b1", Status: StatusInvalid, TransactionId: "t1", Message: "Product already exists"},
+			outErr:    &ErrInvalidTransaction{BatchId: "b1", TransactionId: "t1", Message: "Product already exists"},
+		},
+		"unknown": {
+			inStatus:  batchStatus{Id: "b1", Status: StatusUnknown},
+			outResult: &BatchResult{BatchId: "b1", Status: StatusUnknown},
+			outErr:    &ErrUnknownBatch{"b1"},
+		},
+		"timeout": {
+			inPending: 2,
+			inStatus:  batchStatus{Id: "b1", Status: StatusCommitted},
+			outResult: &BatchResult{BatchId: "b1", Status: StatusPending},
+			outErr:    &ErrTimeout{BatchId: "b1", Wait: 1},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		server := batchStatuses(test.inPending, test.inStatus)
+		mdataClient := MdataClient{url: server.URL}
+		result, err := mdataClient.waitForBatch("b1", 1)
+		server.Close()
+		assert.Equal(t, test.outErr, err)
+		assert.Equal(t, test.outResult, result)
+	}
+}
@@ -0,0 +1,183 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"sync"
+	"time"
+)
+
+// Submission records a batch sent by the client. The log is append-only: a
+// batch is recorded as PENDING when it is sent, and again once its final
+// status is known. The last record of a batch is its current state.
+type Submission struct {
+	BatchId string    `json:"batch_id"`
+	Action  string    `json:"action"`
+	Gtin    string    `json:"gtin,omitempty"`
+	Time    time.Time `json:"time"`
+	Status  string    `json:"status"`
+	Message string    `json:"message,omitempty"`
+}
+
+// SubmissionLog is a file of submissions, one JSON object per line. It is
+// safe for concurrent use by the clients sharing it.
+type SubmissionLog struct {
+	path string
+	// mu keeps an update from reading the log while another appends to it,
+	// which would record the same status twice
+	mu sync.Mutex
+}
+
+func NewSubmissionLog(path string) *SubmissionLog {
+	return &SubmissionLog{path: path}
+}
+
+// DefaultSubmissionLog returns the log in the .sawtooth directory of the
+// current user, next to their keys
+func DefaultSubmissionLog() (*SubmissionLog, error) {
+	username, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	return NewSubmissionLog(path.Join(username.HomeDir, ".sawtooth", "mdata", "submissions.log")), nil
+}
+
+// Append adds a record to the end of the log, creating it if needed
+func (l *SubmissionLog) Append(submission *Submission) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.append(submission)
+}
+
+func (l *SubmissionLog) append(submission *Submission) error {
+	if err := os.MkdirAll(path.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("Failed to create submission log: %v", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to open submission log: %v", err)
+	}
+	defer file.Close()
+
+	line, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+	// Start a new line after one a crash left half written
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			line = append([]byte{'\n'}, line...)
+		}
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("Failed to write submission log: %v", err)
+	}
+	return nil
+}
+
+// Submissions returns the current state of every batch in the log, in the
+// order they were sent. A missing log holds no submissions.
+func (l *SubmissionLog) Submissions() ([]*Submission, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.submissions()
+}
+
+func (l *SubmissionLog) submissions() ([]*Submission, error) {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return []*Submission{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open submission log: %v", err)
+	}
+	defer file.Close()
+
+	submissions := []*Submission{}
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		submission := &Submission{}
+		if err := json.Unmarshal(scanner.Bytes(), submission); err != nil {
+			// A crash can leave the last line half written
+			logger.Warnf("Skipping line %v of submission log: %v", line, err)
+			continue
+		}
+		if i, ok := index[submission.BatchId]; ok {
+			submissions[i] = submission
+			continue
+		}
+		index[submission.BatchId] = len(submissions)
+		submissions = append(submissions, submission)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read submission log: %v", err)
+	}
+	return submissions, nil
+}
+
+// Pending returns the submissions whose last known status is PENDING
+func (l *SubmissionLog) Pending() ([]*Submission, error) {
+	submissions, err := l.Submissions()
+	if err != nil {
+		return nil, err
+	}
+	pending := []*Submission{}
+	for _, submission := range submissions {
+		if submission.Status == StatusPending {
+			pending = append(pending, submission)
+		}
+	}
+	return pending, nil
+}
+
+// Update appends the result of a logged batch when its status changed. Batches
+// missing from the log are left out.
+func (l *SubmissionLog) Update(results []*BatchResult) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	submissions, err := l.submissions()
+	if err != nil {
+		return err
+	}
+	logged := make(map[string]*Submission)
+	for _, submission := range submissions {
+		logged[submission.BatchId] = submission
+	}
+	for _, result := range results {
+		submission, ok := logged[result.BatchId]
+		if !ok || submission.Status == result.Status {
+			continue
+		}
+		update := *submission
+		update.Time = time.Now().UTC()
+		update.Status = result.Status
+		update.Message = result.Message
+		if err := l.append(&update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
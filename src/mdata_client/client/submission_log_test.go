@@ -0,0 +1,86 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmissionLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "submissions")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	log := NewSubmissionLog(path.Join(dir, "mdata", "submissions.log"))
+
+	sent := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, submission := range []*Submission{
+		{BatchId: "b1", Action: "create", Gtin: "00012345600012", Time: sent, Status: StatusPending},
+		{BatchId: "b2", Action: "set", Gtin: "00012345600012", Time: sent, Status: StatusPending},
+		{BatchId: "b3", Action: "delete", Gtin: "00012345600029", Time: sent, Status: StatusPending},
+	} {
+		assert.Nil(t, log.Append(submission))
+	}
+
+	// A line cut short by a crash is skipped
+	file, err := os.OpenFile(log.path, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.Nil(t, err)
+	file.WriteString(`{"batch_id": "b4", "act`)
+	file.Close()
+
+	assert.Nil(t, log.Update([]*BatchResult{
+		{BatchId: "b1", Status: StatusCommitted},
+		{BatchId: "b2", Status: StatusPending},
+		{BatchId: "b3", Status: StatusInvalid, TransactionId: "t3", Message: "No such product"},
+		{BatchId: "b5", Status: StatusCommitted},
+	}))
+
+	submissions, err := log.Submissions()
+	assert.Nil(t, err)
+	statuses := map[string]string{}
+	for _, submission := range submissions {
+		statuses[submission.BatchId] = submission.Status
+	}
+	assert.Equal(t, map[string]string{"b1": StatusCommitted, "b2": StatusPending, "b3": StatusInvalid}, statuses)
+	assert.Equal(t, "No such product", submissions[2].Message)
+	assert.Equal(t, "delete", submissions[2].Action)
+
+	pending, err := log.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, []*Submission{{BatchId: "b2", Action: "set", Gtin: "00012345600012", Time: sent, Status: StatusPending}}, pending)
+}
+
+// Concurrent updates with the same result record it once
+func TestConcurrentSubmissionLogUpdates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "submissions")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	log := NewSubmissionLog(path.Join(dir, "submissions.log"))
+	assert.Nil(t, log.Append(&Submission{BatchId: "b1", Action: "create", Status: StatusPending}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, log.Update([]*BatchResult{{BatchId: "b1", Status: StatusCommitted}}))
+		}()
+	}
+	wg.Wait()
+
+	b, err := ioutil.ReadFile(log.path)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, strings.Count(string(b), "\n"))
+}
+
+func TestMissingSubmissionLog(t *testing.T) {
+	log := NewSubmissionLog(path.Join(os.TempDir(), "no-such-dir", "submissions.log"))
+	submissions, err := log.Submissions()
+	assert.Nil(t, err)
+	assert.Equal(t, []*Submission{}, submissions)
+}
@@ -0,0 +1,271 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/messaging"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_event_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/events_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	zmq "github.com/pebbe/zmq4"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// DEFAULT_VALIDATOR_URL is the validator component endpoint events are read from
+const DEFAULT_VALIDATOR_URL = "tcp://127.0.0.1:4004"
+
+// blockCommitEvent is sent by the validator with the events of every block
+const blockCommitEvent = "sawtooth/block-commit"
+
+// EventConnection is the part of a validator connection Watch uses. It is met
+// by the connections of the sawtooth messaging package, and can be met by a
+// stand-in for the validator.
+type EventConnection interface {
+	SendNewMsg(t validator_pb2.Message_MessageType, c []byte) (string, error)
+	RecvMsg() (string, *validator_pb2.Message, error)
+	RecvMsgWithId(corrId string) (string, *validator_pb2.Message, error)
+	Close()
+}
+
+// DialValidator connects to the component endpoint of a validator, e.g.
+// tcp://127.0.0.1:4004
+func DialValidator(url string) (EventConnection, error) {
+	if url == "" {
+		url = DEFAULT_VALIDATOR_URL
+	}
+	context, err := zmq.NewContext()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to validator: %v", err)
+	}
+	connection, err := messaging.NewConnection(context, zmq.DEALER, url, false)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to validator: %v", err)
+	}
+	return connection, nil
+}
+
+// WatchFilter selects the product changes Watch reports. Empty fields match
+// every change.
+type WatchFilter struct {
+	GtinPrefix string
+	Actions    []string
+	States     []string
+}
+
+// ProductChange is a product change committed in a block, as reported by its
+// event
+type ProductChange struct {
+	BlockId  string        `json:"block_id"`
+	BlockNum uint64        `json:"block_num"`
+	Event    string        `json:"event"`
+	Gtin     string        `json:"gtin"`
+	Action   string        `json:"action"`
+	Signer   string        `json:"signer"`
+	State    string        `json:"state"`
+	Revision uint64        `json:"revision"`
+	Changed  []string      `json:"changed_fields"`
+	Product  *data.Product `json:"product"`
+}
+
+func (c *ProductChange) GetJson() []byte {
+	b, err := json.Marshal(c)
+	if err != nil {
+		fmt.Printf("Error marshalling product change json, %v", err)
+		return nil
+	}
+	return b
+}
+
+// filters returns the event filters of the subscription. The validator only
+// sends events passing all of them.
+func (f WatchFilter) filters() []*events_pb2.EventFilter {
+	filters := []*events_pb2.EventFilter{}
+	if f.GtinPrefix != "" {
+		filters = append(filters, &events_pb2.EventFilter{
+			Key:         data.EventAttributeGtin,
+			MatchString: "^" + regexp.QuoteMeta(f.GtinPrefix),
+			FilterType:  events_pb2.EventFilter_REGEX_ANY,
+		})
+	}
+	if len(f.Actions) > 0 {
+		filters = append(filters, &events_pb2.EventFilter{
+			Key:         data.EventAttributeAction,
+			MatchString: anyOf(f.Actions),
+			FilterType:  events_pb2.EventFilter_REGEX_ANY,
+		})
+	}
+	if len(f.States) > 0 {
+		filters = append(filters, &events_pb2.EventFilter{
+			Key:         data.EventAttributeState,
+			MatchString: anyOf(f.States),
+			FilterType:  events_pb2.EventFilter_REGEX_ANY,
+		})
+	}
+	return filters
+}
+
+func anyOf(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, regexp.QuoteMeta(value))
+	}
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
+
+// Match reports whether the filter selects a change. Watch checks it as well
+// as the validator, so a stand-in does not have to filter.
+func (f WatchFilter) Match(change *ProductChange) bool {
+	if !strings.HasPrefix(change.Gtin, f.GtinPrefix) {
+		return false
+	}
+	if len(f.Actions) > 0 && !contains(f.Actions, change.Action) {
+		return false
+	}
+	if len(f.States) > 0 && !contains(f.States, change.State) {
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Watch subscribes to the product events of the validator and calls handle
+// with every matching change as its block commits, in order. It returns when
+// handle or the connection fails. The connection is not closed.
+func Watch(connection EventConnection, filter WatchFilter, handle func(*ProductChange) error) error {
+	request := &client_event_pb2.ClientEventsSubscribeRequest{
+		Subscriptions: []*events_pb2.EventSubscription{{EventType: blockCommitEvent}},
+	}
+	for _, eventType := range data.ProductEventTypes {
+		request.Subscriptions = append(request.Subscriptions, &events_pb2.EventSubscription{
+			EventType: eventType,
+			Filters:   filter.filters(),
+		})
+	}
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("Unable to serialize subscription: %v", err)
+	}
+
+	corrId, err := connection.SendNewMsg(validator_pb2.Message_CLIENT_EVENTS_SUBSCRIBE_REQUEST, requestBytes)
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe to events: %v", err)
+	}
+	_, message, err := connection.RecvMsgWithId(corrId)
+	if err != nil {
+		return fmt.Errorf("Failed to subscribe to events: %v", err)
+	}
+	response := &client_event_pb2.ClientEventsSubscribeResponse{}
+	if err := proto.Unmarshal(message.GetContent(), response); err != nil {
+		return fmt.Errorf("Malformed subscription response: %v", err)
+	}
+	if response.GetStatus() != client_event_pb2.ClientEventsSubscribeResponse_OK {
+		return fmt.Errorf("Subscription refused: %v %v", response.GetStatus(), response.GetResponseMessage())
+	}
+
+	for {
+		_, message, err := connection.RecvMsg()
+		if err != nil {
+			return fmt.Errorf("Failed to receive events: %v", err)
+		}
+		if message.GetMessageType() != validator_pb2.Message_CLIENT_EVENTS {
+			continue
+		}
+		events := &events_pb2.EventList{}
+		if err := proto.Unmarshal(message.GetContent(), events); err != nil {
+			return fmt.Errorf("Malformed events: %v", err)
+		}
+		changes, err := DecodeChanges(events.GetEvents())
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if !filter.Match(change) {
+				continue
+			}
+			if err := handle(change); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// DecodeChanges returns the product changes of the events of a block, in
+// order, with the block taken from its block-commit event
+func DecodeChanges(events []*events_pb2.Event) ([]*ProductChange, error) {
+	var blockId string
+	var blockNum uint64
+	for _, event := range events {
+		if event.GetEventType() == blockCommitEvent {
+			for _, attribute := range event.GetAttributes() {
+				switch attribute.GetKey() {
+				case "block_id":
+					blockId = attribute.GetValue()
+				case "block_num":
+					blockNum, _ = strconv.ParseUint(attribute.GetValue(), 10, 64)
+				}
+			}
+		}
+	}
+
+	changes := []*ProductChange{}
+	for _, event := range events {
+		if !strings.HasPrefix(event.GetEventType(), data.EventPrefix) {
+			continue
+		}
+		change := &ProductChange{BlockId: blockId, BlockNum: blockNum, Event: event.GetEventType(), Changed: []string{}}
+		for _, attribute := range event.GetAttributes() {
+			switch attribute.GetKey() {
+			case data.EventAttributeGtin:
+				change.Gtin = attribute.GetValue()
+			case data.EventAttributeAction:
+				change.Action = attribute.GetValue()
+			case data.EventAttributeSigner:
+				change.Signer = attribute.GetValue()
+			case data.EventAttributeState:
+				change.State = attribute.GetValue()
+			case data.EventAttributeRevision:
+				change.Revision, _ = strconv.ParseUint(attribute.GetValue(), 10, 64)
+			case data.EventAttributeChanged:
+				change.Changed = append(change.Changed, attribute.GetValue())
+			}
+		}
+		if len(event.GetData()) > 0 {
+			change.Product = &data.Product{}
+			if err := json.Unmarshal(event.GetData(), change.Product); err != nil {
+				return nil, fmt.Errorf("Malformed %v event: %v", event.GetEventType(), err)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/client_event_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/events_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/validator_pb2"
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+	"testing"
+)
+
+var errClosed = errors.New("closed")
+
+// standIn plays the validator: it accepts the subscription and then sends its
+// event lists
+type standIn struct {
+	subscription *client_event_pb2.ClientEventsSubscribeRequest
+	blocks       [][]*events_pb2.Event
+}
+
+func (s *standIn) SendNewMsg(t validator_pb2.Message_MessageType, c []byte) (string, error) {
+	s.subscription = &client_event_pb2.ClientEventsSubscribeRequest{}
+	return "1", proto.Unmarshal(c, s.subscription)
+}
+
+func (s *standIn) RecvMsgWithId(corrId string) (string, *validator_pb2.Message, error) {
+	content, _ := proto.Marshal(&client_event_pb2.ClientEventsSubscribeResponse{Status: client_event_pb2.ClientEventsSubscribeResponse_OK})
+	return "", &validator_pb2.Message{MessageType: validator_pb2.Message_CLIENT_EVENTS_SUBSCRIBE_RESPONSE, CorrelationId: corrId, Content: content}, nil
+}
+
+func (s *standIn) RecvMsg() (string, *validator_pb2.Message, error) {
+	if len(s.blocks) == 0 {
+		return "", nil, errClosed
+	}
+	content, _ := proto.Marshal(&events_pb2.EventList{Events: s.blocks[0]})
+	s.blocks = s.blocks[1:]
+	return "", &validator_pb2.Message{MessageType: validator_pb2.Message_CLIENT_EVENTS, Content: content}, nil
+}
+
+func (s *standIn) Close() {}
+
+func productEvent(eventType string, gtin string, action string, state string, changed ...string) *events_pb2.Event {
+	attributes := []*events_pb2.Event_Attribute{
+		{Key: "gtin", Value: gtin},
+		{Key: "action", Value: action},
+		{Key: "signer", Value: "02aa"},
+		{Key: "state", Value: state},
+		{Key: "revision", Value: "2"},
+	}
+	for _, field := range changed {
+		attributes = append(attributes, &events_pb2.Event_Attribute{Key: "changed", Value: field})
+	}
+	product := &data.Product{Gtin: gtin, Attributes: data.Attributes{"uom": "lbs"}, State: state, Owner: "02aa", Revision: 2}
+	return &events_pb2.Event{EventType: eventType, Attributes: attributes, Data: product.GetJson()}
+}
+
+func TestWatch(t *testing.T) {
+	connection := &standIn{blocks: [][]*events_pb2.Event{{
+		{EventType: "sawtooth/block-commit", Attributes: []*events_pb2.Event_Attribute{{Key: "block_id", Value: "b1"}, {Key: "block_num", Value: "7"}}},
+		productEvent(data.EventProductUpdated, "00012345600012", "update", "ACTIVE", "attributes.uom"),
+		productEvent(data.EventProductStateChanged, "00012345600029", "set", "INACTIVE", "state"),
+		productEvent(data.EventProductStateChanged, "99912345600012", "set", "INACTIVE", "state"),
+	}}}
+	filter := WatchFilter{GtinPrefix: "000123", States: []string{"INACTIVE"}}
+
+	changes := []*ProductChange{}
+	err := Watch(connection, filter, func(change *ProductChange) error {
+		changes = append(changes, change)
+		return nil
+	})
+	assert.EqualError(t, err, "Failed to receive events: closed")
+
+	// The validator is asked for block commits and every product event type
+	subscriptions := connection.subscription.GetSubscriptions()
+	assert.Equal(t, 1+len(data.ProductEventTypes), len(subscriptions))
+	assert.Equal(t, "sawtooth/block-commit", subscriptions[0].GetEventType())
+	assert.Equal(t, []*events_pb2.EventFilter{
+		{Key: "gtin", MatchString: "^000123", FilterType: events_pb2.EventFilter_REGEX_ANY},
+		{Key: "state", MatchString: "^(INACTIVE)$", FilterType: events_pb2.EventFilter_REGEX_ANY},
+	}, subscriptions[1].GetFilters())
+
+	assert.Equal(t, []*ProductChange{{
+		BlockId:  "b1",
+		BlockNum: 7,
+		Event:    data.EventProductStateChanged,
+		Gtin:     "00012345600029",
+		Action:   "set",
+		Signer:   "02aa",
+		State:    "INACTIVE",
+		Revision: 2,
+		Changed:  []string{"state"},
+		Product:  &data.Product{Gtin: "00012345600029", Attributes: data.Attributes{"uom": "lbs"}, State: "INACTIVE", Owner: "02aa", Revision: 2},
+	}}, changes)
+}
+
+func TestWatchFilterMatch(t *testing.T) {
+	change := &ProductChange{Gtin: "00012345600012", Action: "set", State: "INACTIVE"}
+
+	tests := map[string]struct {
+		filter   WatchFilter
+		outMatch bool
+	}{
+		"empty":       {filter: WatchFilter{}, outMatch: true},
+		"gtinPrefix":  {filter: WatchFilter{GtinPrefix: "0001234"}, outMatch: true},
+		"otherPrefix": {filter: WatchFilter{GtinPrefix: "0009"}, outMatch: false},
+		"action":      {filter: WatchFilter{Actions: []string{"create", "set"}}, outMatch: true},
+		"otherAction": {filter: WatchFilter{Actions: []string{"delete"}}, outMatch: false},
+		"state":       {filter: WatchFilter{States: []string{"INACTIVE"}}, outMatch: true},
+		"otherState":  {filter: WatchFilter{GtinPrefix: "0001", States: []string{"ACTIVE"}}, outMatch: false},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, test.outMatch, test.filter.Match(change))
+	}
+}
@@ -0,0 +1,173 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package agent
+
+import (
+	"fmt"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+type Options struct {
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+type AgentCreate struct {
+	Args struct {
+		OrgId     string `positional-arg-name:"org" required:"true" description:"Identify the organization the agent acts for"`
+		PublicKey string `positional-arg-name:"key" required:"true" description:"Specify the public key of the agent"`
+	} `positional-args:"true"`
+	Roles    []string `long:"role" short:"r" required:"false" description:"Specify a role of the agent, may be repeated: product.create, product.update, product.lifecycle, product.delete"`
+	Inactive bool     `long:"inactive" description:"Create the agent without any permissions"`
+	Options
+}
+
+type AgentUpdate struct {
+	Args struct {
+		PublicKey string `positional-arg-name:"key" required:"true" description:"Specify the public key of the agent"`
+	} `positional-args:"true"`
+	Roles    []string `long:"role" short:"r" required:"false" description:"Specify a role of the agent, may be repeated: product.create, product.update, product.lifecycle, product.delete"`
+	Inactive bool     `long:"inactive" description:"Deactivate the agent"`
+	Options
+}
+
+type AgentList struct {
+	OrgId string `long:"org" description:"Only list the agents of an organization"`
+	Url   string `long:"url" description:"Specify URL of REST API"`
+	commands.ReadOptions
+}
+
+// Agent groups the agent subcommands under `mdata agent`
+type Agent struct {
+	Create AgentCreate
+	Update AgentUpdate
+	List   AgentList
+
+	command *flags.Command
+}
+
+func (args *Agent) Name() string {
+	return "agent"
+}
+
+func (args *Agent) KeyfilePassed() string {
+	return args.options().Keyfile
+}
+
+func (args *Agent) UrlPassed() string {
+	return args.options().Url
+}
+
+func (args *Agent) Register(parent *flags.Command) error {
+	cmd, err := parent.AddCommand(args.Name(), "Manages agents", "Creates, updates and lists the keys allowed to act for an organization.", &struct{}{})
+	if err != nil {
+		return err
+	}
+	args.command = cmd
+
+	if _, err := cmd.AddCommand("create", "Creates an agent", "Sends an mdata transaction to let <key> act for organization <org> with the given roles. Requires an admin of <org>.", &args.Create); err != nil {
+		return err
+	}
+	if _, err := cmd.AddCommand("update", "Updates an agent", "Sends an mdata transaction to replace the roles and active flag of agent <key>. Requires an admin of its organization.", &args.Update); err != nil {
+		return err
+	}
+	if _, err := cmd.AddCommand("list", "Displays all agents", "Shows every agent in mdata state.", &args.List); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Agent) active() string {
+	if args.command == nil || args.command.Active == nil {
+		return ""
+	}
+	return args.command.Active.Name
+}
+
+// options returns the connection options of the active subcommand
+func (args *Agent) options() Options {
+	switch args.active() {
+	case "create":
+		return args.Create.Options
+	case "update":
+		return args.Update.Options
+	case "list":
+		return Options{Url: args.List.Url}
+	}
+	return Options{}
+}
+
+func (args *Agent) Run() (string, error) {
+	name := args.active()
+
+	// Construct client
+	mdataClient, err := client.GetClient(args, name != "list")
+	if err != nil {
+		return "", err
+	}
+
+	var batchResult *client.BatchResult
+	var batchErr error
+	switch name {
+	case "create":
+		batchResult, batchErr = mdataClient.CreateAgent(
+			args.Create.Args.PublicKey, args.Create.Args.OrgId, !args.Create.Inactive, args.Create.Roles, args.Create.Wait)
+	case "update":
+		batchResult, batchErr = mdataClient.UpdateAgent(
+			args.Update.Args.PublicKey, !args.Update.Inactive, args.Update.Roles, args.Update.Wait)
+	case "list":
+		if mdataClient, err = mdataClient.ReadAt(args.List.ReadOptions); err != nil {
+			return "", err
+		}
+		return list(mdataClient, args.List.OrgId)
+	default:
+		return "", fmt.Errorf("Unknown agent command: %v", name)
+	}
+
+	if batchErr != nil {
+		return "", batchErr
+	}
+
+	return string(batchResult.GetJson()), nil
+}
+
+func list(mdataClient client.MdataClient, orgId string) (string, error) {
+	agents, err := mdataClient.ListAgents()
+	if err != nil {
+		return "", err
+	}
+
+	agentMap, err := data.DeserializeAgents(agents)
+	if err != nil {
+		return "", err
+	}
+	if orgId != "" {
+		for publicKey, agent := range agentMap {
+			if agent.OrganizationId != orgId {
+				delete(agentMap, publicKey)
+			}
+		}
+	}
+
+	return string(data.GetAgentMapJson(agentMap)), nil
+}
@@ -0,0 +1,101 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+)
+
+type Batch struct {
+	File    string `long:"file" short:"f" description:"Read the operations from a JSON file"`
+	Json    string `long:"json" description:"Give the operations as JSON"`
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Batch) Name() string {
+	return "batch"
+}
+
+func (args *Batch) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Batch) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Batch) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Applies several product operations at once", "Sends one mdata transaction with the create, update, set and delete operations given by --file or --json. They are applied in order, all of them or none.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Batch) Run() (string, error) {
+	// Construct client
+	wait := args.Wait
+
+	ops, err := args.read()
+	if err != nil {
+		return "", err
+	}
+
+	mdataClient, err := client.GetClient(args, true)
+	if err != nil {
+		return "", err
+	}
+
+	batchResult, batchErr := mdataClient.Apply(ops, wait)
+
+	if batchErr != nil {
+		return "", batchErr
+	}
+
+	return string(batchResult.GetJson()), nil
+}
+
+// read decodes the operations, a JSON array of
+// {"action", "gtin", "attributes", "state", "reason", "expected_revision"}
+func (args *Batch) read() ([]client.Op, error) {
+	definition := []byte(args.Json)
+	if args.File != "" {
+		var err error
+		definition, err = ioutil.ReadFile(args.File)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read operations: %v", err)
+		}
+	}
+	if len(definition) == 0 {
+		return nil, errors.New("Operations are required, use --file or --json")
+	}
+
+	ops := []client.Op{}
+	if err := json.Unmarshal(definition, &ops); err != nil {
+		return nil, fmt.Errorf("Malformed operations: %v", err)
+	}
+	return ops, nil
+}
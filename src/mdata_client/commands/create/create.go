@@ -20,7 +20,6 @@ package create
 import (
 	flags "github.com/jessevdk/go-flags"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
-	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
 )
 
 type Create struct {
@@ -64,14 +63,11 @@ func (args *Create) Run() (string, error) {
 		return "", err
 	}
 
-	batchStatusResponse, batchStatusErr := mdataClient.Create(gtin, attributes, wait)
+	batchResult, batchErr := mdataClient.Create(gtin, attributes, wait)
 
-	if batchStatusErr != nil {
-		return "", batchStatusErr
+	if batchErr != nil {
+		return "", batchErr
 	}
 
-	// Query batch transaction status link
-	status := commands.GetTransactionStatus(batchStatusResponse)
-
-	return status, nil
+	return string(batchResult.GetJson()), nil
 }
@@ -20,16 +20,16 @@ package delete
 import (
 	"github.com/jessevdk/go-flags"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
-	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
 )
 
 type Delete struct {
 	Args struct {
 		Gtin string `positional-arg-name:"gtin" required:"true" description:"Identify the gtin of the product to delete"`
 	} `positional-args:"true"`
-	Url     string `long:"url" description:"Specify URL of REST API"`
-	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
-	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+	IfRevision uint64 `long:"if-revision" description:"Only apply the change if the product is at this revision, as shown by show or history"`
+	Url        string `long:"url" description:"Specify URL of REST API"`
+	Keyfile    string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait       uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
 }
 
 func (args *Delete) Name() string {
@@ -55,6 +55,7 @@ func (args *Delete) Register(parent *flags.Command) error {
 func (args *Delete) Run() (string, error) {
 	// Construct client
 	gtin := args.Args.Gtin
+	ifRevision := args.IfRevision
 	wait := args.Wait
 
 	mdataClient, err := client.GetClient(args, true)
@@ -62,14 +63,11 @@ func (args *Delete) Run() (string, error) {
 		return "", err
 	}
 
-	batchStatusResponse, batchStatusErr := mdataClient.Delete(gtin, wait)
+	batchResult, batchErr := mdataClient.Delete(gtin, ifRevision, wait)
 
-	if batchStatusErr != nil {
-		return "", batchStatusErr
+	if batchErr != nil {
+		return "", batchErr
 	}
 
-	// Query batch transaction status link
-	status := commands.GetTransactionStatus(batchStatusResponse)
-
-	return status, nil
+	return string(batchResult.GetJson()), nil
 }
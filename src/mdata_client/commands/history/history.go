@@ -0,0 +1,71 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package history
+
+import (
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+type History struct {
+	Args struct {
+		Gtin string `positional-arg-name:"gtin" required:"true" description:"Identify the gtin of the product to show the history of"`
+	} `positional-args:"true"`
+	Url string `long:"url" description:"Specify URL of REST API"`
+	commands.ReadOptions
+}
+
+func (args *History) Name() string {
+	return "history"
+}
+
+func (args *History) KeyfilePassed() string {
+	return ""
+}
+
+func (args *History) UrlPassed() string {
+	return args.Url
+}
+
+func (args *History) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Displays the revisions of a product", "Shows every change of the product <gtin>, oldest first: its revision, action, signer, transaction and the previous values of the fields it changed.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *History) Run() (string, error) {
+	// Construct client
+	mdataClient, err := client.GetClient(args, false)
+	if err != nil {
+		return "", err
+	}
+	mdataClient, err = mdataClient.ReadAt(args.ReadOptions)
+	if err != nil {
+		return "", err
+	}
+	revisions, err := mdataClient.History(args.Args.Gtin)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data.GetHistoryJson(revisions)), nil
+}
@@ -0,0 +1,99 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+)
+
+type Import struct {
+	Args struct {
+		File string `positional-arg-name:"file" required:"true" description:"Identify the JSON file of operations to import"`
+	} `positional-args:"true"`
+	BatchSize         int    `long:"batch-size" description:"Set the number of transactions per batch, a batch is committed as a whole (default: 100)"`
+	BatchesPerRequest int    `long:"batches-per-request" description:"Set the number of batches sent per request (default: 10)"`
+	Url               string `long:"url" description:"Specify URL of REST API"`
+	Keyfile           string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait              uint   `long:"wait" description:"Set time, in seconds, to wait for each request's batches to commit"`
+}
+
+func (args *Import) Name() string {
+	return "import"
+}
+
+func (args *Import) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Import) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Import) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Imports many product operations", "Sends one mdata transaction per operation in <file>, grouped into batches, and shows the status of every transaction.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Import) Run() (string, error) {
+	// Construct client
+	file := args.Args.File
+	batchSize := args.BatchSize
+	batchesPerRequest := args.BatchesPerRequest
+	wait := args.Wait
+
+	// The file holds the operations taken by mdata batch
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read operations: %v", err)
+	}
+	ops := []client.Op{}
+	if err := json.Unmarshal(content, &ops); err != nil {
+		return "", fmt.Errorf("Malformed operations: %v", err)
+	}
+
+	mdataClient, err := client.GetClient(args, true)
+	if err != nil {
+		return "", err
+	}
+
+	builder := mdataClient.NewBatchBuilder(batchSize, batchesPerRequest)
+	for i, op := range ops {
+		if err := builder.Add(op); err != nil {
+			return "", fmt.Errorf("Operation %v: %v", i+1, err)
+		}
+	}
+
+	statuses, err := builder.Submit(wait)
+	if err != nil {
+		return "", fmt.Errorf("Submitted %v of %v transactions: %v", len(statuses), len(ops), err)
+	}
+
+	response, err := json.Marshal(statuses)
+	if err != nil {
+		return "", err
+	}
+	return string(response), nil
+}
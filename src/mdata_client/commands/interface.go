@@ -10,3 +10,10 @@ type Command interface {
 	UrlPassed() string
 	Run() (string, error)
 }
+
+// ReadOptions select the block a command reads state at. State is read at the
+// current head unless one of them is given.
+type ReadOptions struct {
+	Head string  `long:"head" description:"Read state as of the block with this id"`
+	At   *uint64 `long:"at" description:"Read state as of the block with this number"`
+}
@@ -18,13 +18,28 @@
 package list
 
 import (
+	"encoding/json"
+
 	flags "github.com/jessevdk/go-flags"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
 	"github.com/tross-tyson/mdata_go/src/shared/data"
 )
 
 type List struct {
-	Url string `long:"url" description:"Specify URL of REST API"`
+	GtinPrefix string            `long:"gtin-prefix" short:"g" description:"Only list GTINs starting with this prefix, e.g. a company prefix"`
+	States     []string          `long:"state" short:"s" description:"Only list products in this state. Repeat for several."`
+	Attributes map[string]string `long:"attribute" short:"a" description:"Only list products with this key:value attribute. Repeat for several."`
+	Limit      uint              `long:"limit" description:"List one page of this many state entries, and the start of the next page"`
+	Start      string            `long:"start" description:"List one page beginning at this paging position, as given by the last page"`
+	Url        string            `long:"url" description:"Specify URL of REST API"`
+	commands.ReadOptions
+}
+
+// Page is the output of list when --limit or --start is given
+type Page struct {
+	Products map[string]*data.Product `json:"products"`
+	Next     string                   `json:"next,omitempty"`
 }
 
 func (args *List) Name() string {
@@ -40,7 +55,7 @@ func (args *List) UrlPassed() string {
 }
 
 func (args *List) Register(parent *flags.Command) error {
-	_, err := parent.AddCommand(args.Name(), "Displays all mdata products", "Shows the attributes of all gtins in mdata state.", args)
+	_, err := parent.AddCommand(args.Name(), "Displays all mdata products", "Shows the attributes of all gtins in mdata state, reading every page of state unless --limit or --start is given.", args)
 	if err != nil {
 		return err
 	}
@@ -48,21 +63,53 @@ func (args *List) Register(parent *flags.Command) error {
 }
 
 func (args *List) Run() (string, error) {
+	options := client.ListOptions{
+		Limit:      args.Limit,
+		Start:      args.Start,
+		GtinPrefix: args.GtinPrefix,
+		States:     args.States,
+		Attributes: args.Attributes,
+	}
 
-	//TODO: Check back here after List() has been defined in mdataClient
 	// Construct client
 	mdataClient, err := client.GetClient(args, false)
 	if err != nil {
 		return "", err
 	}
-	products, err := mdataClient.List()
+	mdataClient, err = mdataClient.ReadAt(args.ReadOptions)
 	if err != nil {
 		return "", err
 	}
 
-	productMap, _ := data.Deserialize([]byte(products))
+	if args.Limit > 0 || args.Start != "" {
+		products, next, err := mdataClient.ListPage(options)
+		if err != nil {
+			return "", err
+		}
+		response, err := json.Marshal(Page{productMap(products), next})
+		if err != nil {
+			return "", err
+		}
+		return string(response), nil
+	}
 
-	response := data.GetProductMapJson(productMap)
+	products := []*data.Product{}
+	it := mdataClient.Products(options)
+	for it.Next() {
+		products = append(products, it.Product())
+	}
+	if err := it.Err(); err != nil {
+		return "", err
+	}
+	response := data.GetProductMapJson(productMap(products))
 
 	return string(response), nil
 }
+
+func productMap(products []*data.Product) map[string]*data.Product {
+	productMap := make(map[string]*data.Product)
+	for _, product := range products {
+		productMap[product.Gtin] = product
+	}
+	return productMap
+}
@@ -0,0 +1,228 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package org
+
+import (
+	"fmt"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+type Options struct {
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+type OrgCreate struct {
+	Args struct {
+		Id   string `positional-arg-name:"id" required:"true" description:"Identify the organization to create"`
+		Name string `positional-arg-name:"name" required:"true" description:"Specify the name of the organization"`
+	} `positional-args:"true"`
+	Prefixes []string `long:"prefix" short:"p" required:"false" description:"Specify a GS1 company prefix of the organization, may be repeated"`
+	Options
+}
+
+type OrgUpdate struct {
+	Args struct {
+		Id   string `positional-arg-name:"id" required:"true" description:"Identify the organization to update"`
+		Name string `positional-arg-name:"name" required:"true" description:"Specify the name of the organization"`
+	} `positional-args:"true"`
+	Prefixes []string `long:"prefix" short:"p" required:"false" description:"Specify a GS1 company prefix of the organization, may be repeated"`
+	Options
+}
+
+type OrgKey struct {
+	Args struct {
+		Id        string `positional-arg-name:"id" required:"true" description:"Identify the organization"`
+		PublicKey string `positional-arg-name:"key" required:"true" description:"Specify the public key of the admin"`
+	} `positional-args:"true"`
+	Options
+}
+
+type OrgShow struct {
+	Args struct {
+		Id string `positional-arg-name:"id" required:"true" description:"Identify the organization to show"`
+	} `positional-args:"true"`
+	Url string `long:"url" description:"Specify URL of REST API"`
+	commands.ReadOptions
+}
+
+type OrgList struct {
+	Url string `long:"url" description:"Specify URL of REST API"`
+	commands.ReadOptions
+}
+
+// Org groups the organization registry subcommands under `mdata org`
+type Org struct {
+	Create    OrgCreate
+	Update    OrgUpdate
+	AddKey    OrgKey
+	RemoveKey OrgKey
+	Show      OrgShow
+	List      OrgList
+
+	command *flags.Command
+}
+
+func (args *Org) Name() string {
+	return "org"
+}
+
+func (args *Org) KeyfilePassed() string {
+	return args.options().Keyfile
+}
+
+func (args *Org) UrlPassed() string {
+	return args.options().Url
+}
+
+func (args *Org) Register(parent *flags.Command) error {
+	cmd, err := parent.AddCommand(args.Name(), "Manages organizations", "Creates, updates and lists the organizations owning GS1 company prefixes.", &struct{}{})
+	if err != nil {
+		return err
+	}
+	args.command = cmd
+
+	subcommands := []struct {
+		name  string
+		short string
+		long  string
+		data  interface{}
+	}{
+		{"create", "Creates an organization", "Sends an mdata transaction to create organization <id>. The signer becomes its first admin.", &args.Create},
+		{"update", "Updates an organization", "Sends an mdata transaction to set the name and company prefixes of organization <id>.", &args.Update},
+		{"add-key", "Adds an admin to an organization", "Sends an mdata transaction to make <key> an admin of organization <id>.", &args.AddKey},
+		{"remove-key", "Removes an admin from an organization", "Sends an mdata transaction to remove <key> from the admins of organization <id>.", &args.RemoveKey},
+		{"show", "Displays the specified organization", "Shows the name, admins and company prefixes of organization <id>.", &args.Show},
+		{"list", "Displays all organizations", "Shows every organization in mdata state.", &args.List},
+	}
+	for _, sub := range subcommands {
+		if _, err := cmd.AddCommand(sub.name, sub.short, sub.long, sub.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (args *Org) active() string {
+	if args.command == nil || args.command.Active == nil {
+		return ""
+	}
+	return args.command.Active.Name
+}
+
+// options returns the connection options of the active subcommand
+func (args *Org) options() Options {
+	switch args.active() {
+	case "create":
+		return args.Create.Options
+	case "update":
+		return args.Update.Options
+	case "add-key":
+		return args.AddKey.Options
+	case "remove-key":
+		return args.RemoveKey.Options
+	case "show":
+		return Options{Url: args.Show.Url}
+	case "list":
+		return Options{Url: args.List.Url}
+	}
+	return Options{}
+}
+
+func (args *Org) Run() (string, error) {
+	name := args.active()
+	readFile := name != "show" && name != "list"
+
+	// Construct client
+	mdataClient, err := client.GetClient(args, readFile)
+	if err != nil {
+		return "", err
+	}
+
+	var batchResult *client.BatchResult
+	var batchErr error
+	switch name {
+	case "create":
+		batchResult, batchErr = mdataClient.CreateOrganization(
+			args.Create.Args.Id, args.Create.Args.Name, args.Create.Prefixes, args.Create.Wait)
+	case "update":
+		batchResult, batchErr = mdataClient.UpdateOrganization(
+			args.Update.Args.Id, args.Update.Args.Name, args.Update.Prefixes, args.Update.Wait)
+	case "add-key":
+		batchResult, batchErr = mdataClient.AddOrganizationKey(
+			args.AddKey.Args.Id, args.AddKey.Args.PublicKey, args.AddKey.Wait)
+	case "remove-key":
+		batchResult, batchErr = mdataClient.RemoveOrganizationKey(
+			args.RemoveKey.Args.Id, args.RemoveKey.Args.PublicKey, args.RemoveKey.Wait)
+	case "show":
+		if mdataClient, err = mdataClient.ReadAt(args.Show.ReadOptions); err != nil {
+			return "", err
+		}
+		return show(mdataClient, args.Show.Args.Id)
+	case "list":
+		if mdataClient, err = mdataClient.ReadAt(args.List.ReadOptions); err != nil {
+			return "", err
+		}
+		return list(mdataClient)
+	default:
+		return "", fmt.Errorf("Unknown org command: %v", name)
+	}
+
+	if batchErr != nil {
+		return "", batchErr
+	}
+
+	return string(batchResult.GetJson()), nil
+}
+
+func show(mdataClient client.MdataClient, id string) (string, error) {
+	organizations, err := mdataClient.ShowOrganization(id)
+	if err != nil {
+		return "", err
+	}
+
+	organizationMap, err := data.DeserializeOrganizations([]byte(organizations))
+	if err != nil {
+		return "", err
+	}
+	organization, ok := organizationMap[id]
+	if !ok {
+		return "", fmt.Errorf("No such organization: %s", id)
+	}
+
+	return string(organization.GetJson()), nil
+}
+
+func list(mdataClient client.MdataClient) (string, error) {
+	organizations, err := mdataClient.ListOrganizations()
+	if err != nil {
+		return "", err
+	}
+
+	organizationMap, err := data.DeserializeOrganizations(organizations)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data.GetOrganizationMapJson(organizationMap)), nil
+}
@@ -0,0 +1,83 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package patch
+
+import (
+	"errors"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+)
+
+type Patch struct {
+	Args struct {
+		Gtin string `positional-arg-name:"gtin" required:"true" description:"Identify the gtin of the product to patch"`
+	} `positional-args:"true"`
+	Attributes map[string]string `long:"attributes" short:"a" required:"false" description:"Specify key:value pair of a product attribute to set"`
+	Unset      []string          `long:"unset" required:"false" description:"Specify the key of a product attribute to remove"`
+	IfRevision uint64            `long:"if-revision" description:"Only apply the change if the product is at this revision, as shown by show or history"`
+	Url        string            `long:"url" description:"Specify URL of REST API"`
+	Keyfile    string            `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait       uint              `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Patch) Name() string {
+	return "patch"
+}
+
+func (args *Patch) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Patch) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Patch) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Patches a product", "Sends an mdata transaction to set <attributes> and remove the --unset keys of <gtin>, keeping its other attributes.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Patch) Run() (string, error) {
+	// Construct client
+	gtin := args.Args.Gtin
+	attributes := args.Attributes
+	unset := args.Unset
+	ifRevision := args.IfRevision
+	wait := args.Wait
+
+	if len(attributes) == 0 && len(unset) == 0 {
+		return "", errors.New("Patch requires attributes to set (-a) or unset (--unset)")
+	}
+
+	mdataClient, err := client.GetClient(args, true)
+	if err != nil {
+		return "", err
+	}
+
+	batchResult, batchErr := mdataClient.Patch(gtin, attributes, unset, ifRevision, wait)
+
+	if batchErr != nil {
+		return "", batchErr
+	}
+
+	return string(batchResult.GetJson()), nil
+}
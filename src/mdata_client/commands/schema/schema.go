@@ -0,0 +1,220 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+type Options struct {
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+// Definition is where a schema's JSON definition is read from
+type Definition struct {
+	File string `long:"file" short:"f" description:"Read the schema definition from a JSON file"`
+	Json string `long:"json" description:"Give the schema definition as JSON"`
+}
+
+type SchemaCreate struct {
+	Args struct {
+		OrgId string `positional-arg-name:"org" required:"true" description:"Identify the organization that will own the schema"`
+	} `positional-args:"true"`
+	Definition
+	Options
+}
+
+type SchemaUpdate struct {
+	Definition
+	Options
+}
+
+type SchemaShow struct {
+	Args struct {
+		Name string `positional-arg-name:"name" required:"true" description:"Identify the schema to show"`
+	} `positional-args:"true"`
+	Url string `long:"url" description:"Specify URL of REST API"`
+	commands.ReadOptions
+}
+
+type SchemaList struct {
+	Url string `long:"url" description:"Specify URL of REST API"`
+	commands.ReadOptions
+}
+
+// Schema groups the attribute schema subcommands under `mdata schema`
+type Schema struct {
+	Create SchemaCreate
+	Update SchemaUpdate
+	Show   SchemaShow
+	List   SchemaList
+
+	command *flags.Command
+}
+
+func (args *Schema) Name() string {
+	return "schema"
+}
+
+func (args *Schema) KeyfilePassed() string {
+	return args.options().Keyfile
+}
+
+func (args *Schema) UrlPassed() string {
+	return args.options().Url
+}
+
+func (args *Schema) Register(parent *flags.Command) error {
+	cmd, err := parent.AddCommand(args.Name(), "Manages attribute schemas", "Creates, updates and shows the schemas product attributes are checked against.", &struct{}{})
+	if err != nil {
+		return err
+	}
+	args.command = cmd
+
+	if _, err := cmd.AddCommand("create", "Creates a schema", "Sends an mdata transaction to create the schema defined by --file or --json, owned by organization <org>.", &args.Create); err != nil {
+		return err
+	}
+	if _, err := cmd.AddCommand("update", "Updates a schema", "Sends an mdata transaction to replace the properties of the schema defined by --file or --json.", &args.Update); err != nil {
+		return err
+	}
+	if _, err := cmd.AddCommand("show", "Displays the specified schema", "Shows the properties of schema <name>.", &args.Show); err != nil {
+		return err
+	}
+	if _, err := cmd.AddCommand("list", "Displays all schemas", "Shows every schema in mdata state.", &args.List); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Schema) active() string {
+	if args.command == nil || args.command.Active == nil {
+		return ""
+	}
+	return args.command.Active.Name
+}
+
+// options returns the connection options of the active subcommand
+func (args *Schema) options() Options {
+	switch args.active() {
+	case "create":
+		return args.Create.Options
+	case "update":
+		return args.Update.Options
+	case "show":
+		return Options{Url: args.Show.Url}
+	case "list":
+		return Options{Url: args.List.Url}
+	}
+	return Options{}
+}
+
+func (args *Schema) Run() (string, error) {
+	name := args.active()
+	readFile := name == "create" || name == "update"
+
+	// Construct client
+	mdataClient, err := client.GetClient(args, readFile)
+	if err != nil {
+		return "", err
+	}
+
+	var batchResult *client.BatchResult
+	var batchErr error
+	switch name {
+	case "create":
+		schema, err := args.Create.Definition.read()
+		if err != nil {
+			return "", err
+		}
+		schema.Owner = args.Create.Args.OrgId
+		batchResult, batchErr = mdataClient.CreateSchema(schema, args.Create.Wait)
+	case "update":
+		schema, err := args.Update.Definition.read()
+		if err != nil {
+			return "", err
+		}
+		batchResult, batchErr = mdataClient.UpdateSchema(schema, args.Update.Wait)
+	case "show":
+		if mdataClient, err = mdataClient.ReadAt(args.Show.ReadOptions); err != nil {
+			return "", err
+		}
+		schema, err := mdataClient.GetSchema(args.Show.Args.Name)
+		if err != nil {
+			return "", err
+		}
+		if schema == nil {
+			return "", fmt.Errorf("No such schema: %s", args.Show.Args.Name)
+		}
+		return string(schema.GetJson()), nil
+	case "list":
+		if mdataClient, err = mdataClient.ReadAt(args.List.ReadOptions); err != nil {
+			return "", err
+		}
+		schemas, err := mdataClient.ListSchemas()
+		if err != nil {
+			return "", err
+		}
+		schemaMap, err := data.DeserializeSchemas(schemas)
+		if err != nil {
+			return "", err
+		}
+		return string(data.GetSchemaMapJson(schemaMap)), nil
+	default:
+		return "", fmt.Errorf("Unknown schema command: %v", name)
+	}
+
+	if batchErr != nil {
+		return "", batchErr
+	}
+
+	return string(batchResult.GetJson()), nil
+}
+
+// read decodes the schema definition and checks it before it is submitted
+func (d *Definition) read() (*data.Schema, error) {
+	definition := []byte(d.Json)
+	if d.File != "" {
+		var err error
+		definition, err = ioutil.ReadFile(d.File)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read schema definition: %v", err)
+		}
+	}
+	if len(definition) == 0 {
+		return nil, errors.New("A schema definition is required, use --file or --json")
+	}
+
+	schema := &data.Schema{}
+	if err := json.Unmarshal(definition, schema); err != nil {
+		return nil, fmt.Errorf("Malformed schema definition: %v", err)
+	}
+	if err := schema.CheckDefinition(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
@@ -20,17 +20,18 @@ package set
 import (
 	flags "github.com/jessevdk/go-flags"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
-	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
 )
 
 type Set struct {
 	Args struct {
 		Gtin  string `positional-arg-name:"gtin" required:"true" description:"Identify the gtin of the product to set state"`
-		State string `positional-arg-name:"state" required:"true" description:"Specify the state to set the <gtin>: ACTIVE, INACTIVE, DISCONTINUED" choice:"INACTIVE" choice:"ACTIVE" choice:"DISCONTINUED"`
+		State string `positional-arg-name:"state" required:"true" description:"Specify the state to set the <gtin>, one of the lifecycle states, by default ACTIVE, INACTIVE, DISCONTINUED"`
 	} `positional-args:"true"`
-	Url     string `long:"url" description:"Specify URL of REST API"`
-	Keyfile string `long:"keyfile" description:"Identify file containing user's private key"`
-	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+	Reason     string `long:"reason" short:"r" description:"Specify a reason code to store with the state, e.g. RECALL"`
+	IfRevision uint64 `long:"if-revision" description:"Only apply the change if the product is at this revision, as shown by show or history"`
+	Url        string `long:"url" description:"Specify URL of REST API"`
+	Keyfile    string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait       uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
 }
 
 func (args *Set) Name() string {
@@ -46,7 +47,7 @@ func (args *Set) UrlPassed() string {
 }
 
 func (args *Set) Register(parent *flags.Command) error {
-	_, err := parent.AddCommand(args.Name(), "Set state of a product", "Sends an mdata transaction to set state of <gtin> to <state>.", args)
+	_, err := parent.AddCommand(args.Name(), "Set state of a product", "Sends an mdata transaction to set state of <gtin> to <state>, with an optional --reason code. The product lifecycle decides which state changes are allowed.", args)
 	if err != nil {
 		return err
 	}
@@ -57,20 +58,19 @@ func (args *Set) Run() (string, error) {
 	// Construct client
 	gtin := args.Args.Gtin
 	state := args.Args.State
+	reason := args.Reason
+	ifRevision := args.IfRevision
 	wait := args.Wait
 
 	mdataClient, err := client.GetClient(args, true)
 	if err != nil {
 		return "", err
 	}
-	batchStatusResponse, batchStatusErr := mdataClient.Set(gtin, state, wait)
+	batchResult, batchErr := mdataClient.Set(gtin, state, reason, ifRevision, wait)
 
-	if batchStatusErr != nil {
-		return "", batchStatusErr
+	if batchErr != nil {
+		return "", batchErr
 	}
 
-	// Query batch transaction status link
-	status := commands.GetTransactionStatus(batchStatusResponse)
-
-	return status, nil
+	return string(batchResult.GetJson()), nil
 }
@@ -20,7 +20,7 @@ package show
 import (
 	flags "github.com/jessevdk/go-flags"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
-	"github.com/tross-tyson/mdata_go/src/shared/data"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
 )
 
 type Show struct {
@@ -28,6 +28,7 @@ type Show struct {
 		Gtin string `positional-arg-name:"gtin" required:"true" description:"Identify the gtin of the product to create"`
 	} `positional-args:"true"`
 	Url string `long:"url" description:"Specify URL of REST API"`
+	commands.ReadOptions
 }
 
 func (args *Show) Name() string {
@@ -51,25 +52,19 @@ func (args *Show) Register(parent *flags.Command) error {
 }
 
 func (args *Show) Run() (string, error) {
-	//TODO: Check back here after mdataClient.Show() has been defined
 	// Construct client
-	gtin := args.Args.Gtin
 	mdataClient, err := client.GetClient(args, false)
 	if err != nil {
 		return "", err
 	}
-	products, err := mdataClient.Show(gtin)
+	mdataClient, err = mdataClient.ReadAt(args.ReadOptions)
 	if err != nil {
 		return "", err
 	}
-
-	productMap, deserialize_err := data.Deserialize([]byte(products))
-
-	if deserialize_err != nil {
-		return "", deserialize_err
+	product, err := mdataClient.Show(args.Args.Gtin)
+	if err != nil {
+		return "", err
 	}
 
-	response := productMap[gtin].GetJson()
-
-	return string(response), nil
+	return string(product.GetJson()), nil
 }
@@ -0,0 +1,126 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package status
+
+import (
+	"encoding/json"
+	"errors"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+)
+
+type Status struct {
+	Args struct {
+		BatchIds []string `positional-arg-name:"batch-id" description:"Identify the batches to show the status of"`
+	} `positional-args:"true"`
+	Pending bool   `long:"pending" description:"Show the status of every batch still pending in the submission log"`
+	Url     string `long:"url" description:"Specify URL of REST API"`
+	Wait    uint   `long:"wait" description:"Set time, in seconds, to wait for pending batches to commit"`
+}
+
+func (args *Status) Name() string {
+	return "status"
+}
+
+func (args *Status) KeyfilePassed() string {
+	return ""
+}
+
+func (args *Status) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Status) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Displays the status of batches", "Shows the status of every <batch-id>, or with --pending of every batch the submission log in ~/.sawtooth/mdata still holds as pending, and records the statuses in the log.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Status) Run() (string, error) {
+	batchIds := args.Args.BatchIds
+	pending := args.Pending
+	wait := args.Wait
+
+	if pending == (len(batchIds) > 0) {
+		return "", errors.New("Status requires batch ids or --pending, not both")
+	}
+
+	// Construct client
+	mdataClient, err := client.GetClient(args, false)
+	if err != nil {
+		return "", err
+	}
+
+	if !pending {
+		results, err := mdataClient.Status(batchIds, wait)
+		if err != nil {
+			return "", err
+		}
+		response, err := json.Marshal(results)
+		if err != nil {
+			return "", err
+		}
+		return string(response), nil
+	}
+
+	submissions, err := client.DefaultSubmissionLog()
+	if err != nil {
+		return "", err
+	}
+	pendingSubmissions, err := submissions.Pending()
+	if err != nil {
+		return "", err
+	}
+	if len(pendingSubmissions) > 0 {
+		if err := resume(mdataClient, pendingSubmissions, wait); err != nil {
+			return "", err
+		}
+	}
+	response, err := json.Marshal(pendingSubmissions)
+	if err != nil {
+		return "", err
+	}
+	return string(response), nil
+}
+
+// resume sets the current status of submissions the log holds as pending
+func resume(mdataClient client.MdataClient, submissions []*client.Submission, wait uint) error {
+	batchIds := []string{}
+	for _, submission := range submissions {
+		batchIds = append(batchIds, submission.BatchId)
+	}
+	results, err := mdataClient.Status(batchIds, wait)
+	if err != nil {
+		return err
+	}
+
+	byId := make(map[string]*client.BatchResult)
+	for _, result := range results {
+		byId[result.BatchId] = result
+	}
+	for _, submission := range submissions {
+		if result, ok := byId[submission.BatchId]; ok {
+			submission.Status = result.Status
+			submission.Message = result.Message
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package transfer
+
+import (
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+)
+
+type Transfer struct {
+	Args struct {
+		Gtin     string `positional-arg-name:"gtin" required:"true" description:"Identify the gtin of the product to transfer"`
+		NewOwner string `positional-arg-name:"owner" required:"true" description:"Specify the public key of the new owner of <gtin>"`
+	} `positional-args:"true"`
+	IfRevision uint64 `long:"if-revision" description:"Only apply the change if the product is at this revision, as shown by show or history"`
+	Url        string `long:"url" description:"Specify URL of REST API"`
+	Keyfile    string `long:"keyfile" description:"Identify file containing user's private key"`
+	Wait       uint   `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
+}
+
+func (args *Transfer) Name() string {
+	return "transfer"
+}
+
+func (args *Transfer) KeyfilePassed() string {
+	return args.Keyfile
+}
+
+func (args *Transfer) UrlPassed() string {
+	return args.Url
+}
+
+func (args *Transfer) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Transfer ownership of a product", "Sends an mdata transaction to make <owner> the owner of <gtin>.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Transfer) Run() (string, error) {
+	// Construct client
+	gtin := args.Args.Gtin
+	newOwner := args.Args.NewOwner
+	ifRevision := args.IfRevision
+	wait := args.Wait
+
+	mdataClient, err := client.GetClient(args, true)
+	if err != nil {
+		return "", err
+	}
+	batchResult, batchErr := mdataClient.Transfer(gtin, newOwner, ifRevision, wait)
+
+	if batchErr != nil {
+		return "", batchErr
+	}
+
+	return string(batchResult.GetJson()), nil
+}
@@ -20,7 +20,6 @@ package update
 import (
 	flags "github.com/jessevdk/go-flags"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
-	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
 )
 
 type Update struct {
@@ -28,6 +27,7 @@ type Update struct {
 		Gtin string `positional-arg-name:"gtin" required:"true" description:"Identify the gtin of the product to update"`
 	} `positional-args:"true"`
 	Attributes map[string]string `long:"attributes" short:"a" required:"true" description:"Specify key:value pair to define product attributes"`
+	IfRevision uint64            `long:"if-revision" description:"Only apply the change if the product is at this revision, as shown by show or history"`
 	Url        string            `long:"url" description:"Specify URL of REST API"`
 	Keyfile    string            `long:"keyfile" description:"Identify file containing user's private key"`
 	Wait       uint              `long:"wait" description:"Set time, in seconds, to wait for transaction to commit"`
@@ -57,6 +57,7 @@ func (args *Update) Run() (string, error) {
 	// Construct client
 	gtin := args.Args.Gtin
 	attributes := args.Attributes
+	ifRevision := args.IfRevision
 	wait := args.Wait
 
 	mdataClient, err := client.GetClient(args, true)
@@ -64,14 +65,11 @@ func (args *Update) Run() (string, error) {
 		return "", err
 	}
 
-	batchStatusResponse, batchStatusErr := mdataClient.Update(gtin, attributes, wait)
+	batchResult, batchErr := mdataClient.Update(gtin, attributes, ifRevision, wait)
 
-	if batchStatusErr != nil {
-		return "", batchStatusErr
+	if batchErr != nil {
+		return "", batchErr
 	}
 
-	// Query batch transaction status link
-	status := commands.GetTransactionStatus(batchStatusResponse)
-
-	return status, nil
+	return string(batchResult.GetJson()), nil
 }
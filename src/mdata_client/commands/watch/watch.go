@@ -0,0 +1,72 @@
+/**
+ * Copyright 2018 Intel Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ * ------------------------------------------------------------------------------
+ */
+
+package watch
+
+import (
+	"fmt"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+)
+
+type Watch struct {
+	GtinPrefix string   `long:"gtin-prefix" short:"g" description:"Only show changes of GTINs starting with this prefix, e.g. a company prefix"`
+	Actions    []string `long:"action" short:"a" description:"Only show changes by this action, e.g. create or set. Repeat for several."`
+	States     []string `long:"state" short:"s" description:"Only show changes leaving the product in this state. Repeat for several."`
+	Validator  string   `long:"validator" description:"Specify the component endpoint of the validator (default: tcp://127.0.0.1:4004)"`
+}
+
+func (args *Watch) Name() string {
+	return "watch"
+}
+
+func (args *Watch) KeyfilePassed() string {
+	return ""
+}
+
+func (args *Watch) UrlPassed() string {
+	return ""
+}
+
+func (args *Watch) Register(parent *flags.Command) error {
+	_, err := parent.AddCommand(args.Name(), "Displays product changes as they commit", "Subscribes to the product events of the validator and shows every committed change, one JSON object per line, until interrupted.", args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (args *Watch) Run() (string, error) {
+	filter := client.WatchFilter{
+		GtinPrefix: args.GtinPrefix,
+		Actions:    args.Actions,
+		States:     args.States,
+	}
+
+	connection, err := client.DialValidator(args.Validator)
+	if err != nil {
+		return "", err
+	}
+	defer connection.Close()
+
+	err = client.Watch(connection, filter, func(change *client.ProductChange) error {
+		fmt.Println(string(change.GetJson()))
+		return nil
+	})
+	return "", err
+}
@@ -20,7 +20,7 @@ package constants
 const (
 	// String literals
 	FAMILY_NAME          string = "mdata"
-	FAMILY_VERSION       string = "1.0"
+	FAMILY_VERSION       string = "2.0"
 	DISTRIBUTION_NAME    string = "sawtooth-mdata"
 	DISTRIBUTION_VERSION string = ""
 	DEFAULT_URL          string = "http://127.0.0.1:8008"
@@ -29,10 +29,25 @@ const (
 	VERB_UPDATE    string = "update"
 	VERB_DELETE    string = "delete"
 	VERB_SET_STATE string = "set"
+	VERB_TRANSFER  string = "transfer"
+	VERB_PATCH     string = "patch"
+	VERB_BATCH     string = "batch"
+	// Organization verbs
+	VERB_ORG_CREATE     string = "org_create"
+	VERB_ORG_UPDATE     string = "org_update"
+	VERB_ORG_ADD_KEY    string = "org_add_key"
+	VERB_ORG_REMOVE_KEY string = "org_remove_key"
+	// Agent verbs
+	VERB_AGENT_CREATE string = "agent_create"
+	VERB_AGENT_UPDATE string = "agent_update"
+	// Schema verbs
+	VERB_SCHEMA_CREATE string = "schema_create"
+	VERB_SCHEMA_UPDATE string = "schema_update"
 	// APIs
 	BATCH_SUBMIT_API string = "batches"
 	BATCH_STATUS_API string = "batch_statuses"
 	STATE_API        string = "state"
+	BLOCKS_API       string = "blocks"
 	// Content types
 	CONTENT_TYPE_OCTET_STREAM string = "application/octet-stream"
 	// Integer literals
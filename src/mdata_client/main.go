@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"github.com/hyperledger/sawtooth-sdk-go/logging"
 	flags "github.com/jessevdk/go-flags"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/constants"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/parser"
@@ -30,6 +31,14 @@ import (
 
 var DISTRIBUTION_VERSION string
 
+// Exit codes of the CLI. 2 is left to a missing command.
+const (
+	EXIT_ERROR   = 1
+	EXIT_INVALID = 3
+	EXIT_TIMEOUT = 4
+	EXIT_UNKNOWN = 5
+)
+
 var logger *logging.Logger = logging.Get()
 var CmdsSlice []commands.Command = parser.Commands()
 
@@ -61,7 +70,7 @@ func runCommandLine(cli_parser *flags.Parser) {
 			response, err := cmd.Run()
 			if err != nil {
 				fmt.Println("Error: ", err)
-				os.Exit(1)
+				os.Exit(exitCode(err))
 			}
 			fmt.Println(response)
 			return
@@ -73,11 +82,27 @@ func runCommandLine(cli_parser *flags.Parser) {
 	return
 }
 
+// exitCode tells apart a transaction the validator rejected, one still
+// pending once the wait is over and one it does not know from other errors
+func exitCode(err error) int {
+	switch err.(type) {
+	case *client.ErrInvalidTransaction:
+		return EXIT_INVALID
+	case *client.ErrTimeout:
+		return EXIT_TIMEOUT
+	case *client.ErrUnknownBatch:
+		return EXIT_UNKNOWN
+	default:
+		return EXIT_ERROR
+	}
+}
+
 type Opts struct {
-	Verbose []bool `short:"v" long:"verbose" description:"Enable more verbose output"`
-	Version bool   `short:"V" long:"version" description:"Display version information"`
-	Server  bool   `short:"S" long:"server" description:"Run as REST Server instead of command line"`
-	Port    uint   `short:"p" long:"port" description:"Provide the port to run the REST Service. Default -p=8888"`
+	Verbose  []bool `short:"v" long:"verbose" description:"Enable more verbose output"`
+	Version  bool   `short:"V" long:"version" description:"Display version information"`
+	Server   bool   `short:"S" long:"server" description:"Run as REST Server instead of command line"`
+	Port     uint   `short:"p" long:"port" description:"Provide the port to run the REST Service. Default -p=8888"`
+	Keystore string `long:"keystore" description:"Keystore of the API keys and signing keys of the REST Service. Default ~/.sawtooth/mdata/keystore.json"`
 }
 
 func main() {
@@ -127,7 +152,7 @@ func main() {
 
 	if opts.Server {
 		// Instantiate RESTful API
-		rest_service.Run(opts.Port)
+		rest_service.Run(opts.Port, opts.Keystore)
 	} else {
 		//Debugging
 		// fmt.Printf("Opts PARSED FROM OS.ARGS: \n\t%v\n", opts)
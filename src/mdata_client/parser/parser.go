@@ -4,12 +4,22 @@ import (
 	"github.com/hyperledger/sawtooth-sdk-go/logging"
 	flags "github.com/jessevdk/go-flags"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/agent"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/batch"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/create"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/delete"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/history"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/importer"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/list"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/org"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/patch"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/schema"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/set"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/show"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/status"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/transfer"
 	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/update"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/commands/watch"
 	"os"
 )
 
@@ -20,9 +30,19 @@ func Commands() []commands.Command {
 		&create.Create{},
 		&delete.Delete{},
 		&update.Update{},
+		&patch.Patch{},
 		&set.Set{},
+		&transfer.Transfer{},
+		&batch.Batch{},
+		&importer.Import{},
+		&status.Status{},
 		&show.Show{},
+		&history.History{},
+		&watch.Watch{},
 		&list.List{},
+		&org.Org{},
+		&agent.Agent{},
+		&schema.Schema{},
 	}
 }
 
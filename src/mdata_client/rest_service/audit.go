@@ -0,0 +1,66 @@
+package rest_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"sync"
+	"time"
+)
+
+// AuditRecord is a batch a principal submitted through the REST API
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Principal string    `json:"principal"`
+	PublicKey string    `json:"public_key"`
+	Action    string    `json:"action"`
+	Resource  string    `json:"resource,omitempty"`
+	BatchId   string    `json:"batch_id"`
+}
+
+// AuditLog is an append-only file of the batches principals submitted, one
+// JSON record per line
+type AuditLog struct {
+	path string
+	// mu keeps records of concurrent requests from interleaving
+	mu sync.Mutex
+}
+
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// DefaultAuditLog returns ~/.sawtooth/mdata/audit.log of the user running the
+// server
+func DefaultAuditLog() (*AuditLog, error) {
+	username, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	return NewAuditLog(path.Join(username.HomeDir, ".sawtooth", "mdata", "audit.log")), nil
+}
+
+// Append adds a record to the end of the log, creating it if needed
+func (l *AuditLog) Append(record *AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(path.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("Failed to create audit log: %v", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to open audit log: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("Failed to write audit log: %v", err)
+	}
+	return nil
+}
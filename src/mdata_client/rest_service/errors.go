@@ -0,0 +1,152 @@
+package rest_service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// Codes of the error responses, for callers to tell failures apart without
+// reading the message. Errors of the request itself, e.g. a malformed body,
+// are coded after their HTTP status, e.g. BAD_REQUEST or PRECONDITION_FAILED.
+const (
+	CodeNotFound             = "NOT_FOUND"
+	CodeAlreadyExists        = "ALREADY_EXISTS"
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeInvalidTransaction   = "INVALID_TRANSACTION"
+	CodeValidatorUnavailable = "VALIDATOR_UNAVAILABLE"
+	CodeValidatorBusy        = "VALIDATOR_BUSY"
+	CodeTimeout              = "TIMEOUT"
+	CodeReadOnly             = "READ_ONLY"
+	CodeUnauthorized         = "UNAUTHORIZED"
+)
+
+// busyRetryAfter is the Retry-After, in seconds, of a VALIDATOR_BUSY response
+const busyRetryAfter = "5"
+
+// ErrorResponse is the body of every failed request
+type ErrorResponse struct {
+	Error *Error `json:"error" xml:"error"`
+}
+
+// Error is what failed. Details are specific to the code, e.g. the schema
+// violations of VALIDATION_FAILED.
+type Error struct {
+	Code    string      `json:"code" xml:"code"`
+	Message string      `json:"message" xml:"message"`
+	Details interface{} `json:"details,omitempty" xml:"details,omitempty"`
+}
+
+// ErrAlreadyExists is returned when a create names something already in state
+type ErrAlreadyExists struct {
+	Resource string
+}
+
+func (e *ErrAlreadyExists) Error() string {
+	return fmt.Sprintf("Existing %s", e.Resource)
+}
+
+// ErrReadOnly is returned by writes when the server has no key to sign with
+type ErrReadOnly struct {
+	Err error
+}
+
+func (e *ErrReadOnly) Error() string {
+	return fmt.Sprintf("Only serving reads: %v", e.Err)
+}
+
+// ErrUnauthorized is returned for a request without a known API key, when it
+// needs one
+type ErrUnauthorized struct {
+	Message string
+}
+
+func (e *ErrUnauthorized) Error() string {
+	return e.Message
+}
+
+// apiError returns the status and body of an error. Errors the client returns
+// untyped are it refusing the request before sending it, e.g. a malformed
+// GTIN or operation, so they are validation failures.
+func apiError(err error) (int, *ErrorResponse) {
+	switch e := err.(type) {
+	case *echo.HTTPError:
+		return e.Code, newErrorResponse(statusCode(e.Code), fmt.Sprintf("%v", e.Message), nil)
+	case *client.ErrNotFound:
+		return http.StatusNotFound, newErrorResponse(CodeNotFound, e.Error(), nil)
+	case *ErrAlreadyExists:
+		return http.StatusConflict, newErrorResponse(CodeAlreadyExists, e.Error(), nil)
+	case *client.ErrInvalidTransaction:
+		details := map[string]string{"batch_id": e.BatchId, "transaction_id": e.TransactionId}
+		switch {
+		case strings.HasSuffix(e.Message, "already exists"):
+			return http.StatusConflict, newErrorResponse(CodeAlreadyExists, e.Error(), details)
+		case strings.HasSuffix(e.Message, "does not exist"):
+			return http.StatusNotFound, newErrorResponse(CodeNotFound, e.Error(), details)
+		}
+		return http.StatusUnprocessableEntity, newErrorResponse(CodeInvalidTransaction, e.Error(), details)
+	case *data.SchemaError:
+		return http.StatusUnprocessableEntity, newErrorResponse(CodeValidationFailed, e.Error(), map[string]interface{}{
+			"schema":     e.Schema,
+			"violations": e.Violations,
+		})
+	case *client.ErrUnavailable:
+		return http.StatusBadGateway, newErrorResponse(CodeValidatorUnavailable, e.Error(), nil)
+	case *client.ErrBusy:
+		return http.StatusServiceUnavailable, newErrorResponse(CodeValidatorBusy, e.Error(), nil)
+	case *client.ErrRejected:
+		// A bad request to the REST API is a bad parameter of this one, e.g. an
+		// unknown head. Anything else it refuses is no fault of the caller.
+		status := http.StatusBadGateway
+		if e.Status == http.StatusBadRequest {
+			status = http.StatusBadRequest
+		}
+		return status, newErrorResponse(statusCode(status), e.Error(), e.Sawtooth)
+	case *client.ErrTimeout:
+		return http.StatusGatewayTimeout, newErrorResponse(CodeTimeout, e.Error(), map[string]string{"batch_id": e.BatchId})
+	case *ErrUnauthorized:
+		return http.StatusUnauthorized, newErrorResponse(CodeUnauthorized, e.Error(), nil)
+	case *ErrReadOnly:
+		return http.StatusServiceUnavailable, newErrorResponse(CodeReadOnly, e.Error(), nil)
+	}
+	return http.StatusUnprocessableEntity, newErrorResponse(CodeValidationFailed, err.Error(), nil)
+}
+
+// statusCode is the code of an error coded after its HTTP status, e.g.
+// BAD_REQUEST
+func statusCode(status int) string {
+	return strings.ToUpper(strings.Replace(http.StatusText(status), " ", "_", -1))
+}
+
+func newErrorResponse(code string, message string, details interface{}) *ErrorResponse {
+	return &ErrorResponse{&Error{Code: code, Message: message, Details: details}}
+}
+
+// errorHandler writes the error a handler returned as an ErrorResponse
+func errorHandler(err error, c echo.Context) {
+	status, response := apiError(err)
+	if status >= http.StatusInternalServerError {
+		logger.Errorf("%v %v: %v", c.Request().Method, c.Request().URL, err)
+	}
+	if c.Response().Committed {
+		return
+	}
+	if status == http.StatusUnauthorized {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="mdata"`)
+	}
+	if _, ok := err.(*client.ErrBusy); ok {
+		c.Response().Header().Set("Retry-After", busyRetryAfter)
+	}
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, response)
+	}
+	if err != nil {
+		logger.Errorf("Error writing response: %v", err)
+	}
+}
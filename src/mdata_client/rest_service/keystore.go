@@ -0,0 +1,99 @@
+package rest_service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/user"
+	"path"
+	"strings"
+)
+
+// Principal is a caller of the REST API. Its changes are signed with its own
+// key, read from Keyfile.
+type Principal struct {
+	Name string `json:"name"`
+	// ApiKeySha256 is the hex SHA-256 of the API key of the principal. The
+	// key itself is only known to the caller.
+	ApiKeySha256 string `json:"api_key_sha256"`
+	Keyfile      string `json:"keyfile"`
+}
+
+// Keystore maps the API keys of callers to their principals
+type Keystore struct {
+	Principals []*Principal `json:"principals"`
+
+	byKey map[string]*Principal
+}
+
+// LoadKeystore reads the keystore at keystorePath. Relative keyfiles are read
+// from the directory of the keystore.
+func LoadKeystore(keystorePath string) (*Keystore, error) {
+	b, err := ioutil.ReadFile(keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read keystore: %v", err)
+	}
+	keystore := &Keystore{}
+	if err := json.Unmarshal(b, keystore); err != nil {
+		return nil, fmt.Errorf("Malformed keystore %v: %v", keystorePath, err)
+	}
+	for _, principal := range keystore.Principals {
+		if principal.Keyfile != "" && !path.IsAbs(principal.Keyfile) {
+			principal.Keyfile = path.Join(path.Dir(keystorePath), principal.Keyfile)
+		}
+	}
+	if err := keystore.index(); err != nil {
+		return nil, fmt.Errorf("Malformed keystore %v: %v", keystorePath, err)
+	}
+	return keystore, nil
+}
+
+// DefaultKeystorePath returns ~/.sawtooth/mdata/keystore.json of the user
+// running the server
+func DefaultKeystorePath() (string, error) {
+	username, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return path.Join(username.HomeDir, ".sawtooth", "mdata", "keystore.json"), nil
+}
+
+// index checks every principal has a distinct name and API key, and a keyfile
+func (k *Keystore) index() error {
+	names := make(map[string]bool)
+	k.byKey = make(map[string]*Principal)
+	for i, principal := range k.Principals {
+		if principal.Name == "" {
+			return fmt.Errorf("Principal %v has no name", i+1)
+		}
+		if names[principal.Name] {
+			return fmt.Errorf("Principal %v is listed twice", principal.Name)
+		}
+		names[principal.Name] = true
+
+		hash := strings.ToLower(principal.ApiKeySha256)
+		if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("api_key_sha256 of principal %v must be a hex SHA-256", principal.Name)
+		}
+		if _, ok := k.byKey[hash]; ok {
+			return fmt.Errorf("Principal %v shares its API key with another", principal.Name)
+		}
+		k.byKey[hash] = principal
+
+		if principal.Keyfile == "" {
+			return fmt.Errorf("Principal %v has no keyfile", principal.Name)
+		}
+	}
+	return nil
+}
+
+// Authenticate returns the principal of an API key, or nil for an unknown key
+func (k *Keystore) Authenticate(apiKey string) *Principal {
+	if k == nil || apiKey == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return k.byKey[hex.EncodeToString(sum[:])]
+}
@@ -0,0 +1,96 @@
+package rest_service
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadKeystore(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	other := strings.Repeat("cd", 32)
+
+	tests := map[string]struct {
+		inKeystore  string
+		outKeyfiles []string
+		outError    string
+	}{
+		"relativeKeyfile": {
+			inKeystore:  `{"principals": [{"name": "partner-a", "api_key_sha256": "` + hash + `", "keyfile": "partner-a.priv"}]}`,
+			outKeyfiles: []string{"partner-a.priv"},
+		},
+		"absoluteKeyfile": {
+			inKeystore:  `{"principals": [{"name": "partner-a", "api_key_sha256": "` + strings.ToUpper(hash) + `", "keyfile": "/keys/partner-a.priv"}]}`,
+			outKeyfiles: []string{"/keys/partner-a.priv"},
+		},
+		"noName": {
+			inKeystore: `{"principals": [{"api_key_sha256": "` + hash + `", "keyfile": "partner-a.priv"}]}`,
+			outError:   "Principal 1 has no name",
+		},
+		"repeatedName": {
+			inKeystore: `{"principals": [{"name": "partner-a", "api_key_sha256": "` + hash + `", "keyfile": "a.priv"}, {"name": "partner-a", "api_key_sha256": "` + other + `", "keyfile": "b.priv"}]}`,
+			outError:   "Principal partner-a is listed twice",
+		},
+		"plainApiKey": {
+			inKeystore: `{"principals": [{"name": "partner-a", "api_key_sha256": "secret", "keyfile": "partner-a.priv"}]}`,
+			outError:   "api_key_sha256 of principal partner-a must be a hex SHA-256",
+		},
+		"sharedApiKey": {
+			inKeystore: `{"principals": [{"name": "partner-a", "api_key_sha256": "` + hash + `", "keyfile": "a.priv"}, {"name": "partner-b", "api_key_sha256": "` + hash + `", "keyfile": "b.priv"}]}`,
+			outError:   "Principal partner-b shares its API key with another",
+		},
+		"noKeyfile": {
+			inKeystore: `{"principals": [{"name": "partner-a", "api_key_sha256": "` + hash + `"}]}`,
+			outError:   "Principal partner-a has no keyfile",
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "rest_service")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	keystorePath := path.Join(dir, "keystore.json")
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Nil(t, ioutil.WriteFile(keystorePath, []byte(test.inKeystore), 0600))
+		keystore, err := LoadKeystore(keystorePath)
+		if test.outError != "" {
+			if assert.NotNil(t, err) {
+				assert.Equal(t, "Malformed keystore "+keystorePath+": "+test.outError, err.Error())
+			}
+			continue
+		}
+		if !assert.Nil(t, err) {
+			continue
+		}
+		for i, principal := range keystore.Principals {
+			keyfile := test.outKeyfiles[i]
+			if !path.IsAbs(keyfile) {
+				keyfile = path.Join(dir, keyfile)
+			}
+			assert.Equal(t, keyfile, principal.Keyfile)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	keystore := &Keystore{Principals: []*Principal{
+		// sha256 of "secret"
+		{Name: "partner-a", ApiKeySha256: "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b", Keyfile: "a.priv"},
+	}}
+	if !assert.Nil(t, keystore.index()) {
+		return
+	}
+
+	assert.Equal(t, keystore.Principals[0], keystore.Authenticate("secret"))
+	assert.Nil(t, keystore.Authenticate("Secret"))
+	assert.Nil(t, keystore.Authenticate(""))
+	var none *Keystore
+	assert.Nil(t, none.Authenticate("secret"))
+}
@@ -1,96 +1,180 @@
 package rest_service
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/sawtooth-sdk-go/logging"
-	flags "github.com/jessevdk/go-flags"
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
-	"github.com/tross-tyson/mdata_go/src/mdata_client/commands"
-	"github.com/tross-tyson/mdata_go/src/mdata_client/parser"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
 	"github.com/tross-tyson/mdata_go/src/shared/data"
 )
 
 var logger *logging.Logger = logging.Get()
-var CmdsSlice []commands.Command = parser.Commands()
 
-type CrudResponse struct {
-	Status  string       `json:"Status" sml:"Status" form:"Status" query:"Status"`
-	Product data.Product `json:"Product" sml:"Product" form:"Product" query:"Product"`
-}
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+	headerLink    = "Link"
+	headerApiKey  = "X-API-Key"
+
+	bearer           = "Bearer "
+	contextPrincipal = "principal"
+)
 
-func ParseRequestArgs(args []string) (string, error) {
+// maxWait is the longest an operation request waits for its batch
+const maxWait = 5 * time.Minute
 
-	var RestServiceParser *flags.Parser = parser.GetParser(CmdsSlice)
+// batchIdPattern matches the header signature that is the id of a batch
+var batchIdPattern = regexp.MustCompile("^[0-9a-f]{128}$")
 
-	for _, cmd := range CmdsSlice {
-		err := cmd.Register(RestServiceParser.Command)
-		if err != nil {
-			logger.Errorf("Couldn't register command %v: %v", cmd.Name(), err)
-			return "", err
+// server holds the handlers of the REST API. Requests only share the service,
+// which keeps no state between them.
+type server struct {
+	service *Service
+}
+
+// authenticate finds the principal of the API key a request sends, as
+// "Authorization: Bearer <key>" or "X-API-Key: <key>". Changes need one, so
+// they are signed with the principal's key, reads do not. A key not in the
+// keystore is refused on any route.
+func (s *server) authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		apiKey := c.Request().Header.Get(headerApiKey)
+		if authorization := c.Request().Header.Get(echo.HeaderAuthorization); authorization != "" {
+			if !strings.HasPrefix(authorization, bearer) {
+				return &ErrUnauthorized{"Authorization must be Bearer <API key>"}
+			}
+			apiKey = strings.TrimPrefix(authorization, bearer)
+		}
+		if apiKey == "" {
+			return next(c)
 		}
-	}
 
-	_, err := RestServiceParser.ParseArgs(args)
-	if err != nil {
-		return "", echo.NewHTTPError(http.StatusInternalServerError, "Error parsing arguments %v, %v", args, err)
+		principal, err := s.service.Authenticate(apiKey)
+		if err != nil {
+			return err
+		}
+		c.Set(contextPrincipal, principal)
+		return next(c)
 	}
+}
 
-	cmd_name := RestServiceParser.Command.Active.Name
+// principal returns the authenticated principal of a request, or nil
+func principal(c echo.Context) *Principal {
+	principal, _ := c.Get(contextPrincipal).(*Principal)
+	return principal
+}
 
-	for _, cmd := range CmdsSlice {
-		if cmd.Name() == cmd_name {
-			response, err := cmd.Run()
-			return response, err
+// listProduct lists the products matching the gtin_prefix, state and
+// attribute (key:value) query parameters. state and attribute can be
+// repeated. With limit or start, one page is listed, and the next page is
+// linked in the Link header and named in the paging of the response.
+func (s *server) listProduct(c echo.Context) error {
+	query := c.QueryParams()
+	options := client.ListOptions{
+		Start:      query.Get("start"),
+		GtinPrefix: query.Get("gtin_prefix"),
+		States:     query["state"],
+		Attributes: map[string]string{},
+	}
+	for _, attribute := range query["attribute"] {
+		parts := strings.SplitN(attribute, ":", 2)
+		if len(parts) != 2 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed attribute, expected key:value: '%v'", attribute))
 		}
+		options.Attributes[parts[0]] = parts[1]
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.ParseUint(limit, 10, 32)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit must be a number, GOT: %v", limit))
+		}
+		options.Limit = uint(n)
 	}
 
-	return "", fmt.Errorf("Command active name not found %v", cmd_name)
-}
+	products, next, err := s.service.ListProducts(options, c.QueryParam("head"))
 
-func listProduct(c echo.Context) error {
+	if err != nil {
+		return err
+	}
 
-	//2 Supply arguments to parser
-	args := []string{
-		"list",
+	if next != "" {
+		query.Set("start", next)
+		c.Response().Header().Set(headerLink, fmt.Sprintf(`<%s?%s>; rel="next"`, c.Request().URL.Path, query.Encode()))
 	}
+	return c.JSON(http.StatusOK, &ProductListResponse{
+		Products: products,
+		Paging:   Paging{Limit: options.Limit, Start: options.Start, Next: next},
+	})
+}
 
-	response, err := ParseRequestArgs(args)
+func (s *server) showProduct(c echo.Context) error {
+	product, err := s.service.ShowProduct(c.Param("gtin"), c.QueryParam("head"))
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return err
 	}
 
-	return c.JSON(http.StatusOK, response)
+	// The revision is the product's ETag, send it back as If-Match to only
+	// change the product if nobody else changed it since
+	c.Response().Header().Set(headerETag, etag(product.Revision))
+
+	return c.JSON(http.StatusOK, &ProductResponse{product})
 }
 
-func showProduct(c echo.Context) error {
-	//1. Get product id from REST param
-	gtin := c.Param("gtin")
+func etag(revision uint64) string {
+	return fmt.Sprintf(`"%d"`, revision)
+}
 
-	fmt.Printf("GOT PARAM: %v\n", gtin)
+// ifMatch returns the revision the If-Match header of a request names, so the
+// change is rejected on chain unless the product is still at that revision. A
+// product already at another revision is answered with 412 without sending
+// the transaction. No header, or "*", returns 0, which applies the change to
+// any revision.
+func (s *server) ifMatch(c echo.Context, gtin string) (uint64, error) {
+	header := c.Request().Header.Get(headerIfMatch)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
 
-	//2 Supply arguments to parser
-	args := []string{
-		"show",
-		gtin,
+	revision, err := strconv.ParseUint(strings.Trim(header, `"`), 10, 64)
+	if err != nil || revision == 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("If-Match must be the ETag of the product, e.g. \"3\", GOT: %v", header))
 	}
 
-	fmt.Printf("GOT ARGS: %v\n", args)
+	// A product that can not be read is left to the transaction to report
+	product, err := s.service.ShowProduct(gtin, "")
+	if err == nil && product.Revision != revision {
+		c.Response().Header().Set(headerETag, etag(product.Revision))
+		return 0, echo.NewHTTPError(http.StatusPreconditionFailed,
+			fmt.Sprintf("Product %v is at revision %v, not %v", gtin, product.Revision, revision))
+	}
 
-	response, err := ParseRequestArgs(args)
+	return revision, nil
+}
+
+func (s *server) showProductHistory(c echo.Context) error {
+	// Revisions of the product, oldest first. The history of a deleted
+	// product is kept.
+	revisions, err := s.service.ProductHistory(c.Param("gtin"), c.QueryParam("head"))
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return err
 	}
 
-	return c.JSON(http.StatusOK, response)
+	return c.JSON(http.StatusOK, &HistoryResponse{revisions})
 }
 
-func createProduct(c echo.Context) error {
+func (s *server) createProduct(c echo.Context) error {
 	product := &data.Product{}
 
 	//1 Get data
@@ -98,53 +182,69 @@ func createProduct(c echo.Context) error {
 		return err
 	}
 
-	//2 Supply arguments to parser
-	args := []string{
-		"create",
-		product.Gtin,
+	//2 Attributes are sent as given, numbers and booleans written out
+	result, err := s.service.CreateProduct(principal(c), product.Gtin, product.Attributes)
+
+	if err != nil {
+		return err
 	}
 
-	//3 Split attributes into arguments for the parser, append to args
-	attributes := product.Attributes.Serialize()
-	for _, key_value_pair := range strings.Split(string(attributes), ",") {
-		key_value_pair = strings.Replace(key_value_pair, "=", ":", 1)
-		args = append(args, "-a", key_value_pair)
+	return accepted(c, result)
+}
+
+// productMethod serves the custom methods of the product collection, such as
+// POST /products:batch. The router gives everything after /products as the
+// method, colon included.
+func (s *server) productMethod(c echo.Context) error {
+	switch c.Param("method") {
+	case ":batch":
+		return s.batchProducts(c)
 	}
+	return echo.ErrNotFound
+}
 
-	status, cmd_err := ParseRequestArgs(args)
+func (s *server) batchProducts(c echo.Context) error {
+	// The body is the list of operations, applied in order, all or none
+	ops := []client.Op{}
 
-	if cmd_err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%v", cmd_err))
+	//1 Get data
+	if err := c.Bind(&ops); err != nil {
+		return err
 	}
+	if len(ops) < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "At least one operation is required")
+	}
+
+	result, err := s.service.ApplyOperations(principal(c), ops)
 
-	response := &CrudResponse{Status: status, Product: *product}
+	if err != nil {
+		return err
+	}
 
-	return c.JSON(http.StatusOK, response)
+	return accepted(c, result)
 }
 
-func deleteProduct(c echo.Context) error {
+func (s *server) deleteProduct(c echo.Context) error {
 	// Use this function to delete an existing product
-	// Product must be in INACTIVE state to delete
+	// Product must be in a deletable state of the lifecycle, by default INACTIVE
 
 	//1 Get params
 	gtin := c.Param("gtin")
-
-	//2 Supply arguments to parser
-	args := []string{
-		"delete",
-		gtin,
+	revision, err := s.ifMatch(c, gtin)
+	if err != nil {
+		return err
 	}
 
-	status, err := ParseRequestArgs(args)
+	result, err := s.service.DeleteProduct(principal(c), gtin, revision)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%v", err))
+		return err
 	}
 
-	return c.JSON(http.StatusOK, fmt.Sprintf(`{"Status": %v}`, status))
+	return accepted(c, result)
 }
 
-func updateProductAttributes(c echo.Context) error {
+func (s *server) updateProductAttributes(c echo.Context) error {
 	// Use this function to update state or attributes of existing product
 	// An update of attributes will overwrite existing attributes of the product
 
@@ -171,32 +271,89 @@ func updateProductAttributes(c echo.Context) error {
 	if err := c.Bind(product); err != nil {
 		return err
 	}
+	if gtin := c.Param("gtin"); gtin != "" {
+		product.Gtin = gtin
+	}
+	revision, err := s.ifMatch(c, product.Gtin)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.service.UpdateProduct(principal(c), product.Gtin, product.Attributes, revision)
+
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
+}
+
+func (s *server) patchProduct(c echo.Context) error {
+	// Use this function to apply a JSON Merge Patch to the attributes of an
+	// existing product. Attributes set to null are removed, the others are set
+	// and attributes left out of the patch are kept.
+
+	//i.e.
+	/*
+
+		SAMPLE EXPECTED HTTP REQUEST JSON
+		request_data : {
+			Attributes: {
+				<key1>: <value1>,
+				<key2>: null
+			}
+		}
+
+	*/
 
-	//2 Supply arguments to parser
-	args := []string{
-		"update",
-		product.Gtin,
+	//1 Get params and data
+	gtin := c.Param("gtin")
+
+	patch := map[string]json.RawMessage{}
+	if err := json.NewDecoder(c.Request().Body).Decode(&patch); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed merge patch: %v", err))
+	}
+	var raw json.RawMessage
+	for key, value := range patch {
+		if !strings.EqualFold(key, "Attributes") {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only Attributes can be patched, not '%v'", key))
+		}
+		raw = value
 	}
 
-	//3 Split attributes into arguments for the parser, append to args
-	attributes := product.Attributes.Serialize()
-	for _, key_value_pair := range strings.Split(string(attributes), ",") {
-		key_value_pair = strings.Replace(key_value_pair, "=", ":", 1)
-		args = append(args, "-a", key_value_pair)
+	attributes := data.Attributes{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&attributes); err != nil || attributes == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Attributes must be an object of the attributes to set or remove")
 	}
 
-	status, cmd_err := ParseRequestArgs(args)
+	//2 Split the patch into attributes to set and keys to remove, sorted so
+	// the transaction is the same for the same patch
+	unset := []string{}
+	for _, key := range attributes.Keys() {
+		if attributes[key] == nil {
+			unset = append(unset, key)
+			delete(attributes, key)
+		}
+	}
+	sort.Strings(unset)
 
-	if cmd_err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%v", cmd_err))
+	revision, err := s.ifMatch(c, gtin)
+	if err != nil {
+		return err
 	}
 
-	response := &CrudResponse{Status: status, Product: *product}
+	result, err := s.service.PatchProduct(principal(c), gtin, attributes, unset, revision)
 
-	return c.JSON(http.StatusOK, response)
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
 }
 
-func updateProductState(c echo.Context) error {
+func (s *server) updateProductState(c echo.Context) error {
 	// Use this function to update state or attributes of existing product
 	// An update of attributes will overwrite existing attributes of the product
 
@@ -206,37 +363,328 @@ func updateProductState(c echo.Context) error {
 	if err := c.Bind(product); err != nil {
 		return err
 	}
+	if gtin := c.Param("gtin"); gtin != "" {
+		product.Gtin = gtin
+	}
+	revision, err := s.ifMatch(c, product.Gtin)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.service.SetProductState(principal(c), product.Gtin, product.State, product.Reason, revision)
+
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
+}
+
+func (s *server) updateProductOwner(c echo.Context) error {
+	// Use this function to transfer an existing product to a new owner
+	// Only the current owner of the product can transfer it
+
+	product := &data.Product{}
+
+	//1 Get data
+	if err := c.Bind(product); err != nil {
+		return err
+	}
+	if gtin := c.Param("gtin"); gtin != "" {
+		product.Gtin = gtin
+	}
+	revision, err := s.ifMatch(c, product.Gtin)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.service.TransferProduct(principal(c), product.Gtin, product.Owner, revision)
+
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
+}
+
+// accepted answers a change with the batch it was sent in, still PENDING.
+// Its outcome is followed at the Location of the operation.
+func accepted(c echo.Context, result *client.BatchResult) error {
+	c.Response().Header().Set(echo.HeaderLocation, operationPath(result.BatchId))
+	return c.JSON(http.StatusAccepted, &OperationResponse{result})
+}
+
+func operationPath(batchId string) string {
+	return fmt.Sprintf("/operations/%s", batchId)
+}
+
+// showOperation reports whether the batch of a change is PENDING, COMMITTED or
+// INVALID, with the reason the validator gave. ?wait=30s, or ?wait=30, waits
+// up to that long for it to leave PENDING, and answers PENDING once it is
+// over.
+func (s *server) showOperation(c echo.Context) error {
+	batchId := c.Param("id")
+	if !batchIdPattern.MatchString(batchId) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed operation id: %v", batchId))
+	}
+
+	wait, err := parseWait(c.QueryParam("wait"))
+	if err != nil {
+		return err
+	}
+
+	result, err := s.service.Operation(batchId, wait)
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &OperationResponse{result})
+}
+
+// parseWait returns the seconds a wait parameter names, as a duration or a
+// number of seconds, rounded up
+func parseWait(param string) (uint, error) {
+	if param == "" {
+		return 0, nil
+	}
+	var wait time.Duration
+	seconds, err := strconv.ParseUint(param, 10, 32)
+	if err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else {
+		wait, err = time.ParseDuration(param)
+	}
+	if err != nil || wait < 0 || wait > maxWait {
+		return 0, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("wait must be a duration up to %v, e.g. 30s, GOT: %v", maxWait, param))
+	}
+	return uint((wait + time.Second - 1) / time.Second), nil
+}
+
+func (s *server) listOrganization(c echo.Context) error {
+	organizations, err := s.service.ListOrganizations(c.QueryParam("head"))
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &OrganizationListResponse{organizations})
+}
+
+func (s *server) showOrganization(c echo.Context) error {
+	organization, err := s.service.ShowOrganization(c.Param("id"), c.QueryParam("head"))
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &OrganizationResponse{organization})
+}
+
+func (s *server) createOrganization(c echo.Context) error {
+	// The signer of the transaction becomes the organization's first admin
+	return s.sendOrganization(c, s.service.CreateOrganization)
+}
+
+func (s *server) updateOrganization(c echo.Context) error {
+	// Replaces the name and company prefixes of an existing organization
+	return s.sendOrganization(c, s.service.UpdateOrganization)
+}
+
+func (s *server) sendOrganization(c echo.Context, send func(*Principal, *data.Organization) (*client.BatchResult, error)) error {
+	organization := &data.Organization{}
+
+	//1 Get data
+	if err := c.Bind(organization); err != nil {
+		return err
+	}
+	if id := c.Param("id"); id != "" {
+		organization.Id = id
+	}
+
+	result, err := send(principal(c), organization)
+
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
+}
+
+func (s *server) addOrganizationKey(c echo.Context) error {
+	return s.sendOrganizationKey(c, s.service.AddOrganizationKey)
+}
+
+func (s *server) removeOrganizationKey(c echo.Context) error {
+	return s.sendOrganizationKey(c, s.service.RemoveOrganizationKey)
+}
+
+func (s *server) sendOrganizationKey(c echo.Context, send func(*Principal, string, string) (*client.BatchResult, error)) error {
+	result, err := send(principal(c), c.Param("id"), c.Param("key"))
+
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
+}
+
+func (s *server) listAgent(c echo.Context) error {
+	agents, err := s.service.ListAgents(c.QueryParam("org"), c.QueryParam("head"))
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &AgentListResponse{agents})
+}
+
+func (s *server) createAgent(c echo.Context) error {
+	// Only an admin of the agent's organization can create it
+	agent := &data.Agent{}
+
+	//1 Get data
+	if err := c.Bind(agent); err != nil {
+		return err
+	}
+
+	result, err := s.service.CreateAgent(principal(c), agent)
+
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
+}
+
+func (s *server) updateAgent(c echo.Context) error {
+	// Replaces the roles and active flag of an existing agent
+	agent := &data.Agent{}
+
+	//1 Get data
+	if err := c.Bind(agent); err != nil {
+		return err
+	}
+	agent.PublicKey = c.Param("key")
+
+	result, err := s.service.UpdateAgent(principal(c), agent)
+
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
+}
+
+func (s *server) listSchema(c echo.Context) error {
+	schemas, err := s.service.ListSchemas(c.QueryParam("head"))
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &SchemaListResponse{schemas})
+}
+
+func (s *server) showSchema(c echo.Context) error {
+	schema, err := s.service.ShowSchema(c.Param("name"), c.QueryParam("head"))
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &SchemaResponse{schema})
+}
+
+func (s *server) createSchema(c echo.Context) error {
+	// The schema is owned by the organization named in its owner field
+	schema := &data.Schema{}
 
-	//2 Supply arguments to parser
-	args := []string{
-		"set",
-		product.Gtin,
-		product.State,
+	//1 Get data
+	if err := c.Bind(schema); err != nil {
+		return err
 	}
 
-	status, cmd_err := ParseRequestArgs(args)
+	result, err := s.service.CreateSchema(principal(c), schema)
 
-	if cmd_err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%v", cmd_err))
+	if err != nil {
+		return err
 	}
 
-	response := &CrudResponse{Status: status, Product: *product}
+	return accepted(c, result)
+}
+
+func (s *server) updateSchema(c echo.Context) error {
+	// Replaces the properties of an existing schema
+	schema := &data.Schema{}
+
+	//1 Get data
+	if err := c.Bind(schema); err != nil {
+		return err
+	}
+	schema.Name = c.Param("name")
 
-	return c.JSON(http.StatusOK, response)
+	result, err := s.service.UpdateSchema(principal(c), schema)
+
+	if err != nil {
+		return err
+	}
+
+	return accepted(c, result)
 }
 
-func Run(port uint) {
+// NewEcho returns the REST API of a service
+func NewEcho(service *Service) *echo.Echo {
+	s := &server{service}
+
 	e := echo.New()
+	e.HTTPErrorHandler = errorHandler
 	e.Use(middleware.Logger())
-	e.Use(middleware.CORS()) //for now open to all origins
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{ExposeHeaders: []string{headerETag, headerLink, echo.HeaderLocation}})) //for now open to all origins
+	e.Use(s.authenticate)
+
+	e.GET("/products", s.listProduct)                      // list all products
+	e.GET("/products/:gtin", s.showProduct)                // show specific product
+	e.GET("/products/:gtin/history", s.showProductHistory) // show revisions of specific product
+
+	e.POST("/products", s.createProduct)                     // create new product
+	e.POST("/products:method", s.productMethod)              // apply several product operations with /products:batch
+	e.PUT("/products/attr/:gtin", s.updateProductAttributes) // update existing product attributes or state
+	e.PUT("/products/state/:gtin", s.updateProductState)     // update existing product attributes or state
+	e.PUT("/products/owner/:gtin", s.updateProductOwner)     // transfer existing product to a new owner
+	e.PATCH("/products/:gtin", s.patchProduct)               // set and remove some attributes of existing product
+	e.DELETE("/products/:gtin", s.deleteProduct)             // delete existing inactive product
 
-	e.GET("/products", listProduct)       // list all products
-	e.GET("/products/:gtin", showProduct) // show specific product
+	e.GET("/operations/:id", s.showOperation) // show outcome of the batch of a change, optionally ?wait=30s
+
+	e.GET("/organizations", s.listOrganization)     // list all organizations
+	e.GET("/organizations/:id", s.showOrganization) // show specific organization
+
+	e.POST("/organizations", s.createOrganization)                    // create new organization
+	e.PUT("/organizations/:id", s.updateOrganization)                 // update existing organization name and company prefixes
+	e.PUT("/organizations/:id/keys/:key", s.addOrganizationKey)       // add an admin to existing organization
+	e.DELETE("/organizations/:id/keys/:key", s.removeOrganizationKey) // remove an admin from existing organization
+
+	e.GET("/agents", s.listAgent)        // list all agents, optionally ?org=<id>
+	e.POST("/agents", s.createAgent)     // create new agent
+	e.PUT("/agents/:key", s.updateAgent) // replace roles and active flag of existing agent
+
+	e.GET("/schemas", s.listSchema)         // list all schemas
+	e.GET("/schemas/:name", s.showSchema)   // show specific schema
+	e.POST("/schemas", s.createSchema)      // create new schema
+	e.PUT("/schemas/:name", s.updateSchema) // replace properties of existing schema
+
+	e.GET("/openapi.json", showOpenAPI) // OpenAPI document of all routes
+	e.GET("/docs", showDocs)            // documentation page rendering the OpenAPI document
+
+	return e
+}
 
-	e.POST("/products", createProduct)                     // create new product
-	e.PUT("/products/attr/:gtin", updateProductAttributes) // update existing product attributes or state
-	e.PUT("/products/state/:gtin", updateProductState)     // update existing product attributes or state
-	e.DELETE("/products/:gtin", deleteProduct)             // delete existing inactive product
+// Run serves the REST API on port, signing changes with the keys of the
+// principals in the keystore at keystorePath
+func Run(port uint, keystorePath string) {
+	e := NewEcho(DefaultService(keystorePath))
 
 	if port != 0 {
 		e.Logger.Fatal(e.Start(fmt.Sprintf(":%d", port)))
@@ -0,0 +1,839 @@
+package rest_service
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+)
+
+// openAPIDocument describes every route of NewEcho. TestOpenAPIRoutes checks
+// the two against each other, so a route added to one must be added to the
+// other.
+const openAPIDocument = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "mdata REST API",
+    "description": "Product master data on Hyperledger Sawtooth. Reads answer the state of the current head, or of the block named by ?head. Changes need the API key of a principal, sent as Authorization: Bearer <key> or X-API-Key: <key>. They are signed with the key of the principal, recorded in the audit log of the server, sent in a batch, and answered with 202 Accepted and the operation of the batch to follow.",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/products": {
+      "get": {
+        "summary": "List products",
+        "description": "Products matching all filters, sorted by GTIN. Without limit or start every product is listed. With them one page is listed, and the next page is named in paging.next and linked in the Link header.",
+        "operationId": "listProducts",
+        "tags": ["products"],
+        "parameters": [
+          {"name": "gtin_prefix", "in": "query", "description": "Only products whose GTIN-14 starts with the prefix", "schema": {"type": "string", "pattern": "^[0-9]*$"}},
+          {"name": "state", "in": "query", "description": "Only products in one of the states", "schema": {"type": "array", "items": {"type": "string"}}, "explode": true},
+          {"name": "attribute", "in": "query", "description": "Only products with the attribute, as key:value", "schema": {"type": "array", "items": {"type": "string"}}, "explode": true},
+          {"name": "limit", "in": "query", "description": "Entries of state read for the page", "schema": {"type": "integer", "minimum": 0}},
+          {"name": "start", "in": "query", "description": "Position of the page, the paging.next of the page before", "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/Head"}
+        ],
+        "responses": {
+          "200": {
+            "description": "The products",
+            "headers": {"Link": {"description": "The next page, as <url>; rel=\"next\"", "schema": {"type": "string"}}},
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ProductListResponse"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      },
+      "post": {
+        "summary": "Create a product",
+        "operationId": "createProduct",
+        "tags": ["products"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ProductCreate"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "409": {"$ref": "#/components/responses/AlreadyExists"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/products:batch": {
+      "post": {
+        "summary": "Apply several product operations",
+        "description": "The operations are applied in order in one transaction, all of them or none.",
+        "operationId": "batchProducts",
+        "tags": ["products"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "array", "minItems": 1, "items": {"$ref": "#/components/schemas/Op"}}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/products/{gtin}": {
+      "parameters": [{"$ref": "#/components/parameters/Gtin"}],
+      "get": {
+        "summary": "Show a product",
+        "operationId": "showProduct",
+        "tags": ["products"],
+        "parameters": [{"$ref": "#/components/parameters/Head"}],
+        "responses": {
+          "200": {
+            "description": "The product",
+            "headers": {"ETag": {"description": "The revision of the product, e.g. \"3\", to send back as If-Match", "schema": {"type": "string"}}},
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ProductResponse"}}}
+          },
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      },
+      "patch": {
+        "summary": "Set and remove attributes of a product",
+        "description": "A JSON Merge Patch of the attributes. Attributes set to null are removed, the others are set and attributes left out are kept.",
+        "operationId": "patchProduct",
+        "tags": ["products"],
+        "parameters": [{"$ref": "#/components/parameters/IfMatch"}],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/merge-patch+json": {"schema": {"$ref": "#/components/schemas/ProductPatch"}},
+            "application/json": {"schema": {"$ref": "#/components/schemas/ProductPatch"}}
+          }
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "412": {"$ref": "#/components/responses/PreconditionFailed"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      },
+      "delete": {
+        "summary": "Delete a product",
+        "description": "The product must be in a deletable state of the lifecycle, by default INACTIVE.",
+        "operationId": "deleteProduct",
+        "tags": ["products"],
+        "parameters": [{"$ref": "#/components/parameters/IfMatch"}],
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "412": {"$ref": "#/components/responses/PreconditionFailed"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/products/{gtin}/history": {
+      "parameters": [{"$ref": "#/components/parameters/Gtin"}],
+      "get": {
+        "summary": "Show the revisions of a product",
+        "description": "Revisions oldest first. The history of a deleted product is kept.",
+        "operationId": "showProductHistory",
+        "tags": ["products"],
+        "parameters": [{"$ref": "#/components/parameters/Head"}],
+        "responses": {
+          "200": {"description": "The revisions", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HistoryResponse"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      }
+    },
+    "/products/attr/{gtin}": {
+      "parameters": [{"$ref": "#/components/parameters/Gtin"}],
+      "put": {
+        "summary": "Replace the attributes of a product",
+        "operationId": "updateProductAttributes",
+        "tags": ["products"],
+        "parameters": [{"$ref": "#/components/parameters/IfMatch"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ProductUpdate"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "412": {"$ref": "#/components/responses/PreconditionFailed"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/products/state/{gtin}": {
+      "parameters": [{"$ref": "#/components/parameters/Gtin"}],
+      "put": {
+        "summary": "Move a product to another state of the lifecycle",
+        "operationId": "updateProductState",
+        "tags": ["products"],
+        "parameters": [{"$ref": "#/components/parameters/IfMatch"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ProductSetState"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "412": {"$ref": "#/components/responses/PreconditionFailed"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/products/owner/{gtin}": {
+      "parameters": [{"$ref": "#/components/parameters/Gtin"}],
+      "put": {
+        "summary": "Transfer a product to a new owner",
+        "description": "Only the current owner of the product can transfer it.",
+        "operationId": "updateProductOwner",
+        "tags": ["products"],
+        "parameters": [{"$ref": "#/components/parameters/IfMatch"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ProductTransfer"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "412": {"$ref": "#/components/responses/PreconditionFailed"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/operations/{id}": {
+      "get": {
+        "summary": "Show the outcome of a change",
+        "description": "Whether the batch of a change is PENDING, COMMITTED or INVALID, with the reason the validator gave.",
+        "operationId": "showOperation",
+        "tags": ["operations"],
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "description": "The id of the batch", "schema": {"type": "string", "pattern": "^[0-9a-f]{128}$"}},
+          {"name": "wait", "in": "query", "description": "Wait up to this long, as a duration such as 30s or a number of seconds, for the batch to leave PENDING. At most 5m.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"description": "The outcome, still PENDING if the wait ran out", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/OperationResponse"}}}},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      }
+    },
+    "/organizations": {
+      "get": {
+        "summary": "List organizations",
+        "operationId": "listOrganizations",
+        "tags": ["organizations"],
+        "parameters": [{"$ref": "#/components/parameters/Head"}],
+        "responses": {
+          "200": {"description": "The organizations, sorted by id", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/OrganizationListResponse"}}}},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      },
+      "post": {
+        "summary": "Create an organization",
+        "description": "The signer of the server becomes the first admin of the organization.",
+        "operationId": "createOrganization",
+        "tags": ["organizations"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Organization"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "409": {"$ref": "#/components/responses/AlreadyExists"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/organizations/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/OrganizationId"}],
+      "get": {
+        "summary": "Show an organization",
+        "operationId": "showOrganization",
+        "tags": ["organizations"],
+        "parameters": [{"$ref": "#/components/parameters/Head"}],
+        "responses": {
+          "200": {"description": "The organization", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/OrganizationResponse"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      },
+      "put": {
+        "summary": "Replace the name and company prefixes of an organization",
+        "operationId": "updateOrganization",
+        "tags": ["organizations"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Organization"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/organizations/{id}/keys/{key}": {
+      "parameters": [
+        {"$ref": "#/components/parameters/OrganizationId"},
+        {"$ref": "#/components/parameters/PublicKey"}
+      ],
+      "put": {
+        "summary": "Add an admin to an organization",
+        "operationId": "addOrganizationKey",
+        "tags": ["organizations"],
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      },
+      "delete": {
+        "summary": "Remove an admin from an organization",
+        "operationId": "removeOrganizationKey",
+        "tags": ["organizations"],
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/agents": {
+      "get": {
+        "summary": "List agents",
+        "operationId": "listAgents",
+        "tags": ["agents"],
+        "parameters": [
+          {"name": "org", "in": "query", "description": "Only the agents of the organization", "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/Head"}
+        ],
+        "responses": {
+          "200": {"description": "The agents, sorted by public key", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/AgentListResponse"}}}},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      },
+      "post": {
+        "summary": "Create an agent",
+        "description": "Only an admin of the organization of the agent can create it.",
+        "operationId": "createAgent",
+        "tags": ["agents"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Agent"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "409": {"$ref": "#/components/responses/AlreadyExists"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/agents/{key}": {
+      "parameters": [{"$ref": "#/components/parameters/PublicKey"}],
+      "put": {
+        "summary": "Replace the roles and active flag of an agent",
+        "operationId": "updateAgent",
+        "tags": ["agents"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Agent"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/schemas": {
+      "get": {
+        "summary": "List schemas",
+        "operationId": "listSchemas",
+        "tags": ["schemas"],
+        "parameters": [{"$ref": "#/components/parameters/Head"}],
+        "responses": {
+          "200": {"description": "The schemas, sorted by name", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SchemaListResponse"}}}},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      },
+      "post": {
+        "summary": "Create a schema",
+        "description": "The schema is owned by the organization named in its owner field. Only an admin of it can create the schema.",
+        "operationId": "createSchema",
+        "tags": ["schemas"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Schema"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "409": {"$ref": "#/components/responses/AlreadyExists"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/schemas/{name}": {
+      "parameters": [{"name": "name", "in": "path", "required": true, "description": "The name of the schema", "schema": {"type": "string"}}],
+      "get": {
+        "summary": "Show a schema",
+        "operationId": "showSchema",
+        "tags": ["schemas"],
+        "parameters": [{"$ref": "#/components/parameters/Head"}],
+        "responses": {
+          "200": {"description": "The schema", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SchemaResponse"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"}
+        }
+      },
+      "put": {
+        "summary": "Replace the properties of a schema",
+        "operationId": "updateSchema",
+        "tags": ["schemas"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Schema"}}}
+        },
+        "security": [{"bearer": []}, {"apiKey": []}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Accepted"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "422": {"$ref": "#/components/responses/ValidationFailed"},
+          "502": {"$ref": "#/components/responses/ValidatorUnavailable"},
+          "503": {"$ref": "#/components/responses/ServiceUnavailable"}
+        }
+      }
+    },
+    "/openapi.json": {
+      "get": {
+        "summary": "This document",
+        "operationId": "showOpenAPI",
+        "tags": ["docs"],
+        "responses": {
+          "200": {"description": "The OpenAPI document of the API", "content": {"application/json": {"schema": {"type": "object"}}}}
+        }
+      }
+    },
+    "/docs": {
+      "get": {
+        "summary": "The documentation of the API, rendered from this document",
+        "operationId": "showDocs",
+        "tags": ["docs"],
+        "responses": {
+          "200": {"description": "The documentation page", "content": {"text/html": {"schema": {"type": "string"}}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "Gtin": {"name": "gtin", "in": "path", "required": true, "description": "GTIN-8, 12, 13 or 14 of the product, padded to GTIN-14", "schema": {"type": "string", "pattern": "^[0-9]{8,14}$"}},
+      "Head": {"name": "head", "in": "query", "description": "Read the state as of this block id instead of the current head", "schema": {"type": "string"}},
+      "IfMatch": {"name": "If-Match", "in": "header", "description": "The ETag of the product, e.g. \"3\". The change is only applied if the product is still at that revision. * or no header applies it to any revision.", "schema": {"type": "string"}},
+      "OrganizationId": {"name": "id", "in": "path", "required": true, "description": "The id of the organization", "schema": {"type": "string"}},
+      "PublicKey": {"name": "key", "in": "path", "required": true, "description": "A public key, as hex", "schema": {"type": "string"}}
+    },
+    "responses": {
+      "Accepted": {
+        "description": "The batch of the change was sent, follow its outcome at Location",
+        "headers": {"Location": {"description": "The operation of the batch, /operations/{id}", "schema": {"type": "string"}}},
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/OperationResponse"}}}
+      },
+      "BadRequest": {"description": "BAD_REQUEST: the body or a parameter is malformed, or the Sawtooth REST API refused it, e.g. an unknown head, with its error in details", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}},
+      "NotFound": {"description": "NOT_FOUND: no such product, or other resource", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}},
+      "AlreadyExists": {"description": "ALREADY_EXISTS: the resource is already in state", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}},
+      "PreconditionFailed": {
+        "description": "PRECONDITION_FAILED: the product is not at the revision of If-Match",
+        "headers": {"ETag": {"description": "The current revision of the product", "schema": {"type": "string"}}},
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      },
+      "ValidationFailed": {"description": "VALIDATION_FAILED: a malformed GTIN or attribute, or attributes breaking the schema, whose violations are in details", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}},
+      "ValidatorUnavailable": {"description": "VALIDATOR_UNAVAILABLE: the Sawtooth REST API or the validator can not be reached, or BAD_GATEWAY: the Sawtooth REST API refused the request, with its error in details", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}},
+      "Unauthorized": {
+        "description": "UNAUTHORIZED: no API key was sent, or one not in the keystore of the server",
+        "headers": {"WWW-Authenticate": {"description": "Bearer realm=\"mdata\"", "schema": {"type": "string"}}},
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      },
+      "ServiceUnavailable": {
+        "description": "READ_ONLY: the server has no keystore to sign changes with, or VALIDATOR_BUSY: the queue of the validator is full, send the change again after Retry-After",
+        "headers": {"Retry-After": {"description": "Seconds to wait before sending a change again, for VALIDATOR_BUSY", "schema": {"type": "integer"}}},
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      }
+    },
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer", "description": "The API key of a principal, as Authorization: Bearer <key>"},
+      "apiKey": {"type": "apiKey", "in": "header", "name": "X-API-Key", "description": "The API key of a principal"}
+    },
+    "schemas": {
+      "Attributes": {
+        "type": "object",
+        "description": "Attributes of a product. Numbers and booleans are stored as text.",
+        "additionalProperties": {"oneOf": [{"type": "string"}, {"type": "number"}, {"type": "boolean"}]}
+      },
+      "Product": {
+        "type": "object",
+        "properties": {
+          "gtin": {"type": "string"},
+          "attributes": {"$ref": "#/components/schemas/Attributes"},
+          "state": {"type": "string"},
+          "owner": {"type": "string", "description": "Public key of the owner"},
+          "reason": {"type": "string", "description": "Why the product is in its state"},
+          "revision": {"type": "integer", "description": "Bumped by every change, the ETag of the product"}
+        }
+      },
+      "ProductCreate": {
+        "type": "object",
+        "required": ["gtin"],
+        "properties": {
+          "gtin": {"type": "string"},
+          "attributes": {"$ref": "#/components/schemas/Attributes"}
+        }
+      },
+      "ProductUpdate": {
+        "type": "object",
+        "properties": {"attributes": {"$ref": "#/components/schemas/Attributes"}}
+      },
+      "ProductPatch": {
+        "type": "object",
+        "required": ["Attributes"],
+        "properties": {
+          "Attributes": {
+            "type": "object",
+            "description": "Attributes to set, or to remove when null",
+            "additionalProperties": {"nullable": true, "oneOf": [{"type": "string"}, {"type": "number"}, {"type": "boolean"}]}
+          }
+        }
+      },
+      "ProductSetState": {
+        "type": "object",
+        "required": ["state"],
+        "properties": {
+          "state": {"type": "string"},
+          "reason": {"type": "string", "description": "Required by the lifecycle for some states, e.g. RECALL"}
+        }
+      },
+      "ProductTransfer": {
+        "type": "object",
+        "required": ["owner"],
+        "properties": {"owner": {"type": "string", "description": "Public key of the new owner"}}
+      },
+      "Op": {
+        "type": "object",
+        "required": ["action", "gtin"],
+        "properties": {
+          "action": {"type": "string", "enum": ["create", "update", "set", "delete"]},
+          "gtin": {"type": "string"},
+          "attributes": {"$ref": "#/components/schemas/Attributes"},
+          "state": {"type": "string"},
+          "reason": {"type": "string"},
+          "expected_revision": {"type": "integer", "description": "Only apply the operation if the product is at this revision"}
+        }
+      },
+      "ProductRevision": {
+        "type": "object",
+        "properties": {
+          "revision": {"type": "integer"},
+          "action": {"type": "string"},
+          "signer": {"type": "string"},
+          "transaction_id": {"type": "string"},
+          "changed_fields": {"type": "array", "items": {"type": "string"}},
+          "previous_values": {"$ref": "#/components/schemas/Attributes"}
+        }
+      },
+      "Organization": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "name": {"type": "string"},
+          "admins": {"type": "array", "items": {"type": "string"}, "readOnly": true},
+          "company_prefixes": {"type": "array", "items": {"type": "string"}}
+        }
+      },
+      "Agent": {
+        "type": "object",
+        "properties": {
+          "public_key": {"type": "string"},
+          "organization_id": {"type": "string"},
+          "active": {"type": "boolean"},
+          "roles": {"type": "array", "items": {"type": "string"}}
+        }
+      },
+      "PropertyDefinition": {
+        "type": "object",
+        "required": ["name", "data_type"],
+        "properties": {
+          "name": {"type": "string"},
+          "data_type": {"type": "string"},
+          "required": {"type": "boolean"},
+          "enum_options": {"type": "array", "items": {"type": "string"}},
+          "units": {"type": "array", "items": {"type": "string"}},
+          "description": {"type": "string"}
+        }
+      },
+      "Schema": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "owner": {"type": "string", "description": "The id of the owning organization"},
+          "properties": {"type": "array", "items": {"$ref": "#/components/schemas/PropertyDefinition"}}
+        }
+      },
+      "BatchResult": {
+        "type": "object",
+        "required": ["batch_id", "status"],
+        "properties": {
+          "batch_id": {"type": "string"},
+          "status": {"type": "string", "enum": ["PENDING", "COMMITTED", "INVALID"]},
+          "transaction_id": {"type": "string", "description": "The invalid transaction of an INVALID batch"},
+          "message": {"type": "string", "description": "The reason the validator gave for an INVALID batch"}
+        }
+      },
+      "Paging": {
+        "type": "object",
+        "properties": {
+          "limit": {"type": "integer"},
+          "start": {"type": "string"},
+          "next": {"type": "string", "description": "The start of the next page, left out on the last page"}
+        }
+      },
+      "ProductResponse": {"type": "object", "properties": {"product": {"$ref": "#/components/schemas/Product"}}},
+      "ProductListResponse": {
+        "type": "object",
+        "properties": {
+          "products": {"type": "array", "items": {"$ref": "#/components/schemas/Product"}},
+          "paging": {"$ref": "#/components/schemas/Paging"}
+        }
+      },
+      "HistoryResponse": {"type": "object", "properties": {"revisions": {"type": "array", "items": {"$ref": "#/components/schemas/ProductRevision"}}}},
+      "OperationResponse": {"type": "object", "properties": {"operation": {"$ref": "#/components/schemas/BatchResult"}}},
+      "OrganizationResponse": {"type": "object", "properties": {"organization": {"$ref": "#/components/schemas/Organization"}}},
+      "OrganizationListResponse": {"type": "object", "properties": {"organizations": {"type": "array", "items": {"$ref": "#/components/schemas/Organization"}}}},
+      "AgentListResponse": {"type": "object", "properties": {"agents": {"type": "array", "items": {"$ref": "#/components/schemas/Agent"}}}},
+      "SchemaResponse": {"type": "object", "properties": {"schema": {"$ref": "#/components/schemas/Schema"}}},
+      "SchemaListResponse": {"type": "object", "properties": {"schemas": {"type": "array", "items": {"$ref": "#/components/schemas/Schema"}}}},
+      "ErrorResponse": {
+        "type": "object",
+        "required": ["error"],
+        "properties": {
+          "error": {
+            "type": "object",
+            "required": ["code", "message"],
+            "properties": {
+              "code": {"type": "string", "enum": ["BAD_REQUEST", "NOT_FOUND", "ALREADY_EXISTS", "PRECONDITION_FAILED", "VALIDATION_FAILED", "INVALID_TRANSACTION", "UNAUTHORIZED", "VALIDATOR_UNAVAILABLE", "BAD_GATEWAY", "VALIDATOR_BUSY", "TIMEOUT", "READ_ONLY"]},
+              "message": {"type": "string"},
+              "details": {"type": "object", "description": "Specific to the code, e.g. the schema and violations of VALIDATION_FAILED"}
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// docsPage renders the OpenAPI document of /openapi.json. It is served whole
+// from the binary, loading nothing from elsewhere, so the documentation works
+// on hosts without internet access.
+const docsPage = `<!DOCTYPE html>
+<html>
+  <head>
+    <title>mdata REST API</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <style>
+      body { font-family: sans-serif; max-width: 60em; margin: 0 auto; padding: 1em; color: #222; }
+      h2 { border-bottom: 1px solid #ccc; padding-bottom: .2em; }
+      .operation { border: 1px solid #ddd; border-radius: 4px; margin: 1em 0; padding: 0 1em; }
+      .method { display: inline-block; min-width: 4em; font-weight: bold; text-transform: uppercase; }
+      table { border-collapse: collapse; margin: .5em 0; }
+      td, th { border: 1px solid #ddd; padding: .2em .5em; text-align: left; vertical-align: top; }
+      code, pre { background: #f5f5f5; }
+      pre { padding: .5em; overflow-x: auto; }
+    </style>
+  </head>
+  <body>
+    <div id="docs">Loading /openapi.json</div>
+    <script>
+      // el creates an element with text or child elements
+      function el(tag, children, attrs) {
+        var node = document.createElement(tag);
+        for (var name in attrs || {}) {
+          node.setAttribute(name, attrs[name]);
+        }
+        [].concat(children || []).forEach(function(child) {
+          node.appendChild(typeof child === "string" ? document.createTextNode(child) : child);
+        });
+        return node;
+      }
+
+      // refName is the last part of a $ref, e.g. Product of #/components/schemas/Product
+      function refName(ref) {
+        return ref.substring(ref.lastIndexOf("/") + 1);
+      }
+
+      // resolve follows the $ref of an object to the components of the document
+      function resolve(doc, obj) {
+        if (!obj || !obj["$ref"]) {
+          return obj;
+        }
+        return ref(doc, obj["$ref"]);
+      }
+
+      function ref(doc, path) {
+        return path.substring(2).split("/").reduce(function(obj, key) { return obj[key]; }, doc);
+      }
+
+      // schemaLink names a schema, linking the components it refers to
+      function schemaLink(schema) {
+        if (!schema) {
+          return "";
+        }
+        if (schema["$ref"]) {
+          return el("a", refName(schema["$ref"]), {href: "#schema-" + refName(schema["$ref"])});
+        }
+        if (schema.type === "array" && schema.items) {
+          return el("span", ["array of ", schemaLink(schema.items)]);
+        }
+        return el("code", schema.type || JSON.stringify(schema));
+      }
+
+      function content(body) {
+        var parts = [];
+        for (var type in (body && body.content) || {}) {
+          parts.push(el("span", [type + ": ", schemaLink(body.content[type].schema)]));
+        }
+        return parts;
+      }
+
+      function operation(doc, path, method, op) {
+        var section = el("div", [
+          el("h3", [el("span", method, {"class": "method"}), el("code", path)]),
+          el("p", op.summary || ""),
+        ], {"class": "operation", id: op.operationId || ""});
+        if (op.description) {
+          section.appendChild(el("p", op.description));
+        }
+        if (op.security) {
+          section.appendChild(el("p", "Needs an API key"));
+        }
+        var params = (op.parameters || []).map(function(p) { return resolve(doc, p); });
+        if (params.length) {
+          section.appendChild(el("table", [el("tr", [el("th", "Parameter"), el("th", "In"), el("th", "Type"), el("th", "Description")])].concat(
+            params.map(function(p) {
+              return el("tr", [el("td", el("code", p.name + (p.required ? " *" : ""))), el("td", p["in"]), el("td", schemaLink(p.schema)), el("td", p.description || "")]);
+            }))));
+        }
+        if (op.requestBody) {
+          section.appendChild(el("p", ["Body: "].concat(content(resolve(doc, op.requestBody)))));
+        }
+        var rows = [el("tr", [el("th", "Status"), el("th", "Description"), el("th", "Body")])];
+        for (var status in op.responses || {}) {
+          var response = resolve(doc, op.responses[status]);
+          rows.push(el("tr", [el("td", status), el("td", response.description || ""), el("td", content(response))]));
+        }
+        section.appendChild(el("table", rows));
+        return section;
+      }
+
+      function render(doc) {
+        var page = el("div", [el("h1", doc.info.title + " " + doc.info.version), el("p", doc.info.description || "")]);
+        page.appendChild(el("p", el("a", "openapi.json", {href: "/openapi.json"})));
+        page.appendChild(el("h2", "Routes"));
+        for (var path in doc.paths) {
+          for (var method in doc.paths[path]) {
+            page.appendChild(operation(doc, path, method, doc.paths[path][method]));
+          }
+        }
+        page.appendChild(el("h2", "Schemas"));
+        var schemas = (doc.components && doc.components.schemas) || {};
+        for (var name in schemas) {
+          page.appendChild(el("h3", name, {id: "schema-" + name}));
+          page.appendChild(el("pre", JSON.stringify(schemas[name], null, 2)));
+        }
+        var docs = document.getElementById("docs");
+        docs.replaceChild(page, docs.firstChild);
+      }
+
+      var request = new XMLHttpRequest();
+      request.open("GET", "/openapi.json");
+      request.onload = function() {
+        try {
+          render(JSON.parse(request.responseText));
+        } catch (e) {
+          document.getElementById("docs").textContent = "Failed to render /openapi.json: " + e;
+        }
+      };
+      request.onerror = function() {
+        document.getElementById("docs").textContent = "Failed to load /openapi.json";
+      };
+      request.send();
+    </script>
+  </body>
+</html>
+`
+
+func showOpenAPI(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, []byte(openAPIDocument))
+}
+
+func showDocs(c echo.Context) error {
+	return c.HTML(http.StatusOK, docsPage)
+}
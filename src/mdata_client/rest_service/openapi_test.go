@@ -0,0 +1,190 @@
+package rest_service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type openAPIParameter struct {
+	Ref  string `json:"$ref"`
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+type openAPIOperation struct {
+	OperationId string                      `json:"operationId"`
+	Parameters  []openAPIParameter          `json:"parameters"`
+	Responses   map[string]*json.RawMessage `json:"responses"`
+}
+
+type openAPIPath struct {
+	Parameters []openAPIParameter           `json:"parameters"`
+	Operations map[string]*openAPIOperation `json:"-"`
+}
+
+func (p *openAPIPath) UnmarshalJSON(b []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	p.Operations = map[string]*openAPIOperation{}
+	for key, value := range fields {
+		if key == "parameters" {
+			if err := json.Unmarshal(value, &p.Parameters); err != nil {
+				return err
+			}
+			continue
+		}
+		operation := &openAPIOperation{}
+		if err := json.Unmarshal(value, operation); err != nil {
+			return err
+		}
+		p.Operations[strings.ToUpper(key)] = operation
+	}
+	return nil
+}
+
+type openAPI struct {
+	Paths      map[string]*openAPIPath `json:"paths"`
+	Components struct {
+		Parameters map[string]openAPIParameter `json:"parameters"`
+	} `json:"components"`
+}
+
+// customMethods are the routes of custom methods, such as /products:batch,
+// which the router serves as one route with the method as parameter
+var customMethods = map[string]string{
+	"/products:batch": "/products:method",
+}
+
+var templateParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// echoPath turns the path template of the document into the path of its route
+func echoPath(path string) string {
+	if route, ok := customMethods[path]; ok {
+		return route
+	}
+	return templateParam.ReplaceAllString(path, ":$1")
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	document := &openAPI{}
+	if !assert.Nil(t, json.Unmarshal([]byte(openAPIDocument), document)) {
+		return
+	}
+
+	documented := []string{}
+	operationIds := map[string]bool{}
+	for path, item := range document.Paths {
+		for method, operation := range item.Operations {
+			documented = append(documented, method+" "+echoPath(path))
+
+			assert.NotEmpty(t, operation.OperationId, "%s %s has no operationId", method, path)
+			assert.False(t, operationIds[operation.OperationId], "%s %s repeats operationId %s", method, path, operation.OperationId)
+			operationIds[operation.OperationId] = true
+
+			succeeds := false
+			for status := range operation.Responses {
+				succeeds = succeeds || strings.HasPrefix(status, "2")
+			}
+			assert.True(t, succeeds, "%s %s has no success response", method, path)
+
+			// Every parameter of the path template must be described
+			described := map[string]bool{}
+			for _, parameter := range append(item.Parameters, operation.Parameters...) {
+				if parameter.Ref != "" {
+					parameter = document.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+				}
+				if parameter.In == "path" {
+					described[parameter.Name] = true
+				}
+			}
+			for _, match := range templateParam.FindAllStringSubmatch(path, -1) {
+				assert.True(t, described[match[1]], "%s %s does not describe path parameter %s", method, path, match[1])
+			}
+		}
+	}
+
+	routes := []string{}
+	for _, route := range NewEcho(testService("", nil)).Routes() {
+		routes = append(routes, route.Method+" "+route.Path)
+	}
+
+	sort.Strings(documented)
+	sort.Strings(routes)
+	assert.Equal(t, routes, documented)
+}
+
+func TestOpenAPIRefs(t *testing.T) {
+	document := map[string]interface{}{}
+	if !assert.Nil(t, json.Unmarshal([]byte(openAPIDocument), &document)) {
+		return
+	}
+
+	var refs func(value interface{})
+	refs = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok {
+				var target interface{} = document
+				for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+					component, _ := target.(map[string]interface{})
+					target = component[part]
+				}
+				assert.NotNil(t, target, "%s does not resolve", ref)
+			}
+			for _, child := range v {
+				refs(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				refs(child)
+			}
+		}
+	}
+	refs(document)
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	e := NewEcho(testService("", nil))
+
+	tests := map[string]struct {
+		inPath         string
+		outContentType string
+		outBody        string
+	}{
+		"document": {
+			inPath:         "/openapi.json",
+			outContentType: "application/json; charset=UTF-8",
+			outBody:        openAPIDocument,
+		},
+		"docs": {
+			inPath:         "/docs",
+			outContentType: "text/html; charset=UTF-8",
+			outBody:        docsPage,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.inPath, nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, test.outContentType, recorder.Header().Get("Content-Type"))
+		assert.Equal(t, test.outBody, recorder.Body.String())
+	}
+}
+
+// The docs page is self-contained, so it renders without internet access
+func TestDocsPageOffline(t *testing.T) {
+	assert.NotRegexp(t, `(src|href)="(https?:)?//`, docsPage)
+	assert.NotContains(t, docsPage, "<link")
+	assert.Contains(t, docsPage, `request.open("GET", "/openapi.json")`)
+}
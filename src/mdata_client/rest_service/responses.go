@@ -0,0 +1,60 @@
+package rest_service
+
+import (
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// The bodies of successful responses. Every body is an object naming what it
+// holds, so fields can be added without breaking callers.
+
+type ProductResponse struct {
+	Product *data.Product `json:"product" xml:"product"`
+}
+
+// ProductListResponse is a page of products, sorted by GTIN
+type ProductListResponse struct {
+	Products []*data.Product `json:"products" xml:"products"`
+	Paging   Paging          `json:"paging" xml:"paging"`
+}
+
+// Paging is the limit and start the page was listed with, and the start of
+// the next page. Next is left out on the last page.
+type Paging struct {
+	Limit uint   `json:"limit,omitempty" xml:"limit,omitempty"`
+	Start string `json:"start,omitempty" xml:"start,omitempty"`
+	Next  string `json:"next,omitempty" xml:"next,omitempty"`
+}
+
+// HistoryResponse is the revisions of a product, oldest first
+type HistoryResponse struct {
+	Revisions []*data.ProductRevision `json:"revisions" xml:"revisions"`
+}
+
+// OperationResponse is the status of the batch a change was sent in
+type OperationResponse struct {
+	Operation *client.BatchResult `json:"operation" xml:"operation"`
+}
+
+type OrganizationResponse struct {
+	Organization *data.Organization `json:"organization" xml:"organization"`
+}
+
+// OrganizationListResponse is the organizations, sorted by id
+type OrganizationListResponse struct {
+	Organizations []*data.Organization `json:"organizations" xml:"organizations"`
+}
+
+// AgentListResponse is the agents, sorted by public key
+type AgentListResponse struct {
+	Agents []*data.Agent `json:"agents" xml:"agents"`
+}
+
+type SchemaResponse struct {
+	Schema *data.Schema `json:"schema" xml:"schema"`
+}
+
+// SchemaListResponse is the schemas, sorted by name
+type SchemaListResponse struct {
+	Schemas []*data.Schema `json:"schemas" xml:"schemas"`
+}
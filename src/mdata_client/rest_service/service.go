@@ -0,0 +1,432 @@
+package rest_service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/constants"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// Service reads and changes mdata state for the REST API through the client.
+// It keeps no state between calls, so it serves concurrent requests. Reads
+// take the block id to read state at, "" for the current head. Writes take
+// the principal that asked for them, and are signed with its key.
+type Service struct {
+	client   client.MdataClient
+	keystore *Keystore
+	// writers sign with the key of each principal, by name
+	writers map[string]client.MdataClient
+	// keystoreErr is why no keystore could be loaded. Reads do not need it,
+	// writes fail with it.
+	keystoreErr error
+	audit       *AuditLog
+}
+
+// NewService returns a service sending transactions to the REST API at url,
+// each signed with the key of the principal it is sent for, and recorded in
+// audit
+func NewService(url string, keystore *Keystore, audit *AuditLog) (*Service, error) {
+	reader, _ := client.NewMdataClient(url, "")
+	writers := make(map[string]client.MdataClient)
+	for _, principal := range keystore.Principals {
+		writer, err := client.NewMdataClient(url, principal.Keyfile)
+		if err != nil {
+			return nil, fmt.Errorf("Principal %v: %v", principal.Name, err)
+		}
+		writers[principal.Name] = writer
+	}
+	return &Service{client: reader, keystore: keystore, writers: writers, audit: audit}, nil
+}
+
+// DefaultService returns a service for the default REST API URL, with the
+// principals of the keystore at keystorePath, by default the one of the user
+// running it. Without a keystore it only serves reads. The batches it sends
+// are recorded in the audit log, and followed through /operations, rather than
+// in the submission log of the CLI.
+func DefaultService(keystorePath string) *Service {
+	readOnly := func(err error) *Service {
+		logger.Warnf("Only serving reads: %v", err)
+		reader, _ := client.NewMdataClient(constants.DEFAULT_URL, "")
+		return &Service{client: reader, keystoreErr: err}
+	}
+
+	if keystorePath == "" {
+		var err error
+		if keystorePath, err = DefaultKeystorePath(); err != nil {
+			return readOnly(err)
+		}
+	}
+	keystore, err := LoadKeystore(keystorePath)
+	if err != nil {
+		return readOnly(err)
+	}
+	audit, err := DefaultAuditLog()
+	if err != nil {
+		return readOnly(fmt.Errorf("No audit log: %v", err))
+	}
+	service, err := NewService(constants.DEFAULT_URL, keystore, audit)
+	if err != nil {
+		return readOnly(err)
+	}
+	return service
+}
+
+// Authenticate returns the principal of an API key
+func (s *Service) Authenticate(apiKey string) (*Principal, error) {
+	if s.keystoreErr != nil {
+		return nil, &ErrReadOnly{s.keystoreErr}
+	}
+	if principal := s.keystore.Authenticate(apiKey); principal != nil {
+		return principal, nil
+	}
+	return nil, &ErrUnauthorized{"Unknown API key"}
+}
+
+// writer returns the client to send the transactions of a principal with
+func (s *Service) writer(principal *Principal) (client.MdataClient, error) {
+	if s.keystoreErr != nil {
+		return client.MdataClient{}, &ErrReadOnly{s.keystoreErr}
+	}
+	if principal == nil {
+		return client.MdataClient{}, &ErrUnauthorized{"An API key is required for changes"}
+	}
+	writer, ok := s.writers[principal.Name]
+	if !ok {
+		return client.MdataClient{}, &ErrUnauthorized{fmt.Sprintf("Unknown principal %v", principal.Name)}
+	}
+	return writer, nil
+}
+
+// record adds a batch a principal submitted to the audit log. The batch is
+// already sent, so a failure to record it is only logged.
+func (s *Service) record(principal *Principal, writer client.MdataClient, action string, resource string, result *client.BatchResult, err error) (*client.BatchResult, error) {
+	if err != nil || s.audit == nil {
+		return result, err
+	}
+	auditErr := s.audit.Append(&AuditRecord{
+		Time:      time.Now().UTC(),
+		Principal: principal.Name,
+		PublicKey: writer.PublicKey(),
+		Action:    action,
+		Resource:  resource,
+		BatchId:   result.BatchId,
+	})
+	if auditErr != nil {
+		logger.Errorf("Failed to record batch %v of %v in audit log: %v", result.BatchId, principal.Name, auditErr)
+	}
+	return result, nil
+}
+
+// ListProducts returns the products matching options. Without a limit or
+// start every page is read, otherwise one page and the start of the next.
+func (s *Service) ListProducts(options client.ListOptions, head string) ([]*data.Product, string, error) {
+	reader := s.client.AtHead(head)
+	if options.Limit > 0 || options.Start != "" {
+		return reader.ListPage(options)
+	}
+
+	products := []*data.Product{}
+	it := reader.Products(options)
+	for it.Next() {
+		products = append(products, it.Product())
+	}
+	if err := it.Err(); err != nil {
+		return nil, "", err
+	}
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].Gtin < products[j].Gtin
+	})
+	return products, "", nil
+}
+
+func (s *Service) ShowProduct(gtin string, head string) (*data.Product, error) {
+	return s.client.AtHead(head).Show(gtin)
+}
+
+func (s *Service) ProductHistory(gtin string, head string) ([]*data.ProductRevision, error) {
+	return s.client.AtHead(head).History(gtin)
+}
+
+// CreateProduct returns ErrAlreadyExists for a product already in state. A
+// product that can not be read is left to the transaction to report.
+func (s *Service) CreateProduct(principal *Principal, gtin string, attributes data.Attributes) (*client.BatchResult, error) {
+	attrs, err := attributes.Strings()
+	if err != nil {
+		return nil, err
+	}
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	if product, err := s.ShowProduct(gtin, ""); err == nil {
+		return nil, &ErrAlreadyExists{fmt.Sprintf("product: %s", product.Gtin)}
+	}
+	result, err := writer.Create(gtin, attrs, 0)
+	return s.record(principal, writer, constants.VERB_CREATE, fmt.Sprintf("product: %s", gtin), result, err)
+}
+
+func (s *Service) UpdateProduct(principal *Principal, gtin string, attributes data.Attributes, expectedRevision uint64) (*client.BatchResult, error) {
+	attrs, err := attributes.Strings()
+	if err != nil {
+		return nil, err
+	}
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.Update(gtin, attrs, expectedRevision, 0)
+	return s.record(principal, writer, constants.VERB_UPDATE, fmt.Sprintf("product: %s", gtin), result, err)
+}
+
+// PatchProduct sets attributes and removes the unset keys, keeping the other
+// attributes of the product
+func (s *Service) PatchProduct(principal *Principal, gtin string, attributes data.Attributes, unset []string, expectedRevision uint64) (*client.BatchResult, error) {
+	attrs, err := attributes.Strings()
+	if err != nil {
+		return nil, err
+	}
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.Patch(gtin, attrs, unset, expectedRevision, 0)
+	return s.record(principal, writer, constants.VERB_PATCH, fmt.Sprintf("product: %s", gtin), result, err)
+}
+
+func (s *Service) SetProductState(principal *Principal, gtin string, state string, reason string, expectedRevision uint64) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.Set(gtin, state, reason, expectedRevision, 0)
+	return s.record(principal, writer, constants.VERB_SET_STATE, fmt.Sprintf("product: %s", gtin), result, err)
+}
+
+func (s *Service) TransferProduct(principal *Principal, gtin string, owner string, expectedRevision uint64) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.Transfer(gtin, owner, expectedRevision, 0)
+	return s.record(principal, writer, constants.VERB_TRANSFER, fmt.Sprintf("product: %s", gtin), result, err)
+}
+
+func (s *Service) DeleteProduct(principal *Principal, gtin string, expectedRevision uint64) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.Delete(gtin, expectedRevision, 0)
+	return s.record(principal, writer, constants.VERB_DELETE, fmt.Sprintf("product: %s", gtin), result, err)
+}
+
+// ApplyOperations applies the operations in order, all of them or none
+func (s *Service) ApplyOperations(principal *Principal, ops []client.Op) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.Apply(ops, 0)
+	return s.record(principal, writer, constants.VERB_BATCH, "products", result, err)
+}
+
+// Operation returns the outcome of the batch a change was sent in, waiting up
+// to wait seconds for it to leave PENDING. A batch the validator does not know
+// is ErrNotFound.
+func (s *Service) Operation(batchId string, wait uint) (*client.BatchResult, error) {
+	results, err := s.client.Status([]string{batchId}, wait)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 || results[0].Status == client.StatusUnknown {
+		return nil, &client.ErrNotFound{Resource: fmt.Sprintf("operation: %s", batchId)}
+	}
+	return results[0], nil
+}
+
+// ListOrganizations returns the organizations sorted by id
+func (s *Service) ListOrganizations(head string) ([]*data.Organization, error) {
+	organizations, err := s.client.AtHead(head).ListOrganizations()
+	if err != nil {
+		return nil, err
+	}
+	organizationMap, err := data.DeserializeOrganizations(organizations)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make([]*data.Organization, 0, len(organizationMap))
+	for _, organization := range organizationMap {
+		sorted = append(sorted, organization)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Id < sorted[j].Id
+	})
+	return sorted, nil
+}
+
+func (s *Service) ShowOrganization(id string, head string) (*data.Organization, error) {
+	organizations, err := s.client.AtHead(head).ShowOrganization(id)
+	if err != nil {
+		return nil, err
+	}
+	organizationMap, err := data.DeserializeOrganizations([]byte(organizations))
+	if err != nil {
+		return nil, err
+	}
+	organization, ok := organizationMap[id]
+	if !ok {
+		return nil, &client.ErrNotFound{Resource: fmt.Sprintf("organization: %s", id)}
+	}
+	return organization, nil
+}
+
+// CreateOrganization makes the signer the first admin of the organization
+func (s *Service) CreateOrganization(principal *Principal, organization *data.Organization) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ShowOrganization(organization.Id, ""); err == nil {
+		return nil, &ErrAlreadyExists{fmt.Sprintf("organization: %s", organization.Id)}
+	}
+	result, err := writer.CreateOrganization(organization.Id, organization.Name, organization.CompanyPrefixes, 0)
+	return s.record(principal, writer, constants.VERB_ORG_CREATE, fmt.Sprintf("organization: %s", organization.Id), result, err)
+}
+
+func (s *Service) UpdateOrganization(principal *Principal, organization *data.Organization) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.UpdateOrganization(organization.Id, organization.Name, organization.CompanyPrefixes, 0)
+	return s.record(principal, writer, constants.VERB_ORG_UPDATE, fmt.Sprintf("organization: %s", organization.Id), result, err)
+}
+
+func (s *Service) AddOrganizationKey(principal *Principal, id string, publicKey string) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.AddOrganizationKey(id, publicKey, 0)
+	return s.record(principal, writer, constants.VERB_ORG_ADD_KEY, fmt.Sprintf("organization: %s", id), result, err)
+}
+
+func (s *Service) RemoveOrganizationKey(principal *Principal, id string, publicKey string) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.RemoveOrganizationKey(id, publicKey, 0)
+	return s.record(principal, writer, constants.VERB_ORG_REMOVE_KEY, fmt.Sprintf("organization: %s", id), result, err)
+}
+
+// ListAgents returns every agent, or those of one organization, sorted by
+// public key
+func (s *Service) ListAgents(orgId string, head string) ([]*data.Agent, error) {
+	agents, err := s.client.AtHead(head).ListAgents()
+	if err != nil {
+		return nil, err
+	}
+	agentMap, err := data.DeserializeAgents(agents)
+	if err != nil {
+		return nil, err
+	}
+	sorted := []*data.Agent{}
+	for _, agent := range agentMap {
+		if orgId == "" || agent.OrganizationId == orgId {
+			sorted = append(sorted, agent)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].PublicKey < sorted[j].PublicKey
+	})
+	return sorted, nil
+}
+
+func (s *Service) CreateAgent(principal *Principal, agent *data.Agent) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.ListAgents(agent.OrganizationId, "")
+	if err == nil {
+		for _, existing := range agents {
+			if existing.PublicKey == agent.PublicKey {
+				return nil, &ErrAlreadyExists{fmt.Sprintf("agent: %s", agent.PublicKey)}
+			}
+		}
+	}
+	result, err := writer.CreateAgent(agent.PublicKey, agent.OrganizationId, agent.Active, agent.Roles, 0)
+	return s.record(principal, writer, constants.VERB_AGENT_CREATE, fmt.Sprintf("agent: %s", agent.PublicKey), result, err)
+}
+
+func (s *Service) UpdateAgent(principal *Principal, agent *data.Agent) (*client.BatchResult, error) {
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.UpdateAgent(agent.PublicKey, agent.Active, agent.Roles, 0)
+	return s.record(principal, writer, constants.VERB_AGENT_UPDATE, fmt.Sprintf("agent: %s", agent.PublicKey), result, err)
+}
+
+// ListSchemas returns the schemas sorted by name
+func (s *Service) ListSchemas(head string) ([]*data.Schema, error) {
+	schemas, err := s.client.AtHead(head).ListSchemas()
+	if err != nil {
+		return nil, err
+	}
+	schemaMap, err := data.DeserializeSchemas(schemas)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make([]*data.Schema, 0, len(schemaMap))
+	for _, schema := range schemaMap {
+		sorted = append(sorted, schema)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted, nil
+}
+
+func (s *Service) ShowSchema(name string, head string) (*data.Schema, error) {
+	schema, err := s.client.AtHead(head).GetSchema(name)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return nil, &client.ErrNotFound{Resource: fmt.Sprintf("schema: %s", name)}
+	}
+	return schema, nil
+}
+
+// CreateSchema checks the definition of the schema before it is submitted
+func (s *Service) CreateSchema(principal *Principal, schema *data.Schema) (*client.BatchResult, error) {
+	if err := schema.CheckDefinition(); err != nil {
+		return nil, err
+	}
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ShowSchema(schema.Name, ""); err == nil {
+		return nil, &ErrAlreadyExists{fmt.Sprintf("schema: %s", schema.Name)}
+	}
+	result, err := writer.CreateSchema(schema, 0)
+	return s.record(principal, writer, constants.VERB_SCHEMA_CREATE, fmt.Sprintf("schema: %s", schema.Name), result, err)
+}
+
+func (s *Service) UpdateSchema(principal *Principal, schema *data.Schema) (*client.BatchResult, error) {
+	if err := schema.CheckDefinition(); err != nil {
+		return nil, err
+	}
+	writer, err := s.writer(principal)
+	if err != nil {
+		return nil, err
+	}
+	result, err := writer.UpdateSchema(schema, 0)
+	return s.record(principal, writer, constants.VERB_SCHEMA_UPDATE, fmt.Sprintf("schema: %s", schema.Name), result, err)
+}
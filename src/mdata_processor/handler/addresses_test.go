@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/batch_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/processor_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/mdata_client/client"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// restApi stands in for the REST API of a validator. The transactions of the
+// batches posted to it are applied to state, signed by signer, with only the
+// inputs and outputs their headers declare. The error of the last one is kept
+// in applyErr. Nothing else is in state, so reads answer 404.
+func restApi(t *testing.T, state testState, signer string, applyErr *error) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/batches" {
+			http.NotFound(w, r)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+		batchList := &batch_pb2.BatchList{}
+		assert.Nil(t, proto.Unmarshal(body, batchList))
+		for _, batch := range batchList.GetBatches() {
+			for _, transaction := range batch.GetTransactions() {
+				header := &transaction_pb2.TransactionHeader{}
+				assert.Nil(t, proto.Unmarshal(transaction.GetHeader(), header))
+				header.SignerPublicKey = signer
+				request := &processor_pb2.TpProcessRequest{
+					Header:    header,
+					Payload:   transaction.GetPayload(),
+					Signature: transaction.GetHeaderSignature(),
+				}
+				*applyErr = apply(request, mdata_state.NewMdState(state.declaredContext(header.GetInputs(), header.GetOutputs())))
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"link": "http://localhost:8008/batch_statuses?id=..."}`))
+	}))
+}
+
+// The inputs and outputs the client declares for every verb cover the state
+// the handler reads and writes for it
+func TestDeclaredAddresses(t *testing.T) {
+	const (
+		newGtin      = "00012345600029"
+		inactiveGtin = "00012345600036"
+	)
+	pallet := &data.Schema{
+		Name:       "pallet",
+		Owner:      "acme",
+		Properties: []data.PropertyDefinition{{Name: "height", DataType: data.TypeDecimal}},
+	}
+
+	tests := map[string]func(c client.MdataClient) (*client.BatchResult, error){
+		"create": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.Create(newGtin, map[string]string{"uom": "cases"}, 0)
+		},
+		"update": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.Update(testGtin, map[string]string{"uom": "lbs"}, 0, 0)
+		},
+		"patch": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.Patch(testGtin, map[string]string{"name": "wings"}, []string{"uom"}, 0, 0)
+		},
+		"set": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.Set(testGtin, "INACTIVE", "SEASONAL", 0, 0)
+		},
+		"delete": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.Delete(inactiveGtin, 0, 0)
+		},
+		"transfer": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.Transfer(testGtin, bob, 0, 0)
+		},
+		"batch": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.Apply([]client.Op{
+				{Action: "create", Gtin: newGtin},
+				{Action: "update", Gtin: testGtin, Attributes: data.Attributes{"uom": "lbs"}},
+				{Action: "set", Gtin: testGtin, State: "INACTIVE"},
+				{Action: "delete", Gtin: inactiveGtin},
+			}, 0)
+		},
+		"orgCreate": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.CreateOrganization("globex", "Globex", []string{"0098765"}, 0)
+		},
+		"orgUpdate": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.UpdateOrganization("acme", "Acme Foods", []string{"0098765"}, 0)
+		},
+		"orgAddKey": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.AddOrganizationKey("acme", bob, 0)
+		},
+		"agentCreate": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.CreateAgent(mallory, "acme", true, []string{data.RoleProductCreate}, 0)
+		},
+		"agentUpdate": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.UpdateAgent(bob, false, nil, 0)
+		},
+		"schemaCreate": func(c client.MdataClient) (*client.BatchResult, error) {
+			return c.CreateSchema(pallet, 0)
+		},
+	}
+
+	for name, send := range tests {
+		t.Logf("Running test case: %s", name)
+		state := newTestState(t, &testOrganization, testAgents, []*data.Product{
+			{Gtin: testGtin, Attributes: data.Attributes{"uom": "cases"}, State: "ACTIVE", Owner: alice},
+			{Gtin: inactiveGtin, State: "INACTIVE", Owner: alice},
+		})
+		var applyErr error
+		server := restApi(t, state, alice, &applyErr)
+		mdataClient, err := client.NewMdataClient(server.URL, "")
+		if assert.Nil(t, err) {
+			_, err = send(mdataClient)
+			assert.Nil(t, err)
+			assert.Nil(t, applyErr)
+		}
+		server.Close()
+	}
+}
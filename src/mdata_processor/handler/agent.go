@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// applyAgent handles the agent actions. Agents are managed by the admins of
+// the organization they act for.
+func applyAgent(mdState *mdata_state.MdState, payload *mdata_payload.MdPayload, signer string) error {
+	agent, err := mdState.GetAgent(payload.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	switch payload.Action {
+	case "agent_create":
+		if agent != nil {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Agent %v already exists", payload.PublicKey)}
+		}
+		agent = &data.Agent{
+			PublicKey:      payload.PublicKey,
+			OrganizationId: payload.OrganizationId,
+		}
+	case "agent_update":
+		if agent == nil {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Agent %v does not exist", payload.PublicKey)}
+		}
+	default:
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Invalid Action : '%v'", payload.Action)}
+	}
+
+	organization, err := mdState.GetOrganization(agent.OrganizationId)
+	if err != nil {
+		return err
+	}
+	if organization == nil {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Organization %v does not exist", agent.OrganizationId)}
+	}
+	if !organization.IsAdmin(signer) {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Signer %v is not an admin of organization %v", signer, organization.Id)}
+	}
+
+	agent.Active = payload.Active
+	agent.Roles = payload.Roles
+	displayAgent(signer, agent)
+	return mdState.SetAgent(agent.PublicKey, agent)
+}
+
+// validateRole only lets admins of the organization, or its active agents
+// holding role, act for it.
+func validateRole(mdState *mdata_state.MdState, organization *data.Organization, signer string, role string) error {
+	if organization.IsAdmin(signer) {
+		return nil
+	}
+	agent, err := mdState.GetAgent(signer)
+	if err != nil {
+		return err
+	}
+	if agent == nil || agent.OrganizationId != organization.Id || !agent.HasRole(role) {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Signer %v does not have role %v for organization %v", signer, role, organization.Id)}
+	}
+	return nil
+}
+
+// validateMember checks a key is an admin or an agent of the organization
+func validateMember(mdState *mdata_state.MdState, organization *data.Organization, publicKey string) error {
+	if organization.IsAdmin(publicKey) {
+		return nil
+	}
+	agent, err := mdState.GetAgent(publicKey)
+	if err != nil {
+		return err
+	}
+	if agent == nil || agent.OrganizationId != organization.Id {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Key %v is neither an admin nor an agent of organization %v", publicKey, organization.Id)}
+	}
+	return nil
+}
+
+// validatePermission checks the signer may change an existing product. It must
+// be the owner of the product and, when an organization owns the product's
+// company prefix, an admin of it or an active agent holding role. Products
+// without an owner are left to the organization's roles alone.
+func validatePermission(mdState *mdata_state.MdState, product *data.Product, signer string, role string) error {
+	organization, err := mdState.GetOrganizationForGtin(product.Gtin)
+	if err != nil {
+		return err
+	}
+	if organization == nil {
+		return validateOwner(product, signer)
+	}
+	if product.Owner != "" {
+		if err := validateOwner(product, signer); err != nil {
+			return err
+		}
+	}
+	return validateRole(mdState, organization, signer, role)
+}
+
+func displayAgent(signer string, agent *data.Agent) {
+	s := fmt.Sprintf("+ Signer %s set agent %s of %s to active=%v roles=%v +", signer[:6], agent.PublicKey[:6], agent.OrganizationId, agent.Active, agent.Roles)
+	sLength := len(s)
+	border := "+" + strings.Repeat("-", sLength-2) + "+"
+	fmt.Println(border)
+	fmt.Println(s)
+	fmt.Println(border)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+var (
+	creator  = "03" + strings.Repeat("d", 64)
+	inactive = "03" + strings.Repeat("e", 64)
+	outsider = "03" + strings.Repeat("f", 64)
+)
+
+// testAgents act for testOrganization, except outsider
+var testAgents = []*data.Agent{
+	{PublicKey: bob, OrganizationId: "acme", Active: true, Roles: []string{data.RoleProductUpdate, data.RoleProductDelete}},
+	{PublicKey: creator, OrganizationId: "acme", Active: true, Roles: []string{data.RoleProductCreate, data.RoleProductLifecycle}},
+	{PublicKey: inactive, OrganizationId: "acme", Active: false, Roles: []string{data.RoleProductUpdate}},
+	{PublicKey: outsider, OrganizationId: "other", Active: true, Roles: []string{data.RoleProductUpdate}},
+}
+
+func TestProductPermission(t *testing.T) {
+	update := &mdata_payload.MdPayload{Action: "update", Gtin: testGtin, Attributes: data.Attributes{"uom": "cases"}}
+
+	tests := map[string]struct {
+		inOwner   string
+		inPayload *mdata_payload.MdPayload
+		inSigner  string
+		outError  string
+		outOwner  string
+	}{
+		"adminCreates": {
+			inPayload: &mdata_payload.MdPayload{Action: "create", Gtin: testGtin},
+			inSigner:  alice,
+			outOwner:  alice,
+		},
+		"agentCreates": {
+			inPayload: &mdata_payload.MdPayload{Action: "create", Gtin: testGtin},
+			inSigner:  creator,
+			outOwner:  creator,
+		},
+		"agentCreatesWithoutRole": {
+			inPayload: &mdata_payload.MdPayload{Action: "create", Gtin: testGtin},
+			inSigner:  bob,
+			outError:  "Signer " + bob + " does not have role product.create for organization acme",
+		},
+		"adminOwnerUpdates": {
+			inOwner:   alice,
+			inPayload: update,
+			inSigner:  alice,
+			outOwner:  alice,
+		},
+		"agentOwnerUpdates": {
+			inOwner:   bob,
+			inPayload: update,
+			inSigner:  bob,
+			outOwner:  bob,
+		},
+		"agentOwnerWithoutRole": {
+			inOwner:   bob,
+			inPayload: &mdata_payload.MdPayload{Action: "set", Gtin: testGtin, State: "INACTIVE"},
+			inSigner:  bob,
+			outError:  "Signer " + bob + " does not have role product.lifecycle for organization acme",
+			outOwner:  bob,
+		},
+		"roleWithoutOwnership": {
+			inOwner:   alice,
+			inPayload: update,
+			inSigner:  bob,
+			outError:  "Signer " + bob + " is not the owner of product " + testGtin,
+			outOwner:  alice,
+		},
+		"adminWithoutOwnership": {
+			inOwner:   bob,
+			inPayload: update,
+			inSigner:  alice,
+			outError:  "Signer " + alice + " is not the owner of product " + testGtin,
+			outOwner:  bob,
+		},
+		"inactiveAgentOwner": {
+			inOwner:   inactive,
+			inPayload: update,
+			inSigner:  inactive,
+			outError:  "Signer " + inactive + " does not have role product.update for organization acme",
+			outOwner:  inactive,
+		},
+		"agentOfOtherOrganization": {
+			inOwner:   outsider,
+			inPayload: update,
+			inSigner:  outsider,
+			outError:  "Signer " + outsider + " does not have role product.update for organization acme",
+			outOwner:  outsider,
+		},
+		"ownerlessWithRole": {
+			inPayload: update,
+			inSigner:  bob,
+		},
+		"ownerlessWithoutRole": {
+			inPayload: update,
+			inSigner:  mallory,
+			outError:  "Signer " + mallory + " does not have role product.update for organization acme",
+		},
+		"ownerTransfersToAgent": {
+			inOwner:   bob,
+			inPayload: &mdata_payload.MdPayload{Action: "transfer", Gtin: testGtin, NewOwner: creator},
+			inSigner:  bob,
+			outOwner:  creator,
+		},
+		"ownerTransfersOutside": {
+			inOwner:   bob,
+			inPayload: &mdata_payload.MdPayload{Action: "transfer", Gtin: testGtin, NewOwner: outsider},
+			inSigner:  bob,
+			outError:  "Key " + outsider + " is neither an admin nor an agent of organization acme",
+			outOwner:  bob,
+		},
+		"ownerTransfersWithoutRole": {
+			inOwner:   inactive,
+			inPayload: &mdata_payload.MdPayload{Action: "transfer", Gtin: testGtin, NewOwner: bob},
+			inSigner:  inactive,
+			outError:  "Signer " + inactive + " does not have role product.update for organization acme",
+			outOwner:  inactive,
+		},
+		"adminTransfersAgentProduct": {
+			inOwner:   bob,
+			inPayload: &mdata_payload.MdPayload{Action: "transfer", Gtin: testGtin, NewOwner: alice},
+			inSigner:  alice,
+			outOwner:  alice,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		products := []*data.Product{}
+		if test.inPayload.Action != "create" {
+			products = append(products, &data.Product{Gtin: testGtin, State: "ACTIVE", Owner: test.inOwner})
+		}
+		state := newTestState(t, &testOrganization, testAgents, products)
+		err := applyProduct(mdata_state.NewMdState(state.context()), test.inPayload, test.inSigner, "t1")
+		assertInvalid(t, test.outError, err)
+
+		product, err := mdata_state.NewMdState(state.context()).GetProduct(testGtin)
+		assert.Nil(t, err)
+		if test.inPayload.Action == "create" && test.outError != "" {
+			assert.Nil(t, product)
+		} else if assert.NotNil(t, product) {
+			assert.Equal(t, test.outOwner, product.Owner)
+		}
+	}
+}
+
+// TestTransferWithinOrganization follows a product handed from an admin to an
+// agent: only the new owner can change it afterwards
+func TestTransferWithinOrganization(t *testing.T) {
+	state := newTestState(t, &testOrganization, testAgents, []*data.Product{
+		{Gtin: testGtin, State: "ACTIVE", Owner: alice},
+	})
+	apply := func(payload *mdata_payload.MdPayload, signer string) error {
+		return applyProduct(mdata_state.NewMdState(state.context()), payload, signer, "t1")
+	}
+	update := &mdata_payload.MdPayload{Action: "update", Gtin: testGtin, Attributes: data.Attributes{"uom": "cases"}}
+
+	assert.Nil(t, apply(&mdata_payload.MdPayload{Action: "transfer", Gtin: testGtin, NewOwner: bob}, alice))
+	assertInvalid(t, "Signer "+alice+" is not the owner of product "+testGtin, apply(update, alice))
+	assert.Nil(t, apply(update, bob))
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+)
+
+// applyBatch applies the operations of a batch in order, each seeing the
+// products left by the ones before it. The first failing operation makes the
+// whole transaction invalid, and the validator then discards the state set by
+// the operations already applied, so either all of them are applied or none.
+func applyBatch(mdState *mdata_state.MdState, payload *mdata_payload.MdPayload, signer string, transactionId string) error {
+	for i, operation := range payload.Operations {
+		if err := applyProduct(mdState, operation, signer, transactionId); err != nil {
+			if invalid, ok := err.(*processor.InvalidTransactionError); ok {
+				return &processor.InvalidTransactionError{
+					Msg: fmt.Sprintf("Operation %v (%v %v): %v", i+1, operation.Action, operation.Gtin, invalid.Msg)}
+			}
+			return err
+		}
+	}
+	return nil
+}
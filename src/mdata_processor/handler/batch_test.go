@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// applyTransaction applies a batch as the validator does: on a copy of the
+// state, kept only if the transaction is valid
+func (s testState) applyTransaction(payload *mdata_payload.MdPayload, signer string) error {
+	scratch := testState{}
+	for address, entry := range s {
+		scratch[address] = entry
+	}
+	err := applyBatch(mdata_state.NewMdState(scratch.context()), payload, signer, "t1")
+	if err != nil {
+		return err
+	}
+	for address := range s {
+		if _, ok := scratch[address]; !ok {
+			delete(s, address)
+		}
+	}
+	for address, entry := range scratch {
+		s[address] = entry
+	}
+	return nil
+}
+
+func TestApplyBatch(t *testing.T) {
+	const otherGtin = "00012345600029"
+
+	tests := map[string]struct {
+		inOperations []*mdata_payload.MdPayload
+		outError     string
+		outStates    map[string]string
+	}{
+		"createThenSet": {
+			inOperations: []*mdata_payload.MdPayload{
+				{Action: "create", Gtin: otherGtin, Attributes: data.Attributes{"uom": "cases"}},
+				{Action: "set", Gtin: otherGtin, State: "INACTIVE"},
+			},
+			outStates: map[string]string{testGtin: "ACTIVE", otherGtin: "INACTIVE"},
+		},
+		"setThenDelete": {
+			inOperations: []*mdata_payload.MdPayload{
+				{Action: "set", Gtin: testGtin, State: "INACTIVE"},
+				{Action: "delete", Gtin: testGtin},
+			},
+			outStates: map[string]string{testGtin: "", otherGtin: ""},
+		},
+		"createTwice": {
+			inOperations: []*mdata_payload.MdPayload{
+				{Action: "create", Gtin: otherGtin},
+				{Action: "create", Gtin: otherGtin},
+			},
+			outError:  "Operation 2 (create " + otherGtin + "): Product already exists",
+			outStates: map[string]string{testGtin: "ACTIVE", otherGtin: ""},
+		},
+		"lastOperationInvalid": {
+			inOperations: []*mdata_payload.MdPayload{
+				{Action: "update", Gtin: testGtin, Attributes: data.Attributes{"uom": "lbs"}},
+				{Action: "create", Gtin: otherGtin},
+				{Action: "delete", Gtin: testGtin},
+			},
+			outError:  "Operation 3 (delete " + testGtin + "): Delete requires a product in one of the states INACTIVE. Please change its state with `mdata set <GTIN> <state>`.",
+			outStates: map[string]string{testGtin: "ACTIVE", otherGtin: ""},
+		},
+		"staleRevision": {
+			inOperations: []*mdata_payload.MdPayload{
+				{Action: "update", Gtin: testGtin, Attributes: data.Attributes{"uom": "lbs"}, ExpectedRevision: 1},
+				{Action: "patch", Gtin: testGtin, Attributes: data.Attributes{"name": "wings"}, ExpectedRevision: 1},
+			},
+			outError:  "Operation 2 (patch " + testGtin + "): Product " + testGtin + " is at revision 2, not the expected revision 1",
+			outStates: map[string]string{testGtin: "ACTIVE", otherGtin: ""},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		state := newTestState(t, &testOrganization, nil, []*data.Product{
+			{Gtin: testGtin, Attributes: data.Attributes{"uom": "cases"}, State: "ACTIVE", Owner: alice, Revision: 1},
+		})
+		payload := &mdata_payload.MdPayload{Action: "batch", Operations: test.inOperations}
+		assertInvalid(t, test.outError, state.applyTransaction(payload, alice))
+
+		mdState := mdata_state.NewMdState(state.context())
+		for gtin, productState := range test.outStates {
+			product, err := mdState.GetProduct(gtin)
+			assert.Nil(t, err)
+			if productState == "" {
+				assert.Nil(t, product, "product %v", gtin)
+			} else if assert.NotNil(t, product, "product %v", gtin) {
+				assert.Equal(t, productState, product.State)
+			}
+		}
+		if test.outError != "" {
+			product, _ := mdState.GetProduct(testGtin)
+			if assert.NotNil(t, product) {
+				assert.Equal(t, data.Attributes{"uom": "cases"}, product.Attributes)
+				assert.Equal(t, uint64(1), product.Revision)
+			}
+		}
+	}
+}
@@ -42,7 +42,10 @@ func (self *MdHandler) FamilyName() string {
 
 func (self *MdHandler) FamilyVersions() []string {
 	// Versions allow you to correlate deployments among all the nodes in your  network. You want all the nodes using the same version
-	return []string{"1.0"}
+	// 1.0 payloads are comma separated strings, 2.0 payloads are MdataPayload protobuf messages.
+	// 1.0 stays registered so nodes can be upgraded before clients switch to 2.0. Its transactions only
+	// list the product address, so they are applied with the rules of 1.0, see applyLegacy.
+	return []string{"1.0", "2.0"}
 }
 
 func (self *MdHandler) Namespaces() []string {
@@ -71,6 +74,15 @@ func (self *MdHandler) Apply(request *processor_pb2.TpProcessRequest, context *p
 	// 	contextId  string
 	// }
 
+	// Context provides an abstract interface for getting and setting validator
+	// state. All validator interactions by a handler should be through a Context
+	// instance. Currently, the Context class is NOT thread-safe and Context classes
+	// may not share the same messaging.Connection object.
+	return apply(request, mdata_state.NewMdState(context))
+}
+
+// apply applies a transaction to the state
+func apply(request *processor_pb2.TpProcessRequest, mdState *mdata_state.MdState) error {
 	// The master data organization is defined as the signer of the transaction, so we unpack
 	// the transaction header to obtain the signer's public key, which will be
 	// used as the organization's identity.
@@ -80,66 +92,137 @@ func (self *MdHandler) Apply(request *processor_pb2.TpProcessRequest, context *p
 	// The payload is sent to the transaction processor as bytes (just as it
 	// appears in the transaction constructed by the transactor).  We unpack
 	// the payload into an MdPayload struct so we can access its fields.
-	payload, err := mdata_payload.FromBytes(request.GetPayload())
+	if header.GetFamilyVersion() == "1.0" {
+		payload, err := mdata_payload.FromBytes(request.GetPayload())
+		if err != nil {
+			return err
+		}
+		return applyLegacy(mdState, payload, signer)
+	}
+	if header.GetFamilyVersion() != "2.0" {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Unsupported family version %v", header.GetFamilyVersion())}
+	}
+	payload, err := mdata_payload.FromProtobuf(request.GetPayload())
 	if err != nil {
 		return err
 	}
 
-	// Context provides an abstract interface for getting and setting validator
-	// state. All validator interactions by a handler should be through a Context
-	// instance. Currently, the Context class is NOT thread-safe and Context classes
-	// may not share the same messaging.Connection object.
-	mdState := mdata_state.NewMdState(context)
+	// The transaction id, its header signature, is recorded in the history of
+	// the products it changes
+	transactionId := request.GetSignature()
 
 	logger.Debugf("mdata txn %v: signer %v: payload: Action='%v', Gtin='%v', Attributes='%v'",
 		request.GetSignature(), signer, payload.Action, payload.Gtin, payload.Attributes)
 
+	if payload.IsOrganizationAction() {
+		return applyOrganization(mdState, payload, signer)
+	}
+	if payload.IsAgentAction() {
+		return applyAgent(mdState, payload, signer)
+	}
+	if payload.IsSchemaAction() {
+		return applySchema(mdState, payload, signer)
+	}
+
+	if payload.Action == "batch" {
+		return applyBatch(mdState, payload, signer, transactionId)
+	}
+	return applyProduct(mdState, payload, signer, transactionId)
+}
+
+// applyProduct handles the product actions
+func applyProduct(mdState *mdata_state.MdState, payload *mdata_payload.MdPayload, signer string, transactionId string) error {
+	if err := validateRevision(mdState, payload); err != nil {
+		return err
+	}
+
 	switch payload.Action {
 	case "create":
-		err := validateCreate(mdState, payload.Gtin)
+		err := validateCreate(mdState, payload.Gtin, signer)
+		if err != nil {
+			return err
+		}
+		attributes, err := validateAttributes(mdState, payload.Attributes)
+		if err != nil {
+			return err
+		}
+		lifecycle, err := mdState.GetLifecycle()
 		if err != nil {
 			return err
 		}
 		product := &data.Product{
 			Gtin:       payload.Gtin,
-			Attributes: data.DeserializeAttributes(payload.Attributes),
-			State:      "ACTIVE",
+			Attributes: attributes,
+			State:      lifecycle.Initial,
+			Owner:      signer,
 		}
 		displayCreate(payload, signer)
-		return mdState.SetProduct(payload.Gtin, product)
+		return saveProduct(mdState, payload.Action, signer, transactionId, nil, product)
 	case "delete":
-		err := validateDelete(mdState, payload.Gtin)
+		err := validateDelete(mdState, payload.Gtin, signer)
 		if err != nil {
 			return err
 		}
+		product, _ := mdState.GetProduct(payload.Gtin) //err is not needed here, as it is checked in the validateDelete function
 		displayDelete(signer, payload.Gtin)
-		return mdState.DeleteProduct(payload.Gtin)
+		return removeProduct(mdState, payload.Action, signer, transactionId, product)
 	case "update":
-		err := validateUpdate(mdState, payload.Gtin)
+		err := validateUpdate(mdState, payload.Gtin, signer)
+		if err != nil {
+			return err
+		}
+		attributes, err := validateAttributes(mdState, payload.Attributes)
 		if err != nil {
 			return err
 		}
 		product, _ := mdState.GetProduct(payload.Gtin) //err is not needed here, as it is checked in the validateUpdate function
-		product.Attributes = data.DeserializeAttributes(payload.Attributes)
-		product.State = "ACTIVE"
+		previous := product.Copy()
+		product.Attributes = attributes
 		displayUpdate(payload, signer, product)
-		return mdState.SetProduct(payload.Gtin, product)
+		return saveProduct(mdState, payload.Action, signer, transactionId, previous, product)
+	case "patch":
+		err := validateUpdate(mdState, payload.Gtin, signer)
+		if err != nil {
+			return err
+		}
+		product, _ := mdState.GetProduct(payload.Gtin) //err is not needed here, as it is checked in the validateUpdate function
+		previous := product.Copy()
+		attributes, err := validateAttributes(mdState, patchAttributes(product.Attributes, payload))
+		if err != nil {
+			return err
+		}
+		product.Attributes = attributes
+		displayPatch(payload, signer, product)
+		return saveProduct(mdState, payload.Action, signer, transactionId, previous, product)
 	case "set":
-		err := validateStateChange(mdState, payload.Gtin, payload.State)
+		err := validateStateChange(mdState, payload.Gtin, payload.State, signer)
 		if err != nil {
 			return err
 		}
-		product, _ := mdState.GetProduct(payload.Gtin) //err is not needed here, as it is checked in the validateDeactivate function
+		product, _ := mdState.GetProduct(payload.Gtin) //err is not needed here, as it is checked in the validateStateChange function
+		previous := product.Copy()
 		product.State = payload.State
+		product.Reason = payload.Reason
 		displayStateChange(payload, signer, product)
-		return mdState.SetProduct(payload.Gtin, product)
+		return saveProduct(mdState, payload.Action, signer, transactionId, previous, product)
+	case "transfer":
+		err := validateTransfer(mdState, payload.Gtin, payload.NewOwner, signer)
+		if err != nil {
+			return err
+		}
+		product, _ := mdState.GetProduct(payload.Gtin) //err is not needed here, as it is checked in the validateTransfer function
+		previous := product.Copy()
+		product.Owner = payload.NewOwner
+		displayTransfer(signer, product)
+		return saveProduct(mdState, payload.Action, signer, transactionId, previous, product)
 	default:
 		return &processor.InvalidTransactionError{
 			Msg: fmt.Sprintf("Invalid Action : '%v'", payload.Action)}
 	}
 }
 
-func validateCreate(mdState *mdata_state.MdState, gtin string) error {
+func validateCreate(mdState *mdata_state.MdState, gtin string, signer string) error {
 	product, err := mdState.GetProduct(gtin)
 	if err != nil {
 		return err
@@ -148,7 +231,7 @@ func validateCreate(mdState *mdata_state.MdState, gtin string) error {
 		return &processor.InvalidTransactionError{Msg: "Product already exists"}
 	}
 
-	return nil
+	return validateCompanyPrefix(mdState, gtin, signer)
 }
 
 func displayCreate(payload *mdata_payload.MdPayload, signer string) {
@@ -160,7 +243,24 @@ func displayCreate(payload *mdata_payload.MdPayload, signer string) {
 	fmt.Println(border)
 }
 
-func validateUpdate(mdState *mdata_state.MdState, gtin string) error {
+// validateOwner only lets the owner of a product change it. Products created
+// before owners were recorded have no owner and fail it: where no organization
+// owns their company prefix nobody can change them. Within an organization,
+// validatePermission skips it for them and lets the role holders change them,
+// while an owned product needs both its owner and the role.
+func validateOwner(product *data.Product, signer string) error {
+	if product.Owner == "" {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Product %v has no owner", product.Gtin)}
+	}
+	if product.Owner != signer {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Signer %v is not the owner of product %v", signer, product.Gtin)}
+	}
+	return nil
+}
+
+func validateUpdate(mdState *mdata_state.MdState, gtin string, signer string) error {
 	product, err := mdState.GetProduct(gtin)
 	if err != nil {
 		return err
@@ -168,7 +268,7 @@ func validateUpdate(mdState *mdata_state.MdState, gtin string) error {
 	if product == nil {
 		return &processor.InvalidTransactionError{Msg: "Update requires an existing product"}
 	}
-	return nil
+	return validatePermission(mdState, product, signer, data.RoleProductUpdate)
 }
 
 func displayUpdate(payload *mdata_payload.MdPayload, signer string, product *data.Product) {
@@ -180,6 +280,31 @@ func displayUpdate(payload *mdata_payload.MdPayload, signer string, product *dat
 	fmt.Println(border)
 }
 
+// patchAttributes returns a copy of attributes with the payload's attributes
+// set and its unset keys removed. Unsetting a missing key does nothing.
+func patchAttributes(attributes data.Attributes, payload *mdata_payload.MdPayload) data.Attributes {
+	patched := data.Attributes{}
+	for k, v := range attributes {
+		patched[k] = v
+	}
+	for k, v := range payload.Attributes {
+		patched[k] = v
+	}
+	for _, k := range payload.UnsetKeys {
+		delete(patched, k)
+	}
+	return patched
+}
+
+func displayPatch(payload *mdata_payload.MdPayload, signer string, product *data.Product) {
+	s := fmt.Sprintf("+ Signer %s patched product %s setting %s unsetting %v", signer[:6], product.Gtin, payload.Attributes, payload.UnsetKeys)
+	sLength := len(s)
+	border := "+" + strings.Repeat("-", sLength-2) + "+"
+	fmt.Println(border)
+	fmt.Println(s)
+	fmt.Println(border)
+}
+
 func displayDelete(signer string, gtin string) {
 	s := fmt.Sprintf("+ Signer %s deleted product %s", signer[:6], gtin)
 	sLength := len(s)
@@ -189,7 +314,8 @@ func displayDelete(signer string, gtin string) {
 	fmt.Println(border)
 }
 
-func validateStateChange(mdState *mdata_state.MdState, gtin string, action string) error {
+// validateStateChange only allows the transitions of the product lifecycle
+func validateStateChange(mdState *mdata_state.MdState, gtin string, state string, signer string) error {
 	product, err := mdState.GetProduct(gtin)
 	if err != nil {
 		return err
@@ -197,12 +323,27 @@ func validateStateChange(mdState *mdata_state.MdState, gtin string, action strin
 	if product == nil {
 		return &processor.InvalidTransactionError{Msg: "Set state requires an existing product"}
 	}
+	if err := validatePermission(mdState, product, signer, data.RoleProductLifecycle); err != nil {
+		return err
+	}
 
+	lifecycle, err := mdState.GetLifecycle()
+	if err != nil {
+		return err
+	}
+	if !lifecycle.IsState(state) {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Invalid state (state must be one of %v), GOT: %v", strings.Join(lifecycle.States(), ", "), state)}
+	}
+	if !lifecycle.CanTransition(product.State, state) {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Product %v can not change from %v to %v", gtin, product.State, state)}
+	}
 	return nil
 }
 
 func displayStateChange(payload *mdata_payload.MdPayload, signer string, product *data.Product) {
-	s := fmt.Sprintf("+ Signer %s updated product %s state to %s+", signer[:6], product.Gtin, product.State)
+	s := fmt.Sprintf("+ Signer %s updated product %s state to %s %s+", signer[:6], product.Gtin, product.State, product.Reason)
 	sLength := len(s)
 	border := "+" + strings.Repeat("-", sLength-2) + "+"
 	fmt.Println(border)
@@ -210,7 +351,7 @@ func displayStateChange(payload *mdata_payload.MdPayload, signer string, product
 	fmt.Println(border)
 }
 
-func validateDelete(mdState *mdata_state.MdState, gtin string) error {
+func validateDelete(mdState *mdata_state.MdState, gtin string, signer string) error {
 	product, err := mdState.GetProduct(gtin)
 	if err != nil {
 		return err
@@ -218,8 +359,59 @@ func validateDelete(mdState *mdata_state.MdState, gtin string) error {
 	if product == nil {
 		return &processor.InvalidTransactionError{Msg: "Delete requires an existing product"}
 	}
-	if product.State != "INACTIVE" {
-		return &processor.InvalidTransactionError{Msg: "Delete requires an INACTIVE product. Please deactivate the product with `mdata set <GTIN> INACTIVE`."}
+	if err := validatePermission(mdState, product, signer, data.RoleProductDelete); err != nil {
+		return err
+	}
+	lifecycle, err := mdState.GetLifecycle()
+	if err != nil {
+		return err
+	}
+	if !lifecycle.IsDeletable(product.State) {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Delete requires a product in one of the states %v. Please change its state with `mdata set <GTIN> <state>`.", strings.Join(lifecycle.Deletable, ", "))}
 	}
 	return nil
 }
+
+// validateTransfer lets the owner of a product hand it to another key. Within
+// an organization the owner must also hold product.update, and the new owner
+// must be an admin or agent of it, as nobody else could change the product.
+// Admins of the organization can hand over any of its products, which is how
+// products without an owner are given one.
+func validateTransfer(mdState *mdata_state.MdState, gtin string, newOwner string, signer string) error {
+	product, err := mdState.GetProduct(gtin)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return &processor.InvalidTransactionError{Msg: "Transfer requires an existing product"}
+	}
+
+	organization, err := mdState.GetOrganizationForGtin(gtin)
+	if err != nil {
+		return err
+	}
+	if organization != nil && organization.IsAdmin(signer) {
+		return validateMember(mdState, organization, newOwner)
+	}
+	if product.Owner == "" {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Product %v has no owner, only an admin of its organization can give it one", gtin)}
+	}
+	if err := validatePermission(mdState, product, signer, data.RoleProductUpdate); err != nil {
+		return err
+	}
+	if organization == nil {
+		return nil
+	}
+	return validateMember(mdState, organization, newOwner)
+}
+
+func displayTransfer(signer string, product *data.Product) {
+	s := fmt.Sprintf("+ Signer %s transferred product %s to %s +", signer[:6], product.Gtin, product.Owner[:6])
+	sLength := len(s)
+	border := "+" + strings.Repeat("-", sLength-2) + "+"
+	fmt.Println(border)
+	fmt.Println(s)
+	fmt.Println(border)
+}
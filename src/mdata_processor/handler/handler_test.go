@@ -0,0 +1,521 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/processor_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/setting_pb2"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/transaction_pb2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/payload_pb2"
+)
+
+var (
+	alice   = "02" + strings.Repeat("a", 64)
+	bob     = "02" + strings.Repeat("b", 64)
+	mallory = "02" + strings.Repeat("c", 64)
+)
+
+// testGtin is under the company prefix of testOrganization, legacyGtin under
+// one no organization owns
+const (
+	testGtin   = "00012345600012"
+	legacyGtin = "00098765400012"
+)
+
+var testOrganization = data.Organization{
+	Id:              "acme",
+	Name:            "Acme Foods",
+	Admins:          []string{alice},
+	CompanyPrefixes: []string{"0012345"},
+}
+
+// testState is the state of a validator, as entries by address
+type testState map[string][]byte
+
+// context returns a MockContext reading and writing the entries
+func (s testState) context() *mdata_state.MockContext {
+	return s.declaredContext(nil, nil)
+}
+
+// declaredContext returns a MockContext that, like the validator, fails to
+// read entries outside the inputs of a transaction, and to write entries
+// outside its outputs. Both are addresses or address prefixes, nil declares
+// every address.
+func (s testState) declaredContext(inputs []string, outputs []string) *mdata_state.MockContext {
+	authorizer := func(declared []string) func([]string) error {
+		return func(addresses []string) error {
+			if declared == nil {
+				return nil
+			}
+			for _, address := range addresses {
+				if !isDeclared(declared, address) {
+					return fmt.Errorf("Tried to access unauthorized address %v", address)
+				}
+			}
+			return nil
+		}
+	}
+	authorizeRead, authorize := authorizer(inputs), authorizer(outputs)
+	entryAddresses := func(entries map[string][]byte) []string {
+		addresses := []string{}
+		for address := range entries {
+			addresses = append(addresses, address)
+		}
+		return addresses
+	}
+
+	context := &mdata_state.MockContext{}
+	context.On("GetState", mock.Anything).Return(func(addresses []string) map[string][]byte {
+		entries := map[string][]byte{}
+		if authorizeRead(addresses) != nil {
+			return entries
+		}
+		for _, address := range addresses {
+			if entry, ok := s[address]; ok {
+				entries[address] = entry
+			}
+		}
+		return entries
+	}, authorizeRead)
+	context.On("SetState", mock.Anything).Return(func(entries map[string][]byte) []string {
+		if authorize(entryAddresses(entries)) != nil {
+			return nil
+		}
+		for address, entry := range entries {
+			s[address] = entry
+		}
+		return entryAddresses(entries)
+	}, func(entries map[string][]byte) error {
+		return authorize(entryAddresses(entries))
+	})
+	context.On("DeleteState", mock.Anything).Return(func(addresses []string) []string {
+		if authorize(addresses) != nil {
+			return nil
+		}
+		for _, address := range addresses {
+			delete(s, address)
+		}
+		return addresses
+	}, authorize)
+	context.On("AddEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	context.On("AddReceiptData", mock.Anything).Return(nil)
+	return context
+}
+
+func isDeclared(declared []string, address string) bool {
+	for _, prefix := range declared {
+		if strings.HasPrefix(address, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTestState returns the state holding organization, agents and products
+func newTestState(t *testing.T, organization *data.Organization, agents []*data.Agent, products []*data.Product) testState {
+	state := testState{}
+	mdState := mdata_state.NewMdState(state.context())
+	if organization != nil {
+		organization := *organization
+		assert.Nil(t, mdState.SetOrganization(organization.Id, &organization))
+		for _, prefix := range organization.CompanyPrefixes {
+			assert.Nil(t, mdState.SetCompanyPrefixOwner(prefix, organization.Id))
+		}
+	}
+	for _, agent := range agents {
+		assert.Nil(t, mdState.SetAgent(agent.PublicKey, agent))
+	}
+	for _, product := range products {
+		assert.Nil(t, mdState.SetProduct(product.Gtin, product.Copy()))
+	}
+	return state
+}
+
+// assertInvalid checks err is an invalid transaction with message, or nil for
+// an empty message
+func assertInvalid(t *testing.T, message string, err error) {
+	if message == "" {
+		assert.Nil(t, err)
+		return
+	}
+	if invalid, ok := err.(*processor.InvalidTransactionError); assert.True(t, ok, "%v is not an invalid transaction", err) {
+		assert.Equal(t, message, invalid.Msg)
+	}
+}
+
+func TestProductOwner(t *testing.T) {
+	tests := map[string]struct {
+		inOrganization *data.Organization
+		inProduct      *data.Product
+		inPayload      *mdata_payload.MdPayload
+		inSigner       string
+		outError       string
+		outOwner       string
+	}{
+		"ownerUpdates": {
+			inProduct: &data.Product{Gtin: legacyGtin, State: "ACTIVE", Owner: alice},
+			inPayload: &mdata_payload.MdPayload{Action: "update", Gtin: legacyGtin, Attributes: data.Attributes{"uom": "cases"}},
+			inSigner:  alice,
+			outOwner:  alice,
+		},
+		"otherSignerUpdates": {
+			inProduct: &data.Product{Gtin: legacyGtin, State: "ACTIVE", Owner: alice},
+			inPayload: &mdata_payload.MdPayload{Action: "update", Gtin: legacyGtin, Attributes: data.Attributes{"uom": "cases"}},
+			inSigner:  mallory,
+			outError:  "Signer " + mallory + " is not the owner of product " + legacyGtin,
+			outOwner:  alice,
+		},
+		"ownerlessUpdate": {
+			inProduct: &data.Product{Gtin: legacyGtin, State: "ACTIVE"},
+			inPayload: &mdata_payload.MdPayload{Action: "update", Gtin: legacyGtin, Attributes: data.Attributes{"uom": "cases"}},
+			inSigner:  mallory,
+			outError:  "Product " + legacyGtin + " has no owner",
+		},
+		"ownerlessSetState": {
+			inProduct: &data.Product{Gtin: legacyGtin, State: "ACTIVE"},
+			inPayload: &mdata_payload.MdPayload{Action: "set", Gtin: legacyGtin, State: "INACTIVE"},
+			inSigner:  mallory,
+			outError:  "Product " + legacyGtin + " has no owner",
+		},
+		"ownerlessUpdateInOrganization": {
+			inOrganization: &testOrganization,
+			inProduct:      &data.Product{Gtin: testGtin, State: "ACTIVE"},
+			inPayload:      &mdata_payload.MdPayload{Action: "update", Gtin: testGtin, Attributes: data.Attributes{"uom": "cases"}},
+			inSigner:       alice,
+		},
+		"ownerlessUpdateInOrganizationWithoutRole": {
+			inOrganization: &testOrganization,
+			inProduct:      &data.Product{Gtin: testGtin, State: "ACTIVE"},
+			inPayload:      &mdata_payload.MdPayload{Action: "update", Gtin: testGtin, Attributes: data.Attributes{"uom": "cases"}},
+			inSigner:       mallory,
+			outError:       "Signer " + mallory + " does not have role product.update for organization acme",
+		},
+		"ownerlessSetStateInOrganization": {
+			inOrganization: &testOrganization,
+			inProduct:      &data.Product{Gtin: testGtin, State: "ACTIVE"},
+			inPayload:      &mdata_payload.MdPayload{Action: "set", Gtin: testGtin, State: "INACTIVE"},
+			inSigner:       alice,
+		},
+		"ownerlessSetStateInOrganizationWithoutRole": {
+			inOrganization: &testOrganization,
+			inProduct:      &data.Product{Gtin: testGtin, State: "ACTIVE"},
+			inPayload:      &mdata_payload.MdPayload{Action: "set", Gtin: testGtin, State: "INACTIVE"},
+			inSigner:       mallory,
+			outError:       "Signer " + mallory + " does not have role product.lifecycle for organization acme",
+		},
+		"ownedUpdateInOrganizationByOtherAdmin": {
+			inOrganization: &testOrganization,
+			inProduct:      &data.Product{Gtin: testGtin, State: "ACTIVE", Owner: bob},
+			inPayload:      &mdata_payload.MdPayload{Action: "update", Gtin: testGtin, Attributes: data.Attributes{"uom": "cases"}},
+			inSigner:       alice,
+			outError:       "Signer " + alice + " is not the owner of product " + testGtin,
+			outOwner:       bob,
+		},
+		"ownerTransfers": {
+			inProduct: &data.Product{Gtin: legacyGtin, State: "ACTIVE", Owner: alice},
+			inPayload: &mdata_payload.MdPayload{Action: "transfer", Gtin: legacyGtin, NewOwner: bob},
+			inSigner:  alice,
+			outOwner:  bob,
+		},
+		"otherSignerTransfers": {
+			inProduct: &data.Product{Gtin: legacyGtin, State: "ACTIVE", Owner: alice},
+			inPayload: &mdata_payload.MdPayload{Action: "transfer", Gtin: legacyGtin, NewOwner: mallory},
+			inSigner:  mallory,
+			outError:  "Signer " + mallory + " is not the owner of product " + legacyGtin,
+			outOwner:  alice,
+		},
+		"ownerlessTransferWithoutOrganization": {
+			inProduct: &data.Product{Gtin: legacyGtin, State: "ACTIVE"},
+			inPayload: &mdata_payload.MdPayload{Action: "transfer", Gtin: legacyGtin, NewOwner: mallory},
+			inSigner:  mallory,
+			outError:  "Product " + legacyGtin + " has no owner, only an admin of its organization can give it one",
+		},
+		"ownerlessTransferByAgent": {
+			inOrganization: &testOrganization,
+			inProduct:      &data.Product{Gtin: testGtin, State: "ACTIVE"},
+			inPayload:      &mdata_payload.MdPayload{Action: "transfer", Gtin: testGtin, NewOwner: mallory},
+			inSigner:       mallory,
+			outError:       "Product " + testGtin + " has no owner, only an admin of its organization can give it one",
+		},
+		"ownerlessTransferByAdmin": {
+			inOrganization: &testOrganization,
+			inProduct:      &data.Product{Gtin: testGtin, State: "ACTIVE"},
+			inPayload:      &mdata_payload.MdPayload{Action: "transfer", Gtin: testGtin, NewOwner: alice},
+			inSigner:       alice,
+			outOwner:       alice,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		state := newTestState(t, test.inOrganization, nil, []*data.Product{test.inProduct})
+		mdState := mdata_state.NewMdState(state.context())
+		err := applyProduct(mdState, test.inPayload, test.inSigner, "t1")
+		assertInvalid(t, test.outError, err)
+
+		product, err := mdata_state.NewMdState(state.context()).GetProduct(test.inPayload.Gtin)
+		assert.Nil(t, err)
+		if assert.NotNil(t, product) {
+			assert.Equal(t, test.outOwner, product.Owner)
+			if test.outError == "" && test.inPayload.Action == "set" {
+				assert.Equal(t, test.inPayload.State, product.State)
+			}
+		}
+	}
+}
+
+func TestApplyFamilyVersion(t *testing.T) {
+	update, err := proto.Marshal(&payload_pb2.MdataPayload{
+		Action: &payload_pb2.MdataPayload_Update{Update: &payload_pb2.UpdateProductAction{
+			Gtin:       legacyGtin,
+			Attributes: data.Attributes{"uom": "lbs"}.ToProto(),
+		}}})
+	if !assert.Nil(t, err) {
+		return
+	}
+	productAddress := address.MakeProductAddress(legacyGtin)
+
+	tests := map[string]struct {
+		inVersion     string
+		inPayload     []byte
+		inOwner       string
+		inState       string
+		inInputs      []string
+		outError      string
+		outAttributes data.Attributes
+		outState      string
+	}{
+		"2.0": {
+			inVersion:     "2.0",
+			inPayload:     update,
+			inOwner:       alice,
+			inState:       "ACTIVE",
+			outAttributes: data.Attributes{"uom": "lbs"},
+			outState:      "ACTIVE",
+		},
+		"1.0Update": {
+			inVersion:     "1.0",
+			inPayload:     []byte("update," + legacyGtin + ",uom=lbs,"),
+			inState:       "INACTIVE",
+			inInputs:      []string{productAddress},
+			outAttributes: data.Attributes{"uom": "lbs"},
+			outState:      "ACTIVE",
+		},
+		"1.0SetState": {
+			inVersion:     "1.0",
+			inPayload:     []byte("set," + legacyGtin + ",,DISCONTINUED"),
+			inState:       "ACTIVE",
+			inInputs:      []string{productAddress},
+			outAttributes: data.Attributes{"uom": "cases"},
+			outState:      "DISCONTINUED",
+		},
+		"1.0InvalidState": {
+			inVersion:     "1.0",
+			inPayload:     []byte("set," + legacyGtin + ",,RECALLED"),
+			inState:       "ACTIVE",
+			inInputs:      []string{productAddress},
+			outError:      "Invalid state (state must be one of ACTIVE, DISCONTINUED, INACTIVE), GOT: RECALLED",
+			outAttributes: data.Attributes{"uom": "cases"},
+			outState:      "ACTIVE",
+		},
+		"1.0OwnedProduct": {
+			inVersion:     "1.0",
+			inPayload:     []byte("update," + legacyGtin + ",uom=lbs,"),
+			inOwner:       alice,
+			inState:       "ACTIVE",
+			inInputs:      []string{productAddress},
+			outError:      "Product " + legacyGtin + " has an owner, it can only be changed with family version 2.0",
+			outAttributes: data.Attributes{"uom": "cases"},
+			outState:      "ACTIVE",
+		},
+		"1.0Transfer": {
+			inVersion:     "1.0",
+			inPayload:     []byte("transfer," + legacyGtin + ",,"),
+			inState:       "ACTIVE",
+			inInputs:      []string{productAddress},
+			outError:      "Action 'transfer' requires family version 2.0",
+			outAttributes: data.Attributes{"uom": "cases"},
+			outState:      "ACTIVE",
+		},
+		"unknownVersion": {
+			inVersion:     "3.0",
+			inPayload:     update,
+			inOwner:       alice,
+			inState:       "ACTIVE",
+			outError:      "Unsupported family version 3.0",
+			outAttributes: data.Attributes{"uom": "cases"},
+			outState:      "ACTIVE",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		state := newTestState(t, nil, nil, []*data.Product{
+			{Gtin: legacyGtin, Attributes: data.Attributes{"uom": "cases"}, State: test.inState, Owner: test.inOwner, Revision: 2},
+		})
+		request := &processor_pb2.TpProcessRequest{
+			Header: &transaction_pb2.TransactionHeader{
+				FamilyName:      "mdata",
+				FamilyVersion:   test.inVersion,
+				SignerPublicKey: alice,
+				Inputs:          test.inInputs,
+				Outputs:         test.inInputs,
+			},
+			Payload:   test.inPayload,
+			Signature: "t1",
+		}
+		assertInvalid(t, test.outError, apply(request, mdata_state.NewMdState(state.declaredContext(test.inInputs, test.inInputs))))
+
+		product, err := mdata_state.NewMdState(state.context()).GetProduct(legacyGtin)
+		assert.Nil(t, err)
+		if assert.NotNil(t, product) {
+			assert.Equal(t, test.outAttributes, product.Attributes)
+			assert.Equal(t, test.outState, product.State)
+		}
+	}
+}
+
+// A 1.0 create and delete only use the address of the product
+func TestLegacyCreateAndDelete(t *testing.T) {
+	state := newTestState(t, nil, nil, nil)
+	declared := []string{address.MakeProductAddress(legacyGtin)}
+	apply := func(payload string) error {
+		request := &processor_pb2.TpProcessRequest{
+			Header:    &transaction_pb2.TransactionHeader{FamilyName: "mdata", FamilyVersion: "1.0", SignerPublicKey: alice},
+			Payload:   []byte(payload),
+			Signature: "t1",
+		}
+		return apply(request, mdata_state.NewMdState(state.declaredContext(declared, declared)))
+	}
+
+	assert.Nil(t, apply("create,"+legacyGtin+",uom=cases,"))
+	assertInvalid(t, "Product already exists", apply("create,"+legacyGtin+",,"))
+	product, err := mdata_state.NewMdState(state.context()).GetProduct(legacyGtin)
+	assert.Nil(t, err)
+	assert.Equal(t, &data.Product{Gtin: legacyGtin, Attributes: data.Attributes{"uom": "cases"}, State: "ACTIVE"}, product)
+
+	assertInvalid(t, "Delete requires an INACTIVE product. Please deactivate the product with `mdata set <GTIN> INACTIVE`.", apply("delete,"+legacyGtin+",,"))
+	assert.Nil(t, apply("set,"+legacyGtin+",,INACTIVE"))
+	assert.Nil(t, apply("delete,"+legacyGtin+",,"))
+	product, err = mdata_state.NewMdState(state.context()).GetProduct(legacyGtin)
+	assert.Nil(t, err)
+	assert.Nil(t, product)
+}
+
+func TestProductLifecycle(t *testing.T) {
+	custom, err := proto.Marshal(&setting_pb2.Setting{Entries: []*setting_pb2.Setting_Entry{{
+		Key:   data.LifecycleSetting,
+		Value: `{"initial": "DRAFT", "transitions": {"DRAFT": ["PUBLISHED"], "PUBLISHED": ["DRAFT"]}, "deletable": ["DRAFT"]}`,
+	}}})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	tests := map[string]struct {
+		inSetting  []byte
+		inState    string
+		inPayload  *mdata_payload.MdPayload
+		outError   string
+		outState   string
+		outReason  string
+		outDeleted bool
+	}{
+		"deactivate": {
+			inState:   "ACTIVE",
+			inPayload: &mdata_payload.MdPayload{Action: "set", Gtin: legacyGtin, State: "INACTIVE", Reason: "RECALL"},
+			outState:  "INACTIVE",
+			outReason: "RECALL",
+		},
+		"reactivate": {
+			inState:   "INACTIVE",
+			inPayload: &mdata_payload.MdPayload{Action: "set", Gtin: legacyGtin, State: "ACTIVE"},
+			outState:  "ACTIVE",
+		},
+		"reviveDiscontinued": {
+			inState:   "DISCONTINUED",
+			inPayload: &mdata_payload.MdPayload{Action: "set", Gtin: legacyGtin, State: "ACTIVE"},
+			outError:  "Product " + legacyGtin + " can not change from DISCONTINUED to ACTIVE",
+			outState:  "DISCONTINUED",
+		},
+		"unknownState": {
+			inState:   "ACTIVE",
+			inPayload: &mdata_payload.MdPayload{Action: "set", Gtin: legacyGtin, State: "RECALLED"},
+			outError:  "Invalid state (state must be one of ACTIVE, DISCONTINUED, INACTIVE), GOT: RECALLED",
+			outState:  "ACTIVE",
+		},
+		"deleteActive": {
+			inState:   "ACTIVE",
+			inPayload: &mdata_payload.MdPayload{Action: "delete", Gtin: legacyGtin},
+			outError:  "Delete requires a product in one of the states INACTIVE. Please change its state with `mdata set <GTIN> <state>`.",
+			outState:  "ACTIVE",
+		},
+		"deleteInactive": {
+			inState:    "INACTIVE",
+			inPayload:  &mdata_payload.MdPayload{Action: "delete", Gtin: legacyGtin},
+			outDeleted: true,
+		},
+		"settingTransition": {
+			inSetting: custom,
+			inState:   "DRAFT",
+			inPayload: &mdata_payload.MdPayload{Action: "set", Gtin: legacyGtin, State: "PUBLISHED"},
+			outState:  "PUBLISHED",
+		},
+		"settingReplacesDefault": {
+			inSetting: custom,
+			inState:   "PUBLISHED",
+			inPayload: &mdata_payload.MdPayload{Action: "set", Gtin: legacyGtin, State: "INACTIVE"},
+			outError:  "Invalid state (state must be one of DRAFT, PUBLISHED), GOT: INACTIVE",
+			outState:  "PUBLISHED",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		state := newTestState(t, nil, nil, []*data.Product{
+			{Gtin: legacyGtin, State: test.inState, Owner: alice},
+		})
+		if test.inSetting != nil {
+			state[address.MakeSettingAddress(data.LifecycleSetting)] = test.inSetting
+		}
+		assertInvalid(t, test.outError, applyProduct(mdata_state.NewMdState(state.context()), test.inPayload, alice, "t1"))
+
+		product, err := mdata_state.NewMdState(state.context()).GetProduct(legacyGtin)
+		assert.Nil(t, err)
+		if test.outDeleted {
+			assert.Nil(t, product)
+		} else if assert.NotNil(t, product) {
+			assert.Equal(t, test.outState, product.State)
+			assert.Equal(t, test.outReason, product.Reason)
+		}
+	}
+}
+
+func TestCreateInitialState(t *testing.T) {
+	custom, err := proto.Marshal(&setting_pb2.Setting{Entries: []*setting_pb2.Setting_Entry{{
+		Key:   data.LifecycleSetting,
+		Value: `{"initial": "DRAFT", "transitions": {"DRAFT": []}}`,
+	}}})
+	if !assert.Nil(t, err) {
+		return
+	}
+	state := newTestState(t, &testOrganization, nil, nil)
+	state[address.MakeSettingAddress(data.LifecycleSetting)] = custom
+
+	payload := &mdata_payload.MdPayload{Action: "create", Gtin: testGtin}
+	assert.Nil(t, applyProduct(mdata_state.NewMdState(state.context()), payload, alice, "t1"))
+	product, err := mdata_state.NewMdState(state.context()).GetProduct(testGtin)
+	assert.Nil(t, err)
+	if assert.NotNil(t, product) {
+		assert.Equal(t, "DRAFT", product.State)
+	}
+}
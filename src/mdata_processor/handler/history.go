@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// saveProduct stores a product changed by action, recording the change from
+// previous, nil for a new product, as its next revision, and reports it with
+// an event.
+func saveProduct(mdState *mdata_state.MdState, action string, signer string, transactionId string, previous *data.Product, product *data.Product) error {
+	revision, err := recordRevision(mdState, product.Gtin, action, signer, transactionId, previous, product)
+	if err != nil {
+		return err
+	}
+	product.Revision = revision.Revision
+	if err := mdState.SetProduct(product.Gtin, product); err != nil {
+		return err
+	}
+	return reportChange(mdState, revision, product.Gtin, product)
+}
+
+// removeProduct deletes a product. Its history is kept, ending with the
+// delete, and the event carries the deleted product.
+func removeProduct(mdState *mdata_state.MdState, action string, signer string, transactionId string, previous *data.Product) error {
+	revision, err := recordRevision(mdState, previous.Gtin, action, signer, transactionId, previous, nil)
+	if err != nil {
+		return err
+	}
+	if err := mdState.DeleteProduct(previous.Gtin); err != nil {
+		return err
+	}
+	return reportChange(mdState, revision, previous.Gtin, previous)
+}
+
+// reportChange sends the event and receipt of a recorded change. Subscribers
+// get them once the transaction is committed.
+func reportChange(mdState *mdata_state.MdState, revision *data.ProductRevision, gtin string, product *data.Product) error {
+	receipt := &data.ProductReceipt{
+		Action:   revision.Action,
+		Gtin:     gtin,
+		Revision: revision.Revision,
+		State:    product.State,
+		Changed:  revision.Changed,
+	}
+	return mdState.AddProductEvent(receipt, revision.Signer, product)
+}
+
+// recordRevision appends a change to the history of a GTIN and returns it.
+// Revisions follow the last one in the history, so they keep increasing when
+// a deleted GTIN is created again. Products changed before histories were
+// kept start at revision 1.
+func recordRevision(mdState *mdata_state.MdState, gtin string, action string, signer string, transactionId string, previous *data.Product, product *data.Product) (*data.ProductRevision, error) {
+	history, err := mdState.GetHistory(gtin)
+	if err != nil {
+		return nil, err
+	}
+
+	number := uint64(1)
+	if len(history) > 0 {
+		number = history[len(history)-1].Revision + 1
+	}
+	if previous != nil && previous.Revision >= number {
+		number = previous.Revision + 1
+	}
+
+	changed, values := data.DiffProducts(previous, product)
+	revision := &data.ProductRevision{
+		Revision:      number,
+		Action:        action,
+		Signer:        signer,
+		TransactionId: transactionId,
+		Changed:       changed,
+		Previous:      values,
+	}
+	if err := mdState.AddRevision(gtin, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// validateRevision rejects a payload expecting another revision of the product
+// than the stored one, so of two conflicting changes based on the same
+// revision only the first applies. A missing product is left to the action
+// to report.
+func validateRevision(mdState *mdata_state.MdState, payload *mdata_payload.MdPayload) error {
+	if payload.ExpectedRevision == 0 {
+		return nil
+	}
+	product, err := mdState.GetProduct(payload.Gtin)
+	if err != nil {
+		return err
+	}
+	if product != nil && product.Revision != payload.ExpectedRevision {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Product %v is at revision %v, not the expected revision %v", payload.Gtin, product.Revision, payload.ExpectedRevision)}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+func TestExpectedRevision(t *testing.T) {
+	tests := map[string]struct {
+		inPayload   *mdata_payload.MdPayload
+		outError    string
+		outRevision uint64
+	}{
+		"anyRevision": {
+			inPayload:   &mdata_payload.MdPayload{Action: "update", Gtin: legacyGtin, Attributes: data.Attributes{"uom": "lbs"}},
+			outRevision: 4,
+		},
+		"currentRevision": {
+			inPayload:   &mdata_payload.MdPayload{Action: "update", Gtin: legacyGtin, Attributes: data.Attributes{"uom": "lbs"}, ExpectedRevision: 3},
+			outRevision: 4,
+		},
+		"staleRevision": {
+			inPayload:   &mdata_payload.MdPayload{Action: "update", Gtin: legacyGtin, Attributes: data.Attributes{"uom": "lbs"}, ExpectedRevision: 2},
+			outError:    "Product " + legacyGtin + " is at revision 3, not the expected revision 2",
+			outRevision: 3,
+		},
+		"staleSetState": {
+			inPayload:   &mdata_payload.MdPayload{Action: "set", Gtin: legacyGtin, State: "INACTIVE", ExpectedRevision: 2},
+			outError:    "Product " + legacyGtin + " is at revision 3, not the expected revision 2",
+			outRevision: 3,
+		},
+		"futureRevisionDelete": {
+			inPayload:   &mdata_payload.MdPayload{Action: "delete", Gtin: legacyGtin, ExpectedRevision: 4},
+			outError:    "Product " + legacyGtin + " is at revision 3, not the expected revision 4",
+			outRevision: 3,
+		},
+		"staleTransfer": {
+			inPayload:   &mdata_payload.MdPayload{Action: "transfer", Gtin: legacyGtin, NewOwner: bob, ExpectedRevision: 1},
+			outError:    "Product " + legacyGtin + " is at revision 3, not the expected revision 1",
+			outRevision: 3,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		state := newTestState(t, nil, nil, []*data.Product{
+			{Gtin: legacyGtin, Attributes: data.Attributes{"uom": "cases"}, State: "INACTIVE", Owner: alice, Revision: 3},
+		})
+		assertInvalid(t, test.outError, applyProduct(mdata_state.NewMdState(state.context()), test.inPayload, alice, "t1"))
+
+		product, err := mdata_state.NewMdState(state.context()).GetProduct(legacyGtin)
+		assert.Nil(t, err)
+		if assert.NotNil(t, product) {
+			assert.Equal(t, test.outRevision, product.Revision)
+		}
+	}
+}
+
+// Of two changes based on the same revision only the first applies
+func TestConflictingChanges(t *testing.T) {
+	state := newTestState(t, nil, nil, []*data.Product{
+		{Gtin: legacyGtin, Attributes: data.Attributes{"uom": "cases"}, State: "ACTIVE", Owner: alice, Revision: 1},
+	})
+	first := &mdata_payload.MdPayload{Action: "update", Gtin: legacyGtin, Attributes: data.Attributes{"uom": "lbs"}, ExpectedRevision: 1}
+	second := &mdata_payload.MdPayload{Action: "patch", Gtin: legacyGtin, Attributes: data.Attributes{"name": "wings"}, ExpectedRevision: 1}
+
+	assert.Nil(t, applyProduct(mdata_state.NewMdState(state.context()), first, alice, "t1"))
+	assertInvalid(t, "Product "+legacyGtin+" is at revision 2, not the expected revision 1",
+		applyProduct(mdata_state.NewMdState(state.context()), second, alice, "t2"))
+
+	mdState := mdata_state.NewMdState(state.context())
+	product, err := mdState.GetProduct(legacyGtin)
+	assert.Nil(t, err)
+	if assert.NotNil(t, product) {
+		assert.Equal(t, data.Attributes{"uom": "lbs"}, product.Attributes)
+	}
+	history, err := mdState.GetHistory(legacyGtin)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(history)) {
+		assert.Equal(t, uint64(2), history[0].Revision)
+		assert.Equal(t, "t1", history[0].TransactionId)
+	}
+}
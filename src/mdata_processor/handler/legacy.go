@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// applyLegacy applies a family version 1.0 transaction. Its inputs and outputs
+// are only the address of its product, so it is applied with the rules of 1.0:
+// organizations, agents, schemas, the lifecycle setting and histories can not
+// be read or written. Products are created ACTIVE and without an owner, can be
+// set to any state of the default lifecycle, are made ACTIVE again by an update
+// and can be deleted once INACTIVE. Products with an owner were created or
+// handed over with 2.0, whose rules apply to them, so 1.0 can not change them.
+func applyLegacy(mdState *mdata_state.MdState, payload *mdata_payload.MdPayload, signer string) error {
+	switch payload.Action {
+	case "create":
+		product, err := mdState.GetProduct(payload.Gtin)
+		if err != nil {
+			return err
+		}
+		if product != nil {
+			return &processor.InvalidTransactionError{Msg: "Product already exists"}
+		}
+		product = &data.Product{
+			Gtin:       payload.Gtin,
+			Attributes: payload.Attributes,
+			State:      data.StateActive,
+		}
+		displayCreate(payload, signer)
+		return saveLegacyProduct(mdState, payload.Action, signer, nil, product)
+	case "update":
+		product, err := legacyProduct(mdState, payload.Gtin, "Update")
+		if err != nil {
+			return err
+		}
+		previous := product.Copy()
+		product.Attributes = payload.Attributes
+		product.State = data.StateActive
+		displayUpdate(payload, signer, product)
+		return saveLegacyProduct(mdState, payload.Action, signer, previous, product)
+	case "set":
+		product, err := legacyProduct(mdState, payload.Gtin, "Set state")
+		if err != nil {
+			return err
+		}
+		if !data.DefaultLifecycle.IsState(payload.State) {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Invalid state (state must be one of %v), GOT: %v", strings.Join(data.DefaultLifecycle.States(), ", "), payload.State)}
+		}
+		previous := product.Copy()
+		product.State = payload.State
+		displayStateChange(payload, signer, product)
+		return saveLegacyProduct(mdState, payload.Action, signer, previous, product)
+	case "delete":
+		product, err := legacyProduct(mdState, payload.Gtin, "Delete")
+		if err != nil {
+			return err
+		}
+		if product.State != data.StateInactive {
+			return &processor.InvalidTransactionError{Msg: "Delete requires an INACTIVE product. Please deactivate the product with `mdata set <GTIN> INACTIVE`."}
+		}
+		displayDelete(signer, payload.Gtin)
+		if err := mdState.DeleteProduct(payload.Gtin); err != nil {
+			return err
+		}
+		return reportLegacyChange(mdState, payload.Action, signer, product, nil)
+	default:
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Invalid Action : '%v'", payload.Action)}
+	}
+}
+
+// legacyProduct returns the product a 1.0 action changes, which must exist and
+// have no owner
+func legacyProduct(mdState *mdata_state.MdState, gtin string, action string) (*data.Product, error) {
+	product, err := mdState.GetProduct(gtin)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, &processor.InvalidTransactionError{Msg: fmt.Sprintf("%v requires an existing product", action)}
+	}
+	if product.Owner != "" {
+		return nil, &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Product %v has an owner, it can only be changed with family version 2.0", gtin)}
+	}
+	return product, nil
+}
+
+// saveLegacyProduct stores a product changed by a 1.0 action. The history is
+// not at its address, so the change keeps the revision of the product and is
+// only reported by its event.
+func saveLegacyProduct(mdState *mdata_state.MdState, action string, signer string, previous *data.Product, product *data.Product) error {
+	if err := mdState.SetProduct(product.Gtin, product); err != nil {
+		return err
+	}
+	return reportLegacyChange(mdState, action, signer, previous, product)
+}
+
+// reportLegacyChange sends the event and receipt of a 1.0 change, from
+// previous to product, either nil
+func reportLegacyChange(mdState *mdata_state.MdState, action string, signer string, previous *data.Product, product *data.Product) error {
+	changed, _ := data.DiffProducts(previous, product)
+	receipt := &data.ProductReceipt{Action: action, Changed: changed}
+	if product == nil {
+		product = previous
+	}
+	receipt.Gtin = product.Gtin
+	receipt.Revision = product.Revision
+	receipt.State = product.State
+	return mdState.AddProductEvent(receipt, signer, product)
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// applyOrganization handles the organization registry actions. The signer of
+// an org_create becomes the organization's first admin; every other action
+// must be signed by one of its admins.
+func applyOrganization(mdState *mdata_state.MdState, payload *mdata_payload.MdPayload, signer string) error {
+	organization, err := mdState.GetOrganization(payload.OrganizationId)
+	if err != nil {
+		return err
+	}
+
+	if payload.Action == "org_create" {
+		if organization != nil {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Organization %v already exists", payload.OrganizationId)}
+		}
+		organization = &data.Organization{
+			Id:     payload.OrganizationId,
+			Name:   payload.OrganizationName,
+			Admins: []string{signer},
+		}
+		err := claimCompanyPrefixes(mdState, organization, payload.CompanyPrefixes)
+		if err != nil {
+			return err
+		}
+		displayOrganization(signer, "created", organization)
+		return mdState.SetOrganization(organization.Id, organization)
+	}
+
+	if organization == nil {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Organization %v does not exist", payload.OrganizationId)}
+	}
+	if !organization.IsAdmin(signer) {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Signer %v is not an admin of organization %v", signer, organization.Id)}
+	}
+
+	switch payload.Action {
+	case "org_update":
+		organization.Name = payload.OrganizationName
+		err := claimCompanyPrefixes(mdState, organization, payload.CompanyPrefixes)
+		if err != nil {
+			return err
+		}
+		displayOrganization(signer, "updated", organization)
+	case "org_add_key":
+		if organization.IsAdmin(payload.PublicKey) {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Key %v is already an admin of organization %v", payload.PublicKey, organization.Id)}
+		}
+		organization.Admins = append(organization.Admins, payload.PublicKey)
+		displayOrganization(signer, "added a key to", organization)
+	case "org_remove_key":
+		if !organization.IsAdmin(payload.PublicKey) {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Key %v is not an admin of organization %v", payload.PublicKey, organization.Id)}
+		}
+		if len(organization.Admins) == 1 {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Cannot remove the last admin of organization %v", organization.Id)}
+		}
+		var admins []string
+		for _, admin := range organization.Admins {
+			if admin != payload.PublicKey {
+				admins = append(admins, admin)
+			}
+		}
+		organization.Admins = admins
+		displayOrganization(signer, "removed a key from", organization)
+	default:
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Invalid Action : '%v'", payload.Action)}
+	}
+	return mdState.SetOrganization(organization.Id, organization)
+}
+
+// claimCompanyPrefixes replaces the organization's company prefixes, releasing
+// the ones it no longer lists. A prefix already owned by another organization
+// can not be claimed, nor can a prefix that starts with, or is the start of, a
+// prefix of another organization, as both would cover the same GTINs.
+func claimCompanyPrefixes(mdState *mdata_state.MdState, organization *data.Organization, prefixes []string) error {
+	for _, prefix := range prefixes {
+		owner, err := mdState.GetCompanyPrefixOwner(prefix)
+		if err != nil {
+			return err
+		}
+		if owner != "" && owner != organization.Id {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Company prefix %v belongs to organization %v", prefix, owner)}
+		}
+		overlapping, err := mdState.GetOverlappingCompanyPrefixes(prefix)
+		if err != nil {
+			return err
+		}
+		others := make([]string, 0, len(overlapping))
+		for other := range overlapping {
+			others = append(others, other)
+		}
+		sort.Strings(others)
+		for _, other := range others {
+			if overlapping[other] != organization.Id {
+				return &processor.InvalidTransactionError{
+					Msg: fmt.Sprintf("Company prefix %v overlaps company prefix %v of organization %v", prefix, other, overlapping[other])}
+			}
+		}
+	}
+
+	keep := make(map[string]bool)
+	for _, prefix := range prefixes {
+		keep[prefix] = true
+	}
+	for _, prefix := range organization.CompanyPrefixes {
+		if !keep[prefix] {
+			if err := mdState.DeleteCompanyPrefix(prefix); err != nil {
+				return err
+			}
+		}
+	}
+	for _, prefix := range prefixes {
+		if err := mdState.SetCompanyPrefixOwner(prefix, organization.Id); err != nil {
+			return err
+		}
+	}
+	organization.CompanyPrefixes = prefixes
+	return nil
+}
+
+// validateCompanyPrefix only lets admins and product.create agents of the
+// organization owning the GTIN's company prefix create the product.
+func validateCompanyPrefix(mdState *mdata_state.MdState, gtin string, signer string) error {
+	organization, err := mdState.GetOrganizationForGtin(gtin)
+	if err != nil {
+		return err
+	}
+	if organization == nil {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("No organization owns the company prefix of GTIN %v", gtin)}
+	}
+	return validateRole(mdState, organization, signer, data.RoleProductCreate)
+}
+
+func displayOrganization(signer string, verb string, organization *data.Organization) {
+	s := fmt.Sprintf("+ Signer %s %s organization %s +", signer[:6], verb, organization.Id)
+	sLength := len(s)
+	border := "+" + strings.Repeat("-", sLength-2) + "+"
+	fmt.Println(border)
+	fmt.Println(s)
+	fmt.Println(border)
+}
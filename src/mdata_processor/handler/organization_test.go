@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+func TestCreateUnderCompanyPrefix(t *testing.T) {
+	tests := map[string]struct {
+		inGtin   string
+		inSigner string
+		outError string
+	}{
+		"prefixOfOrganization": {
+			inGtin:   testGtin,
+			inSigner: alice,
+		},
+		"prefixOfNoOrganization": {
+			inGtin:   legacyGtin,
+			inSigner: alice,
+			outError: "No organization owns the company prefix of GTIN " + legacyGtin,
+		},
+		"signerOutsideOrganization": {
+			inGtin:   testGtin,
+			inSigner: mallory,
+			outError: "Signer " + mallory + " does not have role product.create for organization acme",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		state := newTestState(t, &testOrganization, nil, nil)
+		payload := &mdata_payload.MdPayload{Action: "create", Gtin: test.inGtin}
+		assertInvalid(t, test.outError, applyProduct(mdata_state.NewMdState(state.context()), payload, test.inSigner, "t1"))
+
+		product, err := mdata_state.NewMdState(state.context()).GetProduct(test.inGtin)
+		assert.Nil(t, err)
+		assert.Equal(t, test.outError == "", product != nil)
+	}
+}
+
+func TestClaimCompanyPrefixes(t *testing.T) {
+	tests := map[string]struct {
+		inPayload   *mdata_payload.MdPayload
+		inSigner    string
+		outError    string
+		outPrefixes map[string]string
+	}{
+		"createWithFreePrefix": {
+			inPayload:   &mdata_payload.MdPayload{Action: "org_create", OrganizationId: "globex", OrganizationName: "Globex", CompanyPrefixes: []string{"0098765"}},
+			inSigner:    mallory,
+			outPrefixes: map[string]string{"0012345": "acme", "0098765": "globex"},
+		},
+		"createWithTakenPrefix": {
+			inPayload:   &mdata_payload.MdPayload{Action: "org_create", OrganizationId: "globex", OrganizationName: "Globex", CompanyPrefixes: []string{"0012345"}},
+			inSigner:    mallory,
+			outError:    "Company prefix 0012345 belongs to organization acme",
+			outPrefixes: map[string]string{"0012345": "acme", "0098765": ""},
+		},
+		"createInsideTakenPrefix": {
+			inPayload:   &mdata_payload.MdPayload{Action: "org_create", OrganizationId: "globex", OrganizationName: "Globex", CompanyPrefixes: []string{"00123456"}},
+			inSigner:    mallory,
+			outError:    "Company prefix 00123456 overlaps company prefix 0012345 of organization acme",
+			outPrefixes: map[string]string{"0012345": "acme", "00123456": ""},
+		},
+		"createAroundTakenPrefix": {
+			inPayload:   &mdata_payload.MdPayload{Action: "org_create", OrganizationId: "globex", OrganizationName: "Globex", CompanyPrefixes: []string{"001234"}},
+			inSigner:    mallory,
+			outError:    "Company prefix 001234 overlaps company prefix 0012345 of organization acme",
+			outPrefixes: map[string]string{"0012345": "acme", "001234": ""},
+		},
+		"updateInsideOwnPrefix": {
+			inPayload:   &mdata_payload.MdPayload{Action: "org_update", OrganizationId: "acme", OrganizationName: "Acme Foods", CompanyPrefixes: []string{"0012345", "00123456"}},
+			inSigner:    alice,
+			outPrefixes: map[string]string{"0012345": "acme", "00123456": "acme"},
+		},
+		"updateReleasesPrefix": {
+			inPayload:   &mdata_payload.MdPayload{Action: "org_update", OrganizationId: "acme", OrganizationName: "Acme Foods", CompanyPrefixes: []string{"0098765"}},
+			inSigner:    alice,
+			outPrefixes: map[string]string{"0012345": "", "0098765": "acme"},
+		},
+		"updateByNonAdmin": {
+			inPayload:   &mdata_payload.MdPayload{Action: "org_update", OrganizationId: "acme", OrganizationName: "Acme Foods", CompanyPrefixes: []string{"0098765"}},
+			inSigner:    mallory,
+			outError:    "Signer " + mallory + " is not an admin of organization acme",
+			outPrefixes: map[string]string{"0012345": "acme", "0098765": ""},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		state := newTestState(t, &testOrganization, nil, nil)
+		assertInvalid(t, test.outError, applyOrganization(mdata_state.NewMdState(state.context()), test.inPayload, test.inSigner))
+
+		mdState := mdata_state.NewMdState(state.context())
+		for prefix, organizationId := range test.outPrefixes {
+			owner, err := mdState.GetCompanyPrefixOwner(prefix)
+			assert.Nil(t, err)
+			assert.Equal(t, organizationId, owner, "owner of %v", prefix)
+		}
+	}
+}
+
+// A prefix nested in the prefix of another organization can only be claimed
+// once that organization released it
+func TestNestedCompanyPrefixes(t *testing.T) {
+	state := newTestState(t, &testOrganization, nil, nil)
+	claim := func(action string, id string, prefixes []string, signer string) error {
+		payload := &mdata_payload.MdPayload{Action: action, OrganizationId: id, OrganizationName: id, CompanyPrefixes: prefixes}
+		return applyOrganization(mdata_state.NewMdState(state.context()), payload, signer)
+	}
+
+	assert.Nil(t, claim("org_create", "globex", []string{"0098765"}, mallory))
+	assertInvalid(t, "Company prefix 00123456 overlaps company prefix 0012345 of organization acme",
+		claim("org_update", "globex", []string{"0098765", "00123456"}, mallory))
+	assertInvalid(t, "Company prefix 009876 overlaps company prefix 0098765 of organization globex",
+		claim("org_update", "acme", []string{"0012345", "009876"}, alice))
+	assertInvalid(t, "Company prefix 00987654321 overlaps company prefix 0098765 of organization globex",
+		claim("org_update", "acme", []string{"00987654321"}, alice))
+
+	assert.Nil(t, claim("org_update", "acme", []string{"00123457"}, alice))
+	assertInvalid(t, "Company prefix 0012345 overlaps company prefix 00123457 of organization acme",
+		claim("org_update", "globex", []string{"0098765", "0012345"}, mallory))
+	assert.Nil(t, claim("org_update", "globex", []string{"0098765", "00123456"}, mallory))
+
+	mdState := mdata_state.NewMdState(state.context())
+	for prefix, organizationId := range map[string]string{"0012345": "", "00123456": "globex", "00123457": "acme", "0098765": "globex"} {
+		owner, err := mdState.GetCompanyPrefixOwner(prefix)
+		assert.Nil(t, err)
+		assert.Equal(t, organizationId, owner, "owner of %v", prefix)
+	}
+	overlapping, err := mdState.GetOverlappingCompanyPrefixes("001234")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"00123456": "globex", "00123457": "acme"}, overlapping)
+}
+
+// The organization owning the longest matching prefix decides
+func TestCreateUnderLongestPrefix(t *testing.T) {
+	state := newTestState(t, &testOrganization, nil, nil)
+	subsidiary := &data.Organization{Id: "acme-frozen", Admins: []string{mallory}, CompanyPrefixes: []string{"00123456"}}
+	mdState := mdata_state.NewMdState(state.context())
+	assert.Nil(t, mdState.SetOrganization(subsidiary.Id, subsidiary))
+	assert.Nil(t, mdState.SetCompanyPrefixOwner("00123456", subsidiary.Id))
+
+	payload := &mdata_payload.MdPayload{Action: "create", Gtin: testGtin}
+	assertInvalid(t, "Signer "+alice+" does not have role product.create for organization acme-frozen",
+		applyProduct(mdata_state.NewMdState(state.context()), payload, alice, "t1"))
+	assert.Nil(t, applyProduct(mdata_state.NewMdState(state.context()), payload, mallory, "t2"))
+}
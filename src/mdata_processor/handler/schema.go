@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_payload"
+	"github.com/tross-tyson/mdata_go/src/mdata_processor/mdata_state"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// applySchema handles the schema actions. A schema is owned by the
+// organization that created it and only its admins can change it.
+func applySchema(mdState *mdata_state.MdState, payload *mdata_payload.MdPayload, signer string) error {
+	schema, err := mdState.GetSchema(payload.Schema.Name)
+	if err != nil {
+		return err
+	}
+
+	switch payload.Action {
+	case "schema_create":
+		if schema != nil {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Schema %v already exists", payload.Schema.Name)}
+		}
+		schema = payload.Schema
+	case "schema_update":
+		if schema == nil {
+			return &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Schema %v does not exist", payload.Schema.Name)}
+		}
+		schema.Properties = payload.Schema.Properties
+	default:
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Invalid Action : '%v'", payload.Action)}
+	}
+
+	organization, err := mdState.GetOrganization(schema.Owner)
+	if err != nil {
+		return err
+	}
+	if organization == nil {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Organization %v does not exist", schema.Owner)}
+	}
+	if !organization.IsAdmin(signer) {
+		return &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Signer %v is not an admin of organization %v", signer, organization.Id)}
+	}
+
+	displaySchema(signer, schema)
+	return mdState.SetSchema(schema.Name, schema)
+}
+
+// validateAttributes checks product attributes against the product schema and
+// returns them in the types it defines. Attributes are free-form until the
+// product schema is created.
+func validateAttributes(mdState *mdata_state.MdState, attributes data.Attributes) (data.Attributes, error) {
+	schema, err := mdState.GetSchema(data.ProductSchemaName)
+	if err != nil {
+		return nil, err
+	}
+	if schema == nil {
+		return attributes, nil
+	}
+	converted, err := schema.Validate(attributes)
+	if err != nil {
+		return nil, &processor.InvalidTransactionError{Msg: err.Error()}
+	}
+	return converted, nil
+}
+
+func displaySchema(signer string, schema *data.Schema) {
+	s := fmt.Sprintf("+ Signer %s set schema %s with %d properties +", signer[:6], schema.Name, len(schema.Properties))
+	sLength := len(s)
+	border := "+" + strings.Repeat("-", sLength-2) + "+"
+	fmt.Println(border)
+	fmt.Println(s)
+	fmt.Println(border)
+}
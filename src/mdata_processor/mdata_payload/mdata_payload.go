@@ -1,53 +1,72 @@
 package mdata_payload
 
 import (
+	"encoding/hex"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/sawtooth-sdk-go/processor"
-	"reflect"
-	"strconv"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+	"github.com/tross-tyson/mdata_go/src/shared/gs1"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/payload_pb2"
 	"strings"
 )
 
 type MdPayload struct {
 	Action     string
 	Gtin       string
-	Attributes []string
+	Attributes data.Attributes
 	State      string
+	Reason     string
+	NewOwner   string
+	UnsetKeys  []string
+
+	// Revision the product must be at for the action to apply, 0 if any
+	ExpectedRevision uint64
+
+	// Organization actions
+	OrganizationId   string
+	OrganizationName string
+	CompanyPrefixes  []string
+	PublicKey        string
+
+	// Agent actions, which also use OrganizationId and PublicKey
+	Active bool
+	Roles  []string
+
+	// Schema actions, the owner of a new schema is OrganizationId
+	Schema *data.Schema
+
+	// Batch actions, applied in order
+	Operations []*MdPayload
 }
 
-func (p MdPayload) invaildChar() (bool, string) {
-	//Returns true if the payload contains "|", indicating an invalid payload
-	values := reflect.ValueOf(p)
-	num := values.NumField()
+// MaxOperations is the most operations a batch payload can carry
+const MaxOperations = 1000
 
-	for i := 0; i < num; i++ {
-		v := values.Field(i)
-		if v.Kind() == reflect.String {
-			strv := v.String()
-			if strings.Contains(strv, "|") {
-				return true, strv
-			}
-		}
-		if v.Kind() == reflect.Slice {
-			for _, value := range v.Interface().([]string) {
-				if strings.Contains(value, "|") {
-					return true, value
-				}
-			}
-		}
-	}
+// IsOrganizationAction reports whether the payload acts on an organization
+// rather than a product
+func (p *MdPayload) IsOrganizationAction() bool {
+	return strings.HasPrefix(p.Action, "org_")
+}
 
-	return false, ""
+// IsAgentAction reports whether the payload acts on an agent
+func (p *MdPayload) IsAgentAction() bool {
+	return strings.HasPrefix(p.Action, "agent_")
 }
 
-func (p *MdPayload) invalidAttributes() bool {
+// IsSchemaAction reports whether the payload acts on a schema
+func (p *MdPayload) IsSchemaAction() bool {
+	return strings.HasPrefix(p.Action, "schema_")
+}
+
+func invalidAttributes(attributes []string) bool {
 	//Return false for empty attributes
-	if len(p.Attributes) == 1 && p.Attributes[0] == "" {
+	if len(attributes) == 1 && attributes[0] == "" {
 		return false
 	}
 
 	// Verify they are key=value pairs in the slice of string
-	for _, pair := range p.Attributes {
+	for _, pair := range attributes {
 		if strings.Count(pair, "=") != 1 {
 			return true
 		}
@@ -56,36 +75,21 @@ func (p *MdPayload) invalidAttributes() bool {
 	return false
 }
 
-func (p *MdPayload) invalidGtin() bool {
-	// Verify the length of GTIN is 14 integers (no symbols, no letters)
-	_, err := strconv.Atoi(p.Gtin)
+func invalidPublicKey(publicKey string) bool {
+	// Verify the key is a compressed secp256k1 public key: 33 bytes, hex encoded
+	key, err := hex.DecodeString(publicKey)
 	if err != nil {
-		// Error converting string to int; invalid
-		return true
-	}
-
-	if len(p.Gtin) != 14 {
 		return true
 	}
-
-	return false
+	return len(key) != 33
 }
 
-func (p *MdPayload) invalidState() bool {
-	// Verify the state setting is valid: one of ACTIVE, INACTIVE, DISCONTINUED
-	validStates := []string{"ACTIVE", "INACTIVE", "DISCONTINUED"}
-	var foundMatch bool
-	for _, state := range validStates {
-		if p.State == state {
-			foundMatch = true
-		}
-		if foundMatch {
-			return false
-		}
-	}
-	return true
-}
+// legacyActions are the actions a family version 1.0 payload can carry
+var legacyActions = []string{"create", "update", "set", "delete"}
 
+// FromBytes decodes a family version 1.0 payload, a comma separated string.
+// Only the product actions of 1.0 can be sent this way, every other action
+// needs family version 2.0.
 func FromBytes(payloadData []byte) (*MdPayload, error) {
 	if payloadData == nil {
 		return nil, &processor.InvalidTransactionError{Msg: "Must contain payload"}
@@ -101,29 +105,248 @@ func FromBytes(payloadData []byte) (*MdPayload, error) {
 		return nil, &processor.InvalidTransactionError{Msg: "Payload is malformed"}
 	}
 
+	attributes := parts[2 : len(parts)-1]
+	if invalidAttributes(attributes) {
+		return nil, &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Invalid attributes (attributes must be in key=value pairs): %v", attributes)}
+	}
+
 	payload := MdPayload{}
 	payload.Action = parts[0]
 	payload.Gtin = parts[1]
-	payload.Attributes = parts[2 : len(parts)-1]
 	payload.State = parts[len(parts)-1]
 
+	if payload.Action != "" && !isLegacyAction(payload.Action) {
+		return nil, &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Action '%v' requires family version 2.0", payload.Action)}
+	}
+
+	var err error
+	payload.Attributes, err = data.DeserializeAttributes(attributes)
+	if err != nil {
+		return nil, &processor.InvalidTransactionError{Msg: fmt.Sprintf("Invalid attributes: %v", err)}
+	}
+
+	return payload.validate()
+}
+
+func isLegacyAction(action string) bool {
+	for _, legacy := range legacyActions {
+		if action == legacy {
+			return true
+		}
+	}
+	return false
+}
+
+// FromProtobuf decodes a family version 2.0 payload, an MdataPayload protobuf
+// message.
+func FromProtobuf(payloadData []byte) (*MdPayload, error) {
+	if payloadData == nil {
+		return nil, &processor.InvalidTransactionError{Msg: "Must contain payload"}
+	}
+
+	pb := &payload_pb2.MdataPayload{}
+	if err := proto.Unmarshal(payloadData, pb); err != nil {
+		return nil, &processor.InvalidTransactionError{Msg: fmt.Sprintf("Payload is malformed: %v", err)}
+	}
+
+	payload := MdPayload{}
+	var attributes []*payload_pb2.Attribute
+	switch action := pb.GetAction().(type) {
+	case *payload_pb2.MdataPayload_Create:
+		payload.Action = "create"
+		payload.Gtin = action.Create.GetGtin()
+		attributes = action.Create.GetAttributes()
+	case *payload_pb2.MdataPayload_Update:
+		payload.Action = "update"
+		payload.Gtin = action.Update.GetGtin()
+		attributes = action.Update.GetAttributes()
+		payload.ExpectedRevision = action.Update.GetExpectedRevision()
+	case *payload_pb2.MdataPayload_Set:
+		payload.Action = "set"
+		payload.Gtin = action.Set.GetGtin()
+		payload.State = action.Set.GetState()
+		payload.Reason = action.Set.GetReason()
+		payload.ExpectedRevision = action.Set.GetExpectedRevision()
+	case *payload_pb2.MdataPayload_Delete:
+		payload.Action = "delete"
+		payload.Gtin = action.Delete.GetGtin()
+		payload.ExpectedRevision = action.Delete.GetExpectedRevision()
+	case *payload_pb2.MdataPayload_Transfer:
+		payload.Action = "transfer"
+		payload.Gtin = action.Transfer.GetGtin()
+		payload.NewOwner = action.Transfer.GetNewOwner()
+		payload.ExpectedRevision = action.Transfer.GetExpectedRevision()
+	case *payload_pb2.MdataPayload_Patch:
+		payload.Action = "patch"
+		payload.Gtin = action.Patch.GetGtin()
+		payload.UnsetKeys = action.Patch.GetUnsetKeys()
+		attributes = action.Patch.GetAttributes()
+		payload.ExpectedRevision = action.Patch.GetExpectedRevision()
+	case *payload_pb2.MdataPayload_CreateOrganization:
+		payload.Action = "org_create"
+		payload.OrganizationId = action.CreateOrganization.GetId()
+		payload.OrganizationName = action.CreateOrganization.GetName()
+		payload.CompanyPrefixes = action.CreateOrganization.GetCompanyPrefixes()
+	case *payload_pb2.MdataPayload_UpdateOrganization:
+		payload.Action = "org_update"
+		payload.OrganizationId = action.UpdateOrganization.GetId()
+		payload.OrganizationName = action.UpdateOrganization.GetName()
+		payload.CompanyPrefixes = action.UpdateOrganization.GetCompanyPrefixes()
+	case *payload_pb2.MdataPayload_AddOrganizationKey:
+		payload.Action = "org_add_key"
+		payload.OrganizationId = action.AddOrganizationKey.GetId()
+		payload.PublicKey = action.AddOrganizationKey.GetPublicKey()
+	case *payload_pb2.MdataPayload_RemoveOrganizationKey:
+		payload.Action = "org_remove_key"
+		payload.OrganizationId = action.RemoveOrganizationKey.GetId()
+		payload.PublicKey = action.RemoveOrganizationKey.GetPublicKey()
+	case *payload_pb2.MdataPayload_CreateAgent:
+		payload.Action = "agent_create"
+		payload.PublicKey = action.CreateAgent.GetPublicKey()
+		payload.OrganizationId = action.CreateAgent.GetOrganizationId()
+		payload.Active = action.CreateAgent.GetActive()
+		payload.Roles = action.CreateAgent.GetRoles()
+	case *payload_pb2.MdataPayload_UpdateAgent:
+		payload.Action = "agent_update"
+		payload.PublicKey = action.UpdateAgent.GetPublicKey()
+		payload.Active = action.UpdateAgent.GetActive()
+		payload.Roles = action.UpdateAgent.GetRoles()
+	case *payload_pb2.MdataPayload_CreateSchema:
+		payload.Action = "schema_create"
+		payload.OrganizationId = action.CreateSchema.GetOrganizationId()
+		payload.Schema = &data.Schema{
+			Name:       action.CreateSchema.GetName(),
+			Owner:      action.CreateSchema.GetOrganizationId(),
+			Properties: data.PropertiesFromProto(action.CreateSchema.GetProperties()),
+		}
+	case *payload_pb2.MdataPayload_UpdateSchema:
+		payload.Action = "schema_update"
+		payload.Schema = &data.Schema{
+			Name:       action.UpdateSchema.GetName(),
+			Properties: data.PropertiesFromProto(action.UpdateSchema.GetProperties()),
+		}
+	case *payload_pb2.MdataPayload_Batch:
+		payload.Action = "batch"
+		for i, op := range action.Batch.GetOperations() {
+			operation, err := fromOperation(op)
+			if err != nil {
+				return nil, operationError(i, err)
+			}
+			payload.Operations = append(payload.Operations, operation)
+		}
+	}
+
+	var err error
+	payload.Attributes, err = data.AttributesFromProto(attributes)
+	if err != nil {
+		return nil, &processor.InvalidTransactionError{Msg: fmt.Sprintf("Invalid attributes: %v", err)}
+	}
+
+	return payload.validate()
+}
+
+// fromOperation decodes one operation of a batch. It is validated with the
+// batch.
+func fromOperation(op *payload_pb2.ProductOperation) (*MdPayload, error) {
+	payload := MdPayload{}
+	var attributes []*payload_pb2.Attribute
+	switch action := op.GetAction().(type) {
+	case *payload_pb2.ProductOperation_Create:
+		payload.Action = "create"
+		payload.Gtin = action.Create.GetGtin()
+		attributes = action.Create.GetAttributes()
+	case *payload_pb2.ProductOperation_Update:
+		payload.Action = "update"
+		payload.Gtin = action.Update.GetGtin()
+		attributes = action.Update.GetAttributes()
+		payload.ExpectedRevision = action.Update.GetExpectedRevision()
+	case *payload_pb2.ProductOperation_Set:
+		payload.Action = "set"
+		payload.Gtin = action.Set.GetGtin()
+		payload.State = action.Set.GetState()
+		payload.Reason = action.Set.GetReason()
+		payload.ExpectedRevision = action.Set.GetExpectedRevision()
+	case *payload_pb2.ProductOperation_Delete:
+		payload.Action = "delete"
+		payload.Gtin = action.Delete.GetGtin()
+		payload.ExpectedRevision = action.Delete.GetExpectedRevision()
+	}
+
+	var err error
+	payload.Attributes, err = data.AttributesFromProto(attributes)
+	if err != nil {
+		return nil, &processor.InvalidTransactionError{Msg: fmt.Sprintf("Invalid attributes: %v", err)}
+	}
+	return &payload, nil
+}
+
+// operationError prefixes the error of the i-th operation of a batch with its
+// position, counting from 1
+func operationError(i int, err error) error {
+	if invalid, ok := err.(*processor.InvalidTransactionError); ok {
+		return &processor.InvalidTransactionError{Msg: fmt.Sprintf("Operation %v: %v", i+1, invalid.Msg)}
+	}
+	return err
+}
+
+// validate runs the checks of a decoded payload, from FromBytes or
+// FromProtobuf, and normalizes its GTIN
+func (payload *MdPayload) validate() (*MdPayload, error) {
 	if len(payload.Action) < 1 {
 		return nil, &processor.InvalidTransactionError{Msg: "Action is required"}
 	}
 
-	if payload.invalidGtin() {
-		return nil, &processor.InvalidTransactionError{Msg: "Gtin-14 is required"}
+	if payload.IsOrganizationAction() {
+		return payload.validateOrganization()
+	}
+	if payload.IsAgentAction() {
+		return payload.validateAgent()
+	}
+	if payload.IsSchemaAction() {
+		return payload.validateSchema()
+	}
+	if payload.Action == "batch" {
+		return payload.validateBatch()
 	}
 
+	// GTIN-8, GTIN-12 and GTIN-13 are stored under their GTIN-14 form
+	gtin, err := gs1.NormalizeGtin(payload.Gtin)
+	if err != nil {
+		return nil, &processor.InvalidTransactionError{Msg: err.Error()}
+	}
+	payload.Gtin = gtin
+
 	if payload.Action == "update" {
-		if len(payload.Attributes) < 1 || payload.Attributes[0] == "" {
+		if len(payload.Attributes) < 1 {
 			return nil, &processor.InvalidTransactionError{Msg: "Attributes are required for update"}
 		}
 	}
 
-	if payload.invalidAttributes() {
-		return nil, &processor.InvalidTransactionError{
-			Msg: fmt.Sprintf("Invalid attributes (attributes must be in key=value pairs): %v", payload.Attributes)}
+	if payload.Action == "patch" {
+		if len(payload.Attributes) < 1 && len(payload.UnsetKeys) < 1 {
+			return nil, &processor.InvalidTransactionError{Msg: "Attributes to set or unset are required for patch"}
+		}
+		unset := make(map[string]bool)
+		for _, key := range payload.UnsetKeys {
+			if _, ok := payload.Attributes[key]; ok {
+				return nil, &processor.InvalidTransactionError{
+					Msg: fmt.Sprintf("Attribute '%v' can not be both set and unset", key)}
+			}
+			if unset[key] {
+				return nil, &processor.InvalidTransactionError{
+					Msg: fmt.Sprintf("Duplicate unset attribute: '%v'", key)}
+			}
+			unset[key] = true
+		}
+	}
+
+	if payload.Action == "transfer" {
+		if invalidPublicKey(payload.NewOwner) {
+			return nil, &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Invalid new owner (must be a hex encoded public key), GOT: '%v'", payload.NewOwner)}
+		}
 	}
 
 	if payload.Action == "set" {
@@ -132,17 +355,103 @@ func FromBytes(payloadData []byte) (*MdPayload, error) {
 			return nil, &processor.InvalidTransactionError{Msg: "State is required to set"}
 		}
 
-		if payload.invalidState() {
+		// The state itself is checked against the product lifecycle, which
+		// is kept in on-chain settings
+		if err := data.ValidateReason(payload.Reason); err != nil {
+			return nil, &processor.InvalidTransactionError{Msg: err.Error()}
+		}
+	}
+
+	return payload, nil
+}
+
+func (payload *MdPayload) validateOrganization() (*MdPayload, error) {
+	if len(payload.OrganizationId) < 1 {
+		return nil, &processor.InvalidTransactionError{Msg: "Organization id is required"}
+	}
+
+	if payload.Action == "org_create" || payload.Action == "org_update" {
+		if len(payload.OrganizationName) < 1 {
+			return nil, &processor.InvalidTransactionError{Msg: "Organization name is required"}
+		}
+
+		seen := make(map[string]bool)
+		for _, prefix := range payload.CompanyPrefixes {
+			if err := gs1.ValidateCompanyPrefix(prefix); err != nil {
+				return nil, &processor.InvalidTransactionError{Msg: err.Error()}
+			}
+			if seen[prefix] {
+				return nil, &processor.InvalidTransactionError{
+					Msg: fmt.Sprintf("Duplicate company prefix: '%v'", prefix)}
+			}
+			seen[prefix] = true
+		}
+	}
+
+	if payload.Action == "org_add_key" || payload.Action == "org_remove_key" {
+		if invalidPublicKey(payload.PublicKey) {
 			return nil, &processor.InvalidTransactionError{
-				Msg: fmt.Sprintf("Invalid state (state must be one of ACTIVE, INACTIVE, DISCONTINUED), GOT: %v", payload.State)}
+				Msg: fmt.Sprintf("Invalid public key (must be a hex encoded public key), GOT: '%v'", payload.PublicKey)}
 		}
 	}
 
-	isInvalid, invalidString := payload.invaildChar()
-	if isInvalid {
+	return payload, nil
+}
+
+func (payload *MdPayload) validateAgent() (*MdPayload, error) {
+	if invalidPublicKey(payload.PublicKey) {
 		return nil, &processor.InvalidTransactionError{
-			Msg: fmt.Sprintf("Invalid Name (char '|' not allowed): '%v'", invalidString)}
+			Msg: fmt.Sprintf("Invalid public key (must be a hex encoded public key), GOT: '%v'", payload.PublicKey)}
 	}
 
-	return &payload, nil
+	if payload.Action == "agent_create" && len(payload.OrganizationId) < 1 {
+		return nil, &processor.InvalidTransactionError{Msg: "Organization id is required"}
+	}
+
+	seen := make(map[string]bool)
+	for _, role := range payload.Roles {
+		if !data.IsRole(role) {
+			return nil, &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Invalid role (role must be one of %v), GOT: '%v'", strings.Join(data.Roles, ", "), role)}
+		}
+		if seen[role] {
+			return nil, &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Duplicate role: '%v'", role)}
+		}
+		seen[role] = true
+	}
+
+	return payload, nil
+}
+
+func (payload *MdPayload) validateSchema() (*MdPayload, error) {
+	if payload.Action == "schema_create" && len(payload.OrganizationId) < 1 {
+		return nil, &processor.InvalidTransactionError{Msg: "Organization id is required"}
+	}
+	if err := payload.Schema.CheckDefinition(); err != nil {
+		return nil, &processor.InvalidTransactionError{Msg: err.Error()}
+	}
+	return payload, nil
+}
+
+func (payload *MdPayload) validateBatch() (*MdPayload, error) {
+	if len(payload.Operations) < 1 {
+		return nil, &processor.InvalidTransactionError{Msg: "At least one operation is required for batch"}
+	}
+	if len(payload.Operations) > MaxOperations {
+		return nil, &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Too many operations in batch (at most %v), GOT: %v", MaxOperations, len(payload.Operations))}
+	}
+	for i, operation := range payload.Operations {
+		switch operation.Action {
+		case "create", "update", "set", "delete":
+		default:
+			return nil, operationError(i, &processor.InvalidTransactionError{
+				Msg: fmt.Sprintf("Invalid batch operation, GOT: '%v'", operation.Action)})
+		}
+		if _, err := operation.validate(); err != nil {
+			return nil, operationError(i, err)
+		}
+	}
+	return payload, nil
 }
@@ -1,7 +1,11 @@
 package mdata_payload
 
 import (
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/tross-tyson/mdata_go/src/shared/data"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/payload_pb2"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/schema_pb2"
 	"reflect"
 	"testing"
 )
@@ -21,7 +25,7 @@ var testPayloads = map[string]struct {
 	5. Valid Attributes => Ok
 	6. Update with Attributes => Ok
 	7. Update with len(Attributes) < 1  => Err
-	8. Invalid character '|'
+	8. Character '|' in attributes => Ok
 	*/
 	//Input, expected return MdPayload, expected return Error
 	"nullPayload": { //Null payload => Err
@@ -46,12 +50,12 @@ var testPayloads = map[string]struct {
 	},
 	"validAttributesCreate": { //Create with valid Attributes => Ok
 		in:         []byte("create,00012345600012,uom=cases,"),
-		outPayload: &MdPayload{Action: "create", Gtin: "00012345600012", Attributes: []string{"uom=lbs"}},
+		outPayload: &MdPayload{Action: "create", Gtin: "00012345600012", Attributes: data.Attributes{"uom": "cases"}},
 		outError:   nil,
 	},
 	"validAttributesUpdate": { //Update with Attributes => Ok
 		in:         []byte("update,00012345600012,uom=lbs,weight=300,"),
-		outPayload: &MdPayload{Action: "update", Gtin: "00012345600012", Attributes: []string{"uom=lbs", "weight=300"}},
+		outPayload: &MdPayload{Action: "update", Gtin: "00012345600012", Attributes: data.Attributes{"uom": "lbs", "weight": "300"}},
 		outError:   nil,
 	},
 	"noAttributesUpdate": { // Update with len(Attributes) < 1  => Err
@@ -64,16 +68,41 @@ var testPayloads = map[string]struct {
 		outPayload: nil,
 		outError:   &sampleError,
 	},
+	"badCheckDigit": { //GTIN with wrong check digit => Err
+		in:         []byte("create,00012345600013,uom=cases,"),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"negativeGtin": { //Negative number is not a GTIN => Err
+		in:         []byte("create,-1234567890123,,"),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"gtin12": { //GTIN-12 is normalized to GTIN-14 => Ok
+		in:         []byte("create,012345678905,,"),
+		outPayload: &MdPayload{Action: "create", Gtin: "00012345678905"},
+		outError:   nil,
+	},
 	"set": { //Set state to INACTIVE => OK
 		in:         []byte("set,00012345600012,,INACTIVE"),
 		outPayload: &MdPayload{Action: "set", Gtin: "00012345600012", State: "INACTIVE"},
 		outError:   nil,
 	},
-	"invalidCharAttr": { //Invalid character '|'  => Err
-		in:         []byte("update,00012345600012,uom=lbs,weight=3|00,"),
+	"transferAction": { //Transfer needs family version 2.0 => Err
+		in:         []byte("transfer,00012345600012,,"),
 		outPayload: nil,
 		outError:   &sampleError,
 	},
+	"organizationAction": { //Organization actions need family version 2.0 => Err
+		in:         []byte("org_create,acme,,"),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"pipeCharAttr": { //Character '|' is allowed in attributes => Ok
+		in:         []byte("update,00012345600012,uom=lbs,name=wings|hot,"),
+		outPayload: &MdPayload{Action: "update", Gtin: "00012345600012", Attributes: data.Attributes{"uom": "lbs", "name": "wings|hot"}},
+		outError:   nil,
+	},
 }
 
 func compareExpectedActualError(expectedErr error, actualError error) bool {
@@ -116,6 +145,12 @@ func compareStructs(expected, actual MdPayload) bool {
 			if ev != av {
 				return false
 			}
+		case reflect.Uint64:
+			ev := expected_value.Uint()
+			av := actual_value.Uint()
+			if ev != av {
+				return false
+			}
 		}
 
 	}
@@ -145,3 +180,315 @@ func TestFromBytes(t *testing.T) {
 		}
 	}
 }
+
+func marshalPayload(pb *payload_pb2.MdataPayload) []byte {
+	b, err := proto.Marshal(pb)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var testPublicKey = "02a1b2c3d4e5f60718293a4b5c6d7e8f9010a1b2c3d4e5f60718293a4b5c6d7e8f"
+
+var testProtobufPayloads = map[string]struct {
+	in         []byte
+	outPayload *MdPayload
+	outError   error
+}{
+	"nullPayload": {
+		in:         nil,
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"malformedPayload": {
+		in:         []byte{0x0a, 0x05},
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"missingAction": {
+		in:         marshalPayload(&payload_pb2.MdataPayload{}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"missingGtinCreate": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Create{Create: &payload_pb2.CreateProductAction{}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"validAttributesCreate": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Create{Create: &payload_pb2.CreateProductAction{
+				Gtin:       "00012345600012",
+				Attributes: data.Attributes{"uom": "cases", "weight": int64(300)}.ToProto(),
+			}}}),
+		outPayload: &MdPayload{Action: "create", Gtin: "00012345600012", Attributes: data.Attributes{"uom": "cases", "weight": int64(300)}},
+		outError:   nil,
+	},
+	"invalidCharGtin": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Create{Create: &payload_pb2.CreateProductAction{Gtin: "000123|45600012"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"badCheckDigit": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Create{Create: &payload_pb2.CreateProductAction{Gtin: "00012345600013"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"negativeGtin": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Create{Create: &payload_pb2.CreateProductAction{Gtin: "-1234567890123"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"gtin12": { // GTIN-12 is normalized to GTIN-14
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Create{Create: &payload_pb2.CreateProductAction{Gtin: "012345678905"}}}),
+		outPayload: &MdPayload{Action: "create", Gtin: "00012345678905"},
+		outError:   nil,
+	},
+	"noAttributesUpdate": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Update{Update: &payload_pb2.UpdateProductAction{Gtin: "00012345600012"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"attributeWithoutValue": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Update{Update: &payload_pb2.UpdateProductAction{
+				Gtin:       "00012345600012",
+				Attributes: []*payload_pb2.Attribute{{Key: "uom"}},
+			}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"separatorCharsAttr": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Update{Update: &payload_pb2.UpdateProductAction{
+				Gtin:       "00012345600012",
+				Attributes: data.Attributes{"name": "wings, hot=spicy|large"}.ToProto(),
+			}}}),
+		outPayload: &MdPayload{Action: "update", Gtin: "00012345600012", Attributes: data.Attributes{"name": "wings, hot=spicy|large"}},
+		outError:   nil,
+	},
+	"updateExpectedRevision": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Update{Update: &payload_pb2.UpdateProductAction{
+				Gtin:             "00012345600012",
+				Attributes:       data.Attributes{"uom": "lbs"}.ToProto(),
+				ExpectedRevision: 3,
+			}}}),
+		outPayload: &MdPayload{Action: "update", Gtin: "00012345600012", Attributes: data.Attributes{"uom": "lbs"}, ExpectedRevision: 3},
+		outError:   nil,
+	},
+	"set": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Set{Set: &payload_pb2.SetProductStateAction{Gtin: "00012345600012", State: "INACTIVE"}}}),
+		outPayload: &MdPayload{Action: "set", Gtin: "00012345600012", State: "INACTIVE"},
+		outError:   nil,
+	},
+	"setReason": { // States are checked against the lifecycle by the handler
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Set{Set: &payload_pb2.SetProductStateAction{Gtin: "00012345600012", State: "RECALLED", Reason: "supplier.recall"}}}),
+		outPayload: &MdPayload{Action: "set", Gtin: "00012345600012", State: "RECALLED", Reason: "supplier.recall"},
+		outError:   nil,
+	},
+	"noState": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Set{Set: &payload_pb2.SetProductStateAction{Gtin: "00012345600012"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"invalidReason": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Set{Set: &payload_pb2.SetProductStateAction{Gtin: "00012345600012", State: "INACTIVE", Reason: "no longer sold"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"transfer": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Transfer{Transfer: &payload_pb2.TransferProductAction{Gtin: "00012345600012", NewOwner: testPublicKey}}}),
+		outPayload: &MdPayload{Action: "transfer", Gtin: "00012345600012", NewOwner: testPublicKey},
+		outError:   nil,
+	},
+	"transferInvalidOwner": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Transfer{Transfer: &payload_pb2.TransferProductAction{Gtin: "00012345600012", NewOwner: "not-a-key"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"createOrganization": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_CreateOrganization{CreateOrganization: &payload_pb2.CreateOrganizationAction{
+				Id: "acme", Name: "Acme Foods", CompanyPrefixes: []string{"0012345"}}}}),
+		outPayload: &MdPayload{Action: "org_create", OrganizationId: "acme", OrganizationName: "Acme Foods"},
+		outError:   nil,
+	},
+	"createOrganizationNoName": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_CreateOrganization{CreateOrganization: &payload_pb2.CreateOrganizationAction{
+				Id: "acme", CompanyPrefixes: []string{"0012345"}}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"updateOrganizationBadPrefix": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_UpdateOrganization{UpdateOrganization: &payload_pb2.UpdateOrganizationAction{
+				Id: "acme", Name: "Acme Foods", CompanyPrefixes: []string{"12"}}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"addOrganizationKey": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_AddOrganizationKey{AddOrganizationKey: &payload_pb2.AddOrganizationKeyAction{
+				Id: "acme", PublicKey: testPublicKey}}}),
+		outPayload: &MdPayload{Action: "org_add_key", OrganizationId: "acme", PublicKey: testPublicKey},
+		outError:   nil,
+	},
+	"removeOrganizationKeyInvalid": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_RemoveOrganizationKey{RemoveOrganizationKey: &payload_pb2.RemoveOrganizationKeyAction{
+				Id: "acme", PublicKey: "02ab"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"createAgent": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_CreateAgent{CreateAgent: &payload_pb2.CreateAgentAction{
+				PublicKey: testPublicKey, OrganizationId: "acme", Active: true, Roles: []string{"product.create"}}}}),
+		outPayload: &MdPayload{Action: "agent_create", PublicKey: testPublicKey, OrganizationId: "acme", Active: true},
+		outError:   nil,
+	},
+	"createAgentNoOrganization": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_CreateAgent{CreateAgent: &payload_pb2.CreateAgentAction{
+				PublicKey: testPublicKey, Active: true}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"updateAgentInvalidRole": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_UpdateAgent{UpdateAgent: &payload_pb2.UpdateAgentAction{
+				PublicKey: testPublicKey, Active: true, Roles: []string{"product.transfer"}}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"createSchema": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_CreateSchema{CreateSchema: &payload_pb2.CreateSchemaAction{
+				Name: "product", OrganizationId: "acme", Properties: []*schema_pb2.PropertyDefinition{
+					{Name: "uom", DataType: schema_pb2.PropertyDefinition_ENUM, EnumOptions: []string{"cases", "lbs"}}}}}}),
+		outPayload: &MdPayload{Action: "schema_create", OrganizationId: "acme"},
+		outError:   nil,
+	},
+	"updateSchemaEnumNoOptions": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_UpdateSchema{UpdateSchema: &payload_pb2.UpdateSchemaAction{
+				Name: "product", Properties: []*schema_pb2.PropertyDefinition{
+					{Name: "uom", DataType: schema_pb2.PropertyDefinition_ENUM}}}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"patch": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Patch{Patch: &payload_pb2.PatchProductAction{
+				Gtin:       "25825825825824",
+				Attributes: data.Attributes{"uom": "lbs"}.ToProto(),
+				UnsetKeys:  []string{"weight"}}}}),
+		outPayload: &MdPayload{Action: "patch", Gtin: "25825825825824", Attributes: data.Attributes{"uom": "lbs"}},
+		outError:   nil,
+	},
+	"patchUnsetOnly": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Patch{Patch: &payload_pb2.PatchProductAction{
+				Gtin:      "25825825825824",
+				UnsetKeys: []string{"weight"}}}}),
+		outPayload: &MdPayload{Action: "patch", Gtin: "25825825825824"},
+		outError:   nil,
+	},
+	"patchEmpty": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Patch{Patch: &payload_pb2.PatchProductAction{
+				Gtin: "25825825825824"}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"patchSetAndUnset": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Patch{Patch: &payload_pb2.PatchProductAction{
+				Gtin:       "25825825825824",
+				Attributes: data.Attributes{"uom": "lbs"}.ToProto(),
+				UnsetKeys:  []string{"uom"}}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"delete": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Delete{Delete: &payload_pb2.DeleteProductAction{Gtin: "00012345600012"}}}),
+		outPayload: &MdPayload{Action: "delete", Gtin: "00012345600012"},
+		outError:   nil,
+	},
+	"batch": { // Every operation is validated, GTINs are normalized
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Batch{Batch: &payload_pb2.BatchProductAction{
+				Operations: []*payload_pb2.ProductOperation{
+					{Action: &payload_pb2.ProductOperation_Create{Create: &payload_pb2.CreateProductAction{
+						Gtin: "012345600012", Attributes: data.Attributes{"uom": "cases"}.ToProto()}}},
+					{Action: &payload_pb2.ProductOperation_Set{Set: &payload_pb2.SetProductStateAction{
+						Gtin: "00012345600012", State: "INACTIVE", ExpectedRevision: 1}}},
+				}}}}),
+		outPayload: &MdPayload{Action: "batch", Operations: []*MdPayload{
+			{Action: "create", Gtin: "00012345600012", Attributes: data.Attributes{"uom": "cases"}},
+			{Action: "set", Gtin: "00012345600012", State: "INACTIVE", ExpectedRevision: 1},
+		}},
+		outError: nil,
+	},
+	"batchEmpty": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Batch{Batch: &payload_pb2.BatchProductAction{}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"batchInvalidOperation": { // One invalid operation rejects the batch
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Batch{Batch: &payload_pb2.BatchProductAction{
+				Operations: []*payload_pb2.ProductOperation{
+					{Action: &payload_pb2.ProductOperation_Delete{Delete: &payload_pb2.DeleteProductAction{Gtin: "00012345600012"}}},
+					{Action: &payload_pb2.ProductOperation_Update{Update: &payload_pb2.UpdateProductAction{Gtin: "00012345600012"}}},
+				}}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+	"batchMissingAction": {
+		in: marshalPayload(&payload_pb2.MdataPayload{
+			Action: &payload_pb2.MdataPayload_Batch{Batch: &payload_pb2.BatchProductAction{
+				Operations: []*payload_pb2.ProductOperation{{}}}}}),
+		outPayload: nil,
+		outError:   &sampleError,
+	},
+}
+
+func TestFromProtobuf(t *testing.T) {
+	for name, test := range testProtobufPayloads {
+		t.Logf("Running test case: %s", name)
+		payload, err := FromProtobuf(test.in)
+		if compareExpectedActualPayload(test.outPayload, payload) != true || compareExpectedActualError(test.outError, err) != true {
+			t.Errorf("Test Case Failure %v \n FromProtobuf(%v) => GOT %v, %v, WANT %v, %v", name, test.in, payload, err, test.outPayload, test.outError)
+		}
+		if test.outPayload != nil && payload != nil && len(test.outPayload.Attributes) > 0 && !reflect.DeepEqual(test.outPayload.Attributes, payload.Attributes) {
+			t.Errorf("Test Case Failure %v \n Attributes => GOT %v, WANT %v", name, payload.Attributes, test.outPayload.Attributes)
+		}
+		if test.outPayload != nil && payload != nil && len(test.outPayload.Operations) != len(payload.Operations) {
+			t.Errorf("Test Case Failure %v \n Operations => GOT %v, WANT %v", name, len(payload.Operations), len(test.outPayload.Operations))
+		} else if test.outPayload != nil && payload != nil {
+			for i, operation := range test.outPayload.Operations {
+				if !compareExpectedActualPayload(operation, payload.Operations[i]) {
+					t.Errorf("Test Case Failure %v \n Operation %v => GOT %v, WANT %v", name, i, payload.Operations[i], operation)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package mdata_state
+
+import (
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+func (self *MdState) GetAgent(publicKey string) (*_data.Agent, error) {
+	agents, err := self.loadAgents(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	agent, ok := agents[publicKey]
+	if ok {
+		return agent, nil
+	}
+	return nil, nil
+}
+
+func (self *MdState) SetAgent(publicKey string, agent *_data.Agent) error {
+	agents, err := self.loadAgents(publicKey)
+	if err != nil {
+		return err
+	}
+	agents[publicKey] = agent
+
+	var a []*_data.Agent
+	for _, agent := range agents {
+		a = append(a, agent)
+	}
+	return self.storeAddress(address.MakeAgentAddress(publicKey), _data.SerializeAgents(a))
+}
+
+func (self *MdState) loadAgents(publicKey string) (map[string]*_data.Agent, error) {
+	data, err := self.loadAddress(address.MakeAgentAddress(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return make(map[string]*_data.Agent), nil
+	}
+	return _data.DeserializeAgents(data)
+}
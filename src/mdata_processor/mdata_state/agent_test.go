@@ -0,0 +1,56 @@
+package mdata_state
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+	"testing"
+)
+
+var testAgent _data.Agent = _data.Agent{
+	PublicKey:      "02dd",
+	OrganizationId: "acme",
+	Active:         true,
+	Roles:          []string{_data.RoleProductCreate},
+}
+var testAgentAddress string = address.MakeAgentAddress(testAgent.PublicKey)
+
+func TestGetAgent(t *testing.T) {
+
+	tests := map[string]struct {
+		publicKey string
+		outAgent  *_data.Agent
+	}{
+		"existingAgent": {
+			publicKey: "02dd",
+			outAgent:  &testAgent,
+		},
+		"noAgent": {
+			publicKey: "02ff",
+			outAgent:  nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		testContext := &MockContext{}
+		testContext.On("GetState", []string{testAgentAddress}).Return(
+			map[string][]byte{
+				testAgentAddress: _data.SerializeAgents([]*_data.Agent{&testAgent}),
+			},
+			nil,
+		)
+		testContext.On("GetState", mock.Anything).Return(map[string][]byte{}, nil)
+
+		testState := &MdState{
+			context:      testContext,
+			addressCache: make(map[string][]byte),
+		}
+
+		agent, err := testState.GetAgent(test.publicKey)
+		assert.Nil(t, err)
+		assert.Equal(t, test.outAgent, agent)
+	}
+}
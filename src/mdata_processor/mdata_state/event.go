@@ -0,0 +1,30 @@
+package mdata_state
+
+import (
+	"strconv"
+
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// AddProductEvent sends the event of a product change, with the product as its
+// data, and adds the receipt to the transaction. Both are dropped if the
+// transaction is invalid.
+func (self *MdState) AddProductEvent(receipt *_data.ProductReceipt, signer string, product *_data.Product) error {
+	attributes := []processor.Attribute{
+		{Key: _data.EventAttributeGtin, Value: receipt.Gtin},
+		{Key: _data.EventAttributeAction, Value: receipt.Action},
+		{Key: _data.EventAttributeSigner, Value: signer},
+		{Key: _data.EventAttributeState, Value: receipt.State},
+		{Key: _data.EventAttributeRevision, Value: strconv.FormatUint(receipt.Revision, 10)},
+	}
+	for _, field := range receipt.Changed {
+		attributes = append(attributes, processor.Attribute{Key: _data.EventAttributeChanged, Value: field})
+	}
+
+	err := self.context.AddEvent(_data.ProductEventType(receipt.Action), attributes, product.GetJson())
+	if err != nil {
+		return err
+	}
+	return self.context.AddReceiptData(receipt.GetJson())
+}
@@ -0,0 +1,34 @@
+package mdata_state
+
+import (
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/stretchr/testify/assert"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+	"testing"
+)
+
+func TestAddProductEvent(t *testing.T) {
+	product := &_data.Product{Gtin: "00012345600012", Attributes: _data.Attributes{"uom": "lbs"}, State: "ACTIVE", Owner: "02aa", Revision: 2}
+	receipt := &_data.ProductReceipt{Action: "update", Gtin: product.Gtin, Revision: 2, State: "ACTIVE", Changed: []string{"attributes.name", "attributes.uom"}}
+
+	testContext := &MockContext{}
+	testContext.On("AddEvent", _data.EventProductUpdated, []processor.Attribute{
+		{Key: "gtin", Value: "00012345600012"},
+		{Key: "action", Value: "update"},
+		{Key: "signer", Value: "02bb"},
+		{Key: "state", Value: "ACTIVE"},
+		{Key: "revision", Value: "2"},
+		{Key: "changed", Value: "attributes.name"},
+		{Key: "changed", Value: "attributes.uom"},
+	}, product.GetJson()).Return(nil)
+	testContext.On("AddReceiptData", receipt.GetJson()).Return(nil)
+
+	testState := &MdState{
+		context:      testContext,
+		addressCache: make(map[string][]byte),
+	}
+
+	err := testState.AddProductEvent(receipt, "02bb", product)
+	assert.Nil(t, err)
+	testContext.AssertExpectations(t)
+}
@@ -0,0 +1,36 @@
+package mdata_state
+
+import (
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// GetHistory returns the revisions of a GTIN, oldest first
+func (self *MdState) GetHistory(gtin string) ([]*_data.ProductRevision, error) {
+	histories, err := self.loadHistories(gtin)
+	if err != nil {
+		return nil, err
+	}
+	return histories[gtin], nil
+}
+
+// AddRevision appends a revision to the history of a GTIN
+func (self *MdState) AddRevision(gtin string, revision *_data.ProductRevision) error {
+	histories, err := self.loadHistories(gtin)
+	if err != nil {
+		return err
+	}
+	histories[gtin] = append(histories[gtin], revision)
+	return self.storeAddress(address.MakeHistoryAddress(gtin), _data.SerializeHistories(histories))
+}
+
+func (self *MdState) loadHistories(gtin string) (map[string][]*_data.ProductRevision, error) {
+	data, err := self.loadAddress(address.MakeHistoryAddress(gtin))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return make(map[string][]*_data.ProductRevision), nil
+	}
+	return _data.DeserializeHistories(data)
+}
@@ -0,0 +1,40 @@
+package mdata_state
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+	"testing"
+)
+
+func TestAddRevision(t *testing.T) {
+	gtin := "00012345600012"
+	historyAddress := address.MakeHistoryAddress(gtin)
+	first := &_data.ProductRevision{Revision: 1, Action: "create", Signer: "02aa", TransactionId: "txn1", Changed: []string{"state"}, Previous: _data.Attributes{}}
+	second := &_data.ProductRevision{Revision: 2, Action: "set", Signer: "02aa", TransactionId: "txn2", Changed: []string{"state"}, Previous: _data.Attributes{"state": "ACTIVE"}}
+
+	testContext := &MockContext{}
+	testContext.On("GetState", []string{historyAddress}).Return(
+		map[string][]byte{
+			historyAddress: _data.SerializeHistories(map[string][]*_data.ProductRevision{gtin: {first}}),
+		},
+		nil,
+	)
+	testContext.On("SetState", mock.Anything).Return([]string{historyAddress}, nil)
+
+	testState := &MdState{
+		context:      testContext,
+		addressCache: make(map[string][]byte),
+	}
+
+	err := testState.AddRevision(gtin, second)
+	assert.Nil(t, err)
+	testContext.AssertCalled(t, "SetState", map[string][]byte{
+		historyAddress: _data.SerializeHistories(map[string][]*_data.ProductRevision{gtin: {first, second}}),
+	})
+
+	history, err := testState.GetHistory(gtin)
+	assert.Nil(t, err)
+	assert.Equal(t, []*_data.ProductRevision{first, second}, history)
+}
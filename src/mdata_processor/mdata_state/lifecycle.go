@@ -0,0 +1,42 @@
+package mdata_state
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/setting_pb2"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+// GetLifecycle returns the product lifecycle set on-chain with the sawtooth
+// settings family, or the default lifecycle if it is not set. The setting's
+// address must be among the transaction's inputs. A setting that can not be
+// read makes every transaction using the lifecycle invalid until it is fixed.
+func (self *MdState) GetLifecycle() (*_data.Lifecycle, error) {
+	data, err := self.loadAddress(address.MakeSettingAddress(_data.LifecycleSetting))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return &_data.DefaultLifecycle, nil
+	}
+
+	setting := &setting_pb2.Setting{}
+	if err := proto.Unmarshal(data, setting); err != nil {
+		return nil, &processor.InvalidTransactionError{
+			Msg: fmt.Sprintf("Malformed %v setting: %v", _data.LifecycleSetting, err)}
+	}
+	for _, entry := range setting.GetEntries() {
+		if entry.GetKey() == _data.LifecycleSetting {
+			lifecycle, err := _data.ParseLifecycle(entry.GetValue())
+			if err != nil {
+				return nil, &processor.InvalidTransactionError{
+					Msg: fmt.Sprintf("Invalid %v setting: %v", _data.LifecycleSetting, err)}
+			}
+			return lifecycle, nil
+		}
+	}
+	return &_data.DefaultLifecycle, nil
+}
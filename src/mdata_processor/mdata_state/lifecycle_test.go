@@ -0,0 +1,67 @@
+package mdata_state
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/sawtooth-sdk-go/protobuf/setting_pb2"
+	"github.com/stretchr/testify/assert"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+	"testing"
+)
+
+func serializeSetting(key string, value string) []byte {
+	b, _ := proto.Marshal(&setting_pb2.Setting{
+		Entries: []*setting_pb2.Setting_Entry{{Key: key, Value: value}},
+	})
+	return b
+}
+
+func TestGetLifecycle(t *testing.T) {
+	settingAddress := address.MakeSettingAddress(_data.LifecycleSetting)
+
+	tests := map[string]struct {
+		setting   []byte
+		outStates []string
+		outError  bool
+	}{
+		"defaultLifecycle": {
+			setting:   nil,
+			outStates: []string{"ACTIVE", "DISCONTINUED", "INACTIVE"},
+		},
+		"settingLifecycle": {
+			setting:   serializeSetting(_data.LifecycleSetting, `{"initial": "DRAFT", "transitions": {"DRAFT": ["PUBLISHED"], "PUBLISHED": []}}`),
+			outStates: []string{"DRAFT", "PUBLISHED"},
+		},
+		"otherSetting": {
+			setting:   serializeSetting("mdata.other", `{}`),
+			outStates: []string{"ACTIVE", "DISCONTINUED", "INACTIVE"},
+		},
+		"invalidSetting": {
+			setting:  serializeSetting(_data.LifecycleSetting, `{"initial": "DRAFT"}`),
+			outError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		testContext := &MockContext{}
+		testContext.On("GetState", []string{settingAddress}).Return(
+			map[string][]byte{settingAddress: test.setting},
+			nil,
+		)
+
+		testState := &MdState{
+			context:      testContext,
+			addressCache: make(map[string][]byte),
+		}
+
+		lifecycle, err := testState.GetLifecycle()
+		if test.outError {
+			assert.NotNil(t, err)
+		} else {
+			assert.Nil(t, err)
+			assert.Equal(t, test.outStates, lifecycle.States())
+		}
+	}
+}
@@ -18,35 +18,37 @@
 package mdata_state
 
 import (
-	"crypto/sha512"
-	"encoding/hex"
 	"sort"
-	"strings"
 
 	"github.com/hyperledger/sawtooth-sdk-go/processor"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
 	_data "github.com/tross-tyson/mdata_go/src/shared/data"
 )
 
-type context interface {
+// Context is the part of a processor.Context the state reads and writes
+// through
+type Context interface {
 	GetState([]string) (map[string][]byte, error)
 	DeleteState([]string) ([]string, error)
 	SetState(map[string][]byte) ([]string, error)
+	AddEvent(string, []processor.Attribute, []byte) error
+	AddReceiptData([]byte) error
 }
 
 /* Namespace prefix is six hex characters, or three bytes
 All data under a namespace prefix follows a consistent address and data encoding/serialization schem that is determined
 by the transaction family which defines the namespace
 */
-var Namespace = hexdigest("mdata")[:6]
+var Namespace = address.Namespace
 
 // MdState handles addressing, serialization, deserialization,
 // and holding an addressCache of data at the address.
 type MdState struct {
-	context      context
+	context      Context
 	addressCache map[string][]byte
 }
 
-func NewMdState(context *processor.Context) *MdState {
+func NewMdState(context Context) *MdState {
 	return &MdState{
 		context:      context,
 		addressCache: make(map[string][]byte),
@@ -91,8 +93,6 @@ func (self *MdState) DeleteProduct(gtin string) error {
 }
 
 func (self *MdState) storeProducts(gtin string, products map[string]*_data.Product) error {
-	address := makeAddress(gtin)
-
 	var gtins []string
 
 	//for each Gtin (key) in map[string]*_data.Product
@@ -107,52 +107,59 @@ func (self *MdState) storeProducts(gtin string, products map[string]*_data.Produ
 		p = append(p, products[gtin])
 	}
 
-	data := _data.Serialize(p)
+	return self.storeAddress(makeAddress(gtin), _data.Serialize(p))
+}
 
-	self.addressCache[address] = data
+func (self *MdState) loadProducts(gtin string) (map[string]*_data.Product, error) {
+	data, err := self.loadAddress(makeAddress(gtin))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return make(map[string]*_data.Product), nil
+	}
+	return _data.Deserialize(data)
+}
 
-	_, err := self.context.SetState(map[string][]byte{
-		address: data,
-	})
-	return err
+func (self *MdState) deleteProducts(gtin string) error {
+	return self.deleteAddress(makeAddress(gtin))
 }
 
-func (self *MdState) loadProducts(gtin string) (map[string]*_data.Product, error) {
-	address := makeAddress(gtin)
+// loadAddress returns the data at an address, reading through the
+// addressCache. An empty address returns nil data.
+func (self *MdState) loadAddress(address string) ([]byte, error) {
 	data, ok := self.addressCache[address]
 	if ok {
-		if self.addressCache[address] != nil {
-			return _data.Deserialize(data)
-		}
-		return make(map[string]*_data.Product), nil
+		return data, nil
 	}
 	results, err := self.context.GetState([]string{address})
 	if err != nil {
 		return nil, err
 	}
-	if len(string(results[address])) > 0 {
+	if len(results[address]) > 0 {
 		self.addressCache[address] = results[address]
-		return _data.Deserialize(results[address])
+		return results[address], nil
 	}
 	self.addressCache[address] = nil
-	products := make(map[string]*_data.Product)
-	return products, nil
+	return nil, nil
 }
 
-func (self *MdState) deleteProducts(gtin string) error {
-	address := makeAddress(gtin)
+func (self *MdState) storeAddress(address string, data []byte) error {
+	self.addressCache[address] = data
 
-	_, err := self.context.DeleteState([]string{address})
+	_, err := self.context.SetState(map[string][]byte{
+		address: data,
+	})
 	return err
 }
 
-func makeAddress(gtin string) string {
-	return Namespace + hexdigest(gtin)[:64]
+func (self *MdState) deleteAddress(address string) error {
+	self.addressCache[address] = nil
+
+	_, err := self.context.DeleteState([]string{address})
+	return err
 }
 
-func hexdigest(str string) string {
-	hash := sha512.New()
-	hash.Write([]byte(str))
-	hashBytes := hash.Sum(nil)
-	return strings.ToLower(hex.EncodeToString(hashBytes))
+func makeAddress(gtin string) string {
+	return address.MakeProductAddress(gtin)
 }
@@ -48,12 +48,17 @@ func TestGetProduct(t *testing.T) {
 			outProduct: &testProduct,
 			err:        nil,
 		},
+		"legacyProduct": {
+			gtin:       testGtin,
+			outProduct: &testProduct,
+			err:        nil,
+		},
 	}
 
 	for name, test := range tests {
 		t.Logf("Running test case: %s", name)
 
-		testContext := &mockContext{}
+		testContext := &MockContext{}
 
 		if name == "existingProduct" {
 			returnState := make(map[string][]byte)
@@ -66,6 +71,15 @@ func TestGetProduct(t *testing.T) {
 				nil,
 			)
 		}
+		if name == "legacyProduct" {
+			returnState := make(map[string][]byte)
+			returnState[testGtinAddress] = []byte(testGtin + ",uom=cases,ACTIVE")
+
+			testContext.On("GetState", []string{testGtinAddress}).Return(
+				returnState,
+				nil,
+			)
+		}
 		if name == "emptyProduct" {
 			testContext.On("GetState", []string{testGtinAddress}).Return(
 				nil,
@@ -115,7 +129,7 @@ func TestSetProduct(t *testing.T) {
 	for name, test := range tests {
 		t.Logf("Running test case: %s", name)
 
-		testContext := &mockContext{}
+		testContext := &MockContext{}
 		testProductSlice := []*_data.Product{&testProduct}
 
 		if name == "newProduct" {
@@ -179,7 +193,7 @@ func TestDeleteProduct(t *testing.T) {
 	for name, test := range tests {
 		t.Logf("Running test case: %s", name)
 
-		testContext := &mockContext{}
+		testContext := &MockContext{}
 
 		testProductSlice := make([]*_data.Product, 2)
 		testProductSlice[0] = &testProduct
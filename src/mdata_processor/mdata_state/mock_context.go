@@ -3,14 +3,43 @@
 package mdata_state
 
 import mock "github.com/stretchr/testify/mock"
+import processor "github.com/hyperledger/sawtooth-sdk-go/processor"
 
-// mockContext is an autogenerated mock type for the context type
-type mockContext struct {
+// MockContext is an autogenerated mock type for the Context type
+type MockContext struct {
 	mock.Mock
 }
 
+// AddEvent provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockContext) AddEvent(_a0 string, _a1 []processor.Attribute, _a2 []byte) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []processor.Attribute, []byte) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddReceiptData provides a mock function with given fields: _a0
+func (_m *MockContext) AddReceiptData(_a0 []byte) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]byte) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteState provides a mock function with given fields: _a0
-func (_m *mockContext) DeleteState(_a0 []string) ([]string, error) {
+func (_m *MockContext) DeleteState(_a0 []string) ([]string, error) {
 	ret := _m.Called(_a0)
 
 	var r0 []string
@@ -33,7 +62,7 @@ func (_m *mockContext) DeleteState(_a0 []string) ([]string, error) {
 }
 
 // GetState provides a mock function with given fields: _a0
-func (_m *mockContext) GetState(_a0 []string) (map[string][]byte, error) {
+func (_m *MockContext) GetState(_a0 []string) (map[string][]byte, error) {
 	ret := _m.Called(_a0)
 
 	var r0 map[string][]byte
@@ -56,7 +85,7 @@ func (_m *mockContext) GetState(_a0 []string) (map[string][]byte, error) {
 }
 
 // SetState provides a mock function with given fields: _a0
-func (_m *mockContext) SetState(_a0 map[string][]byte) ([]string, error) {
+func (_m *MockContext) SetState(_a0 map[string][]byte) ([]string, error) {
 	ret := _m.Called(_a0)
 
 	var r0 []string
@@ -0,0 +1,179 @@
+package mdata_state
+
+import (
+	"strings"
+
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+	"github.com/tross-tyson/mdata_go/src/shared/gs1"
+)
+
+func (self *MdState) GetOrganization(id string) (*_data.Organization, error) {
+	organizations, err := self.loadOrganizations(id)
+	if err != nil {
+		return nil, err
+	}
+	organization, ok := organizations[id]
+	if ok {
+		return organization, nil
+	}
+	return nil, nil
+}
+
+func (self *MdState) SetOrganization(id string, organization *_data.Organization) error {
+	organizations, err := self.loadOrganizations(id)
+	if err != nil {
+		return err
+	}
+	organizations[id] = organization
+
+	var o []*_data.Organization
+	for _, organization := range organizations {
+		o = append(o, organization)
+	}
+	return self.storeAddress(address.MakeOrganizationAddress(id), _data.SerializeOrganizations(o))
+}
+
+func (self *MdState) loadOrganizations(id string) (map[string]*_data.Organization, error) {
+	data, err := self.loadAddress(address.MakeOrganizationAddress(id))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return make(map[string]*_data.Organization), nil
+	}
+	return _data.DeserializeOrganizations(data)
+}
+
+// GetCompanyPrefixOwner returns the id of the organization that owns a
+// company prefix, or "" if no organization has claimed it.
+func (self *MdState) GetCompanyPrefixOwner(prefix string) (string, error) {
+	prefixes, err := self.loadCompanyPrefixes(prefix)
+	if err != nil {
+		return "", err
+	}
+	return prefixes[prefix], nil
+}
+
+// SetCompanyPrefixOwner records the owner of a company prefix. Every shorter
+// company prefix the prefix starts with also records it as a nested claim, so
+// that GetOverlappingCompanyPrefixes finds it from there.
+func (self *MdState) SetCompanyPrefixOwner(prefix string, organizationId string) error {
+	if err := self.setCompanyPrefixEntry(prefix, prefix, organizationId); err != nil {
+		return err
+	}
+	for _, shorter := range shorterCompanyPrefixes(prefix) {
+		if err := self.setCompanyPrefixEntry(shorter, nestedKey(shorter, prefix), organizationId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteCompanyPrefix deletes the company prefix record and its nested claims,
+// handling hash collisions.
+func (self *MdState) DeleteCompanyPrefix(prefix string) error {
+	if err := self.deleteCompanyPrefixEntry(prefix, prefix); err != nil {
+		return err
+	}
+	for _, shorter := range shorterCompanyPrefixes(prefix) {
+		if err := self.deleteCompanyPrefixEntry(shorter, nestedKey(shorter, prefix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetOverlappingCompanyPrefixes returns the claimed company prefixes, other
+// than prefix itself, that prefix starts with or that start with prefix,
+// mapped to the id of the organization owning them.
+func (self *MdState) GetOverlappingCompanyPrefixes(prefix string) (map[string]string, error) {
+	overlapping := make(map[string]string)
+	for _, shorter := range shorterCompanyPrefixes(prefix) {
+		organizationId, err := self.GetCompanyPrefixOwner(shorter)
+		if err != nil {
+			return nil, err
+		}
+		if organizationId != "" {
+			overlapping[shorter] = organizationId
+		}
+	}
+
+	prefixes, err := self.loadCompanyPrefixes(prefix)
+	if err != nil {
+		return nil, err
+	}
+	nested := nestedKey(prefix, "")
+	for key, organizationId := range prefixes {
+		if strings.HasPrefix(key, nested) {
+			overlapping[strings.TrimPrefix(key, nested)] = organizationId
+		}
+	}
+	return overlapping, nil
+}
+
+// GetOrganizationForGtin returns the organization owning the longest company
+// prefix that the GTIN-14 starts with, or nil if there is none.
+func (self *MdState) GetOrganizationForGtin(gtin string) (*_data.Organization, error) {
+	for _, prefix := range gs1.CompanyPrefixCandidates(gtin) {
+		organizationId, err := self.GetCompanyPrefixOwner(prefix)
+		if err != nil {
+			return nil, err
+		}
+		if organizationId != "" {
+			return self.GetOrganization(organizationId)
+		}
+	}
+	return nil, nil
+}
+
+func (self *MdState) setCompanyPrefixEntry(prefix string, key string, organizationId string) error {
+	prefixes, err := self.loadCompanyPrefixes(prefix)
+	if err != nil {
+		return err
+	}
+	prefixes[key] = organizationId
+	return self.storeAddress(address.MakeCompanyPrefixAddress(prefix), _data.SerializeCompanyPrefixes(prefixes))
+}
+
+func (self *MdState) deleteCompanyPrefixEntry(prefix string, key string) error {
+	prefixes, err := self.loadCompanyPrefixes(prefix)
+	if err != nil {
+		return err
+	}
+	if _, ok := prefixes[key]; !ok {
+		return nil
+	}
+	delete(prefixes, key)
+	if len(prefixes) > 0 {
+		return self.storeAddress(address.MakeCompanyPrefixAddress(prefix), _data.SerializeCompanyPrefixes(prefixes))
+	}
+	return self.deleteAddress(address.MakeCompanyPrefixAddress(prefix))
+}
+
+func (self *MdState) loadCompanyPrefixes(prefix string) (map[string]string, error) {
+	data, err := self.loadAddress(address.MakeCompanyPrefixAddress(prefix))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return make(map[string]string), nil
+	}
+	return _data.DeserializeCompanyPrefixes(data)
+}
+
+// nestedKey is the key under which the record of a company prefix lists a
+// longer claimed prefix starting with it.
+func nestedKey(prefix string, longer string) string {
+	return prefix + ">" + longer
+}
+
+// shorterCompanyPrefixes returns the company prefixes, at least
+// gs1.MinCompanyPrefixLength digits long, that prefix starts with.
+func shorterCompanyPrefixes(prefix string) []string {
+	shorter := []string{}
+	for length := gs1.MinCompanyPrefixLength; length < len(prefix); length++ {
+		shorter = append(shorter, prefix[:length])
+	}
+	return shorter
+}
@@ -0,0 +1,104 @@
+package mdata_state
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+	"testing"
+)
+
+var testOrganization _data.Organization = _data.Organization{
+	Id:              "acme",
+	Name:            "Acme Foods",
+	Admins:          []string{"02aa"},
+	CompanyPrefixes: []string{"0012345"},
+}
+var testOrganizationAddress string = address.MakeOrganizationAddress(testOrganization.Id)
+var testCompanyPrefixAddress string = address.MakeCompanyPrefixAddress("0012345")
+
+func TestGetOrganizationForGtin(t *testing.T) {
+
+	tests := map[string]struct {
+		gtin            string
+		outOrganization *_data.Organization
+	}{
+		"matchingPrefix": {
+			gtin:            "00012345600012",
+			outOrganization: &testOrganization,
+		},
+		"noMatchingPrefix": {
+			gtin:            "00098765400015",
+			outOrganization: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		testContext := &MockContext{}
+		testContext.On("GetState", []string{testCompanyPrefixAddress}).Return(
+			map[string][]byte{
+				testCompanyPrefixAddress: _data.SerializeCompanyPrefixes(map[string]string{"0012345": "acme"}),
+			},
+			nil,
+		)
+		testContext.On("GetState", []string{testOrganizationAddress}).Return(
+			map[string][]byte{
+				testOrganizationAddress: _data.SerializeOrganizations([]*_data.Organization{&testOrganization}),
+			},
+			nil,
+		)
+		testContext.On("GetState", mock.Anything).Return(map[string][]byte{}, nil)
+
+		testState := &MdState{
+			context:      testContext,
+			addressCache: make(map[string][]byte),
+		}
+
+		organization, err := testState.GetOrganizationForGtin(test.gtin)
+		assert.Nil(t, err)
+		assert.Equal(t, test.outOrganization, organization)
+	}
+}
+
+func TestDeleteCompanyPrefix(t *testing.T) {
+	testContext := &MockContext{}
+	testContext.On("GetState", []string{testCompanyPrefixAddress}).Return(
+		map[string][]byte{
+			testCompanyPrefixAddress: _data.SerializeCompanyPrefixes(map[string]string{"0012345": "acme"}),
+		},
+		nil,
+	)
+	testContext.On("DeleteState", []string{testCompanyPrefixAddress}).Return(
+		[]string{testCompanyPrefixAddress},
+		nil,
+	).Once()
+	// The record of a shorter prefix lists the claim as nested in it
+	shorterAddress := address.MakeCompanyPrefixAddress("00123")
+	testContext.On("GetState", []string{shorterAddress}).Return(
+		map[string][]byte{
+			shorterAddress: _data.SerializeCompanyPrefixes(map[string]string{"00123>0012345": "acme"}),
+		},
+		nil,
+	)
+	testContext.On("DeleteState", []string{shorterAddress}).Return(
+		[]string{shorterAddress},
+		nil,
+	).Once()
+	testContext.On("GetState", mock.Anything).Return(map[string][]byte{}, nil)
+
+	testState := &MdState{
+		context:      testContext,
+		addressCache: make(map[string][]byte),
+	}
+
+	assert.Nil(t, testState.DeleteCompanyPrefix("0012345"))
+	owner, err := testState.GetCompanyPrefixOwner("0012345")
+	assert.Nil(t, err)
+	assert.Equal(t, "", owner)
+	overlapping, err := testState.GetOverlappingCompanyPrefixes("00123")
+	assert.Nil(t, err)
+	assert.Empty(t, overlapping)
+	testContext.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package mdata_state
+
+import (
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+)
+
+func (self *MdState) GetSchema(name string) (*_data.Schema, error) {
+	schemas, err := self.loadSchemas(name)
+	if err != nil {
+		return nil, err
+	}
+	schema, ok := schemas[name]
+	if ok {
+		return schema, nil
+	}
+	return nil, nil
+}
+
+func (self *MdState) SetSchema(name string, schema *_data.Schema) error {
+	schemas, err := self.loadSchemas(name)
+	if err != nil {
+		return err
+	}
+	schemas[name] = schema
+
+	var s []*_data.Schema
+	for _, schema := range schemas {
+		s = append(s, schema)
+	}
+	return self.storeAddress(address.MakeSchemaAddress(name), _data.SerializeSchemas(s))
+}
+
+func (self *MdState) loadSchemas(name string) (map[string]*_data.Schema, error) {
+	data, err := self.loadAddress(address.MakeSchemaAddress(name))
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return make(map[string]*_data.Schema), nil
+	}
+	return _data.DeserializeSchemas(data)
+}
@@ -0,0 +1,57 @@
+package mdata_state
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tross-tyson/mdata_go/src/shared/address"
+	_data "github.com/tross-tyson/mdata_go/src/shared/data"
+	"testing"
+)
+
+var testSchema _data.Schema = _data.Schema{
+	Name:  _data.ProductSchemaName,
+	Owner: "acme",
+	Properties: []_data.PropertyDefinition{
+		{Name: "uom", DataType: _data.TypeEnum, Required: true, EnumOptions: []string{"cases", "lbs"}},
+	},
+}
+var testSchemaAddress string = address.MakeSchemaAddress(testSchema.Name)
+
+func TestGetSchema(t *testing.T) {
+
+	tests := map[string]struct {
+		name      string
+		outSchema *_data.Schema
+	}{
+		"existingSchema": {
+			name:      _data.ProductSchemaName,
+			outSchema: &testSchema,
+		},
+		"noSchema": {
+			name:      "pallet",
+			outSchema: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+
+		testContext := &MockContext{}
+		testContext.On("GetState", []string{testSchemaAddress}).Return(
+			map[string][]byte{
+				testSchemaAddress: _data.SerializeSchemas([]*_data.Schema{&testSchema}),
+			},
+			nil,
+		)
+		testContext.On("GetState", mock.Anything).Return(map[string][]byte{}, nil)
+
+		testState := &MdState{
+			context:      testContext,
+			addressCache: make(map[string][]byte),
+		}
+
+		schema, err := testState.GetSchema(test.name)
+		assert.Nil(t, err)
+		assert.Equal(t, test.outSchema, schema)
+	}
+}
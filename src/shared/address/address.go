@@ -0,0 +1,98 @@
+// Package address computes the state addresses used by the mdata family.
+//
+// Products are stored at the namespace followed by the first 64 hex
+// characters of the hashed GTIN. Every other entity is stored at the
+// namespace, a two character type prefix and the first 62 hex characters of
+// its hashed key. Product addresses can start with any type prefix, so readers
+// listing a range of addresses check each entry against the address its key
+// hashes to.
+package address
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	FamilyName = "mdata"
+
+	OrganizationPrefix  = "01"
+	CompanyPrefixPrefix = "02"
+	AgentPrefix         = "03"
+	SchemaPrefix        = "04"
+	HistoryPrefix       = "05"
+)
+
+// Namespace is the first six hex characters, or three bytes, of the hashed
+// family name. All data under it is encoded the way this package describes.
+var Namespace = Hexdigest(FamilyName)[:6]
+
+// HistorySpace is the address prefix of every product history
+var HistorySpace = Namespace + HistoryPrefix
+
+// SettingsNamespace is the namespace of the sawtooth settings family, which
+// holds the on-chain settings of the consortium
+const SettingsNamespace = "000000"
+
+// OrganizationSpace is the address prefix of every organization
+var OrganizationSpace = Namespace + OrganizationPrefix
+
+// CompanyPrefixSpace is the address prefix of every company prefix record
+var CompanyPrefixSpace = Namespace + CompanyPrefixPrefix
+
+// AgentSpace is the address prefix of every agent
+var AgentSpace = Namespace + AgentPrefix
+
+// SchemaSpace is the address prefix of every schema
+var SchemaSpace = Namespace + SchemaPrefix
+
+func MakeProductAddress(gtin string) string {
+	return Namespace + Hexdigest(gtin)[:64]
+}
+
+func MakeOrganizationAddress(id string) string {
+	return OrganizationSpace + Hexdigest(id)[:62]
+}
+
+func MakeCompanyPrefixAddress(prefix string) string {
+	return CompanyPrefixSpace + Hexdigest(prefix)[:62]
+}
+
+func MakeAgentAddress(publicKey string) string {
+	return AgentSpace + Hexdigest(publicKey)[:62]
+}
+
+func MakeSchemaAddress(name string) string {
+	return SchemaSpace + Hexdigest(name)[:62]
+}
+
+// MakeHistoryAddress returns the address of the revisions of a GTIN, which
+// outlive the product itself
+func MakeHistoryAddress(gtin string) string {
+	return HistorySpace + Hexdigest(gtin)[:62]
+}
+
+// MakeSettingAddress returns the address of a sawtooth setting. The key is
+// split on dots into at most four parts, padded with empty parts, and each
+// part adds the first 16 hex characters of its sha256 hash.
+func MakeSettingAddress(key string) string {
+	parts := strings.SplitN(key, ".", 4)
+	for len(parts) < 4 {
+		parts = append(parts, "")
+	}
+	address := SettingsNamespace
+	for _, part := range parts {
+		hash := sha256.Sum256([]byte(part))
+		address += hex.EncodeToString(hash[:])[:16]
+	}
+	return address
+}
+
+func Hexdigest(str string) string {
+	hash := sha512.New()
+	hash.Write([]byte(str))
+	hashBytes := hash.Sum(nil)
+	return strings.ToLower(hex.EncodeToString(hashBytes))
+}
@@ -0,0 +1,56 @@
+package address
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNamespace(t *testing.T) {
+	// Namespace documented in docs/RFC.md
+	assert.Equal(t, "fa3781", Namespace)
+}
+
+func TestSettingAddress(t *testing.T) {
+	// Address of the setting as computed by the sawtooth settings family
+	assert.Equal(t, "000000a87cb5eafdcca6a8cde0fb0dec1400c5ab274474a6aa82c12840f169a04216b7",
+		MakeSettingAddress("sawtooth.settings.vote.authorized_keys"))
+	assert.Equal(t, 70, len(MakeSettingAddress("mdata.lifecycle")))
+}
+
+func TestAddressLength(t *testing.T) {
+	tests := map[string]struct {
+		address string
+		prefix  string
+	}{
+		"product": {
+			address: MakeProductAddress("00012345600012"),
+			prefix:  Namespace,
+		},
+		"organization": {
+			address: MakeOrganizationAddress("acme"),
+			prefix:  OrganizationSpace,
+		},
+		"companyPrefix": {
+			address: MakeCompanyPrefixAddress("0012345"),
+			prefix:  CompanyPrefixSpace,
+		},
+		"agent": {
+			address: MakeAgentAddress("02aa"),
+			prefix:  AgentSpace,
+		},
+		"schema": {
+			address: MakeSchemaAddress("product"),
+			prefix:  SchemaSpace,
+		},
+		"history": {
+			address: MakeHistoryAddress("00012345600012"),
+			prefix:  HistorySpace,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, 70, len(test.address))
+		assert.Equal(t, test.prefix, test.address[:len(test.prefix)])
+	}
+}
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/organization_pb2"
+)
+
+// Roles an agent can be given. Each one allows the product actions named
+// after it; product.lifecycle covers setting a product's state.
+const (
+	RoleProductCreate    = "product.create"
+	RoleProductUpdate    = "product.update"
+	RoleProductLifecycle = "product.lifecycle"
+	RoleProductDelete    = "product.delete"
+)
+
+var Roles = []string{RoleProductCreate, RoleProductUpdate, RoleProductLifecycle, RoleProductDelete}
+
+func IsRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Agent is a public key allowed to act for an organization
+type Agent struct {
+	PublicKey      string   `json:"public_key" xml:"public_key" form:"public_key" query:"public_key"`
+	OrganizationId string   `json:"organization_id" xml:"organization_id" form:"organization_id" query:"organization_id"`
+	Active         bool     `json:"active" xml:"active" form:"active" query:"active"`
+	Roles          []string `json:"roles" xml:"roles" form:"roles" query:"roles"`
+}
+
+// HasRole reports whether the agent is active and has been given role
+func (a *Agent) HasRole(role string) bool {
+	if !a.Active {
+		return false
+	}
+	for _, r := range a.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Agent) GetJson() []byte {
+	b, err := json.Marshal(a)
+	if err != nil {
+		fmt.Printf("Error marshalling agent json, %v", err)
+		return nil
+	}
+	return b
+}
+
+func GetAgentMapJson(agentMap map[string]*Agent) []byte {
+	b, err := json.Marshal(agentMap)
+	if err != nil {
+		fmt.Printf("Error marshalling agent json, %v", err)
+		return nil
+	}
+	return b
+}
+
+// DeserializeAgents returns every agent stored at an address, keyed by public
+// key
+func DeserializeAgents(data []byte) (map[string]*Agent, error) {
+	container := &organization_pb2.AgentContainer{}
+	if err := proto.Unmarshal(data, container); err != nil {
+		return nil, fmt.Errorf("Malformed agent data: %v", err)
+	}
+
+	agents := make(map[string]*Agent)
+	for _, entry := range container.GetEntries() {
+		agents[entry.GetPublicKey()] = &Agent{
+			PublicKey:      entry.GetPublicKey(),
+			OrganizationId: entry.GetOrganizationId(),
+			Active:         entry.GetActive(),
+			Roles:          entry.GetRoles(),
+		}
+	}
+	return agents, nil
+}
+
+// SerializeAgents encodes agents as an AgentContainer, sorted by public key
+// with sorted roles.
+func SerializeAgents(agents []*Agent) []byte {
+	sorted := make([]*Agent, len(agents))
+	copy(sorted, agents)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].PublicKey < sorted[j].PublicKey
+	})
+
+	container := &organization_pb2.AgentContainer{}
+	for _, agent := range sorted {
+		container.Entries = append(container.Entries, &organization_pb2.Agent{
+			PublicKey:      agent.PublicKey,
+			OrganizationId: agent.OrganizationId,
+			Active:         agent.Active,
+			Roles:          sortedCopy(agent.Roles),
+		})
+	}
+
+	b, err := proto.Marshal(container)
+	if err != nil {
+		fmt.Printf("Error marshalling agent container, %v", err)
+		return nil
+	}
+	return b
+}
@@ -0,0 +1,55 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testAgent Agent = Agent{
+	PublicKey:      "02dd",
+	OrganizationId: "acme",
+	Active:         true,
+	Roles:          []string{RoleProductUpdate, RoleProductCreate},
+}
+
+var testInactiveAgent Agent = Agent{
+	PublicKey:      "02ee",
+	OrganizationId: "acme",
+	Active:         false,
+	Roles:          []string{RoleProductCreate},
+}
+
+func TestSerializedAgent(t *testing.T) {
+	serialized := SerializeAgents([]*Agent{&testInactiveAgent, &testAgent})
+	assert.Equal(t, serialized, SerializeAgents([]*Agent{&testAgent, &testInactiveAgent}))
+
+	deserialized, err := DeserializeAgents(serialized)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(deserialized))
+	assert.Equal(t, []string{RoleProductCreate, RoleProductUpdate}, deserialized["02dd"].Roles)
+	assert.Equal(t, "acme", deserialized["02dd"].OrganizationId)
+	assert.True(t, deserialized["02dd"].Active)
+	assert.False(t, deserialized["02ee"].Active)
+}
+
+func TestHasRole(t *testing.T) {
+	tests := map[string]struct {
+		agent Agent
+		role  string
+		out   bool
+	}{
+		"hasRole":      {agent: testAgent, role: RoleProductCreate, out: true},
+		"missingRole":  {agent: testAgent, role: RoleProductDelete, out: false},
+		"inactiveRole": {agent: testInactiveAgent, role: RoleProductCreate, out: false},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, test.out, test.agent.HasRole(test.role))
+	}
+}
+
+func TestIsRole(t *testing.T) {
+	assert.True(t, IsRole("product.lifecycle"))
+	assert.False(t, IsRole("product.transfer"))
+}
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Types of the events sent when a transaction changes a product. Subscribers
+// can match all of them with EventPrefix.
+const (
+	EventPrefix              = "mdata/"
+	EventProductCreated      = "mdata/product-created"
+	EventProductUpdated      = "mdata/product-updated"
+	EventProductStateChanged = "mdata/product-state-changed"
+	EventProductDeleted      = "mdata/product-deleted"
+)
+
+// Keys of the attributes of a product event. An event has one
+// EventAttributeChanged per changed field, named as in the product history.
+const (
+	EventAttributeGtin     = "gtin"
+	EventAttributeAction   = "action"
+	EventAttributeSigner   = "signer"
+	EventAttributeState    = "state"
+	EventAttributeRevision = "revision"
+	EventAttributeChanged  = "changed"
+)
+
+// ProductEventTypes lists every product event type
+var ProductEventTypes = []string{EventProductCreated, EventProductUpdated, EventProductStateChanged, EventProductDeleted}
+
+// ProductEventType returns the type of the event sent for a product action.
+// Update, patch and transfer are all product updates.
+func ProductEventType(action string) string {
+	switch action {
+	case "create":
+		return EventProductCreated
+	case "set":
+		return EventProductStateChanged
+	case "delete":
+		return EventProductDeleted
+	}
+	return EventProductUpdated
+}
+
+// ProductReceipt is added to the receipt of a transaction for every product
+// it changes, in the order they were changed
+type ProductReceipt struct {
+	Action   string   `json:"action"`
+	Gtin     string   `json:"gtin"`
+	Revision uint64   `json:"revision"`
+	State    string   `json:"state"`
+	Changed  []string `json:"changed_fields"`
+}
+
+func (r *ProductReceipt) GetJson() []byte {
+	b, err := json.Marshal(r)
+	if err != nil {
+		fmt.Printf("Error marshalling receipt json, %v", err)
+		return nil
+	}
+	return b
+}
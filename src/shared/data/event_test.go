@@ -0,0 +1,25 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProductEventType(t *testing.T) {
+	tests := map[string]struct {
+		action       string
+		outEventType string
+	}{
+		"create":   {action: "create", outEventType: EventProductCreated},
+		"update":   {action: "update", outEventType: EventProductUpdated},
+		"patch":    {action: "patch", outEventType: EventProductUpdated},
+		"transfer": {action: "transfer", outEventType: EventProductUpdated},
+		"set":      {action: "set", outEventType: EventProductStateChanged},
+		"delete":   {action: "delete", outEventType: EventProductDeleted},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, test.outEventType, ProductEventType(test.action))
+	}
+}
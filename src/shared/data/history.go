@@ -0,0 +1,153 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/product_pb2"
+)
+
+// Names of the product fields a revision records, besides attributes.<key>
+const (
+	FieldState           = "state"
+	FieldOwner           = "owner"
+	FieldReason          = "reason"
+	AttributeFieldPrefix = "attributes."
+)
+
+// ProductRevision records who changed which fields of a product, and what
+// they were before
+type ProductRevision struct {
+	Revision      uint64     `json:"revision" xml:"revision" form:"revision" query:"revision"`
+	Action        string     `json:"action" xml:"action" form:"action" query:"action"`
+	Signer        string     `json:"signer" xml:"signer" form:"signer" query:"signer"`
+	TransactionId string     `json:"transaction_id" xml:"transaction_id" form:"transaction_id" query:"transaction_id"`
+	Changed       []string   `json:"changed_fields" xml:"changed_fields" form:"changed_fields" query:"changed_fields"`
+	Previous      Attributes `json:"previous_values" xml:"previous_values" form:"previous_values" query:"previous_values"`
+}
+
+func GetHistoryJson(revisions []*ProductRevision) []byte {
+	b, err := json.Marshal(revisions)
+	if err != nil {
+		fmt.Printf("Error marshalling history json, %v", err)
+		return nil
+	}
+	return b
+}
+
+// Copy returns a product that shares no attributes with p
+func (p *Product) Copy() *Product {
+	product := *p
+	product.Attributes = Attributes{}
+	for k, v := range p.Attributes {
+		product.Attributes[k] = v
+	}
+	return &product
+}
+
+// fields returns the recorded fields of a product by name. Empty fields are
+// left out.
+func (p *Product) fields() map[string]interface{} {
+	fields := make(map[string]interface{})
+	if p == nil {
+		return fields
+	}
+	for k, v := range p.Attributes {
+		fields[AttributeFieldPrefix+k] = v
+	}
+	for name, value := range map[string]string{FieldState: p.State, FieldOwner: p.Owner, FieldReason: p.Reason} {
+		if value != "" {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// DiffProducts returns the sorted names of the fields that differ between two
+// versions of a product, and their values in the previous version. previous is
+// nil for a new product and current is nil for a deleted one.
+func DiffProducts(previous *Product, current *Product) ([]string, Attributes) {
+	before := previous.fields()
+	after := current.fields()
+
+	changed := []string{}
+	values := Attributes{}
+	for name, value := range before {
+		if afterValue, ok := after[name]; !ok || afterValue != value {
+			changed = append(changed, name)
+			values[name] = value
+		}
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed, values
+}
+
+// DeserializeHistories returns the revisions of every GTIN stored at an
+// address, keyed by GTIN
+func DeserializeHistories(data []byte) (map[string][]*ProductRevision, error) {
+	container := &product_pb2.ProductHistoryContainer{}
+	if err := proto.Unmarshal(data, container); err != nil {
+		return nil, fmt.Errorf("Malformed history data: %v", err)
+	}
+
+	histories := make(map[string][]*ProductRevision)
+	for _, entry := range container.GetEntries() {
+		revisions := []*ProductRevision{}
+		for _, revision := range entry.GetRevisions() {
+			previous, err := AttributesFromProto(revision.GetPreviousValues())
+			if err != nil {
+				return nil, fmt.Errorf("Malformed history data: %v", err)
+			}
+			revisions = append(revisions, &ProductRevision{
+				Revision:      revision.GetRevision(),
+				Action:        revision.GetAction(),
+				Signer:        revision.GetSigner(),
+				TransactionId: revision.GetTransactionId(),
+				Changed:       revision.GetChangedFields(),
+				Previous:      previous,
+			})
+		}
+		histories[entry.GetGtin()] = revisions
+	}
+	return histories, nil
+}
+
+// SerializeHistories encodes histories as a ProductHistoryContainer, sorted by
+// GTIN. Revisions keep their order.
+func SerializeHistories(histories map[string][]*ProductRevision) []byte {
+	gtins := make([]string, 0, len(histories))
+	for gtin := range histories {
+		gtins = append(gtins, gtin)
+	}
+	sort.Strings(gtins)
+
+	container := &product_pb2.ProductHistoryContainer{}
+	for _, gtin := range gtins {
+		entry := &product_pb2.ProductHistory{Gtin: gtin}
+		for _, revision := range histories[gtin] {
+			entry.Revisions = append(entry.Revisions, &product_pb2.ProductRevision{
+				Revision:       revision.Revision,
+				Action:         revision.Action,
+				Signer:         revision.Signer,
+				TransactionId:  revision.TransactionId,
+				ChangedFields:  revision.Changed,
+				PreviousValues: revision.Previous.ToProto(),
+			})
+		}
+		container.Entries = append(container.Entries, entry)
+	}
+
+	b, err := proto.Marshal(container)
+	if err != nil {
+		fmt.Printf("Error marshalling history container, %v", err)
+		return nil
+	}
+	return b
+}
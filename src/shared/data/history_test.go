@@ -0,0 +1,78 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffProducts(t *testing.T) {
+	testProduct := &Product{Gtin: testGtin1, Attributes: Attributes{"uom": "cases", "pack": int64(12)}, State: "ACTIVE", Owner: "02aa"}
+
+	tests := map[string]struct {
+		previous    *Product
+		current     *Product
+		outChanged  []string
+		outPrevious Attributes
+	}{
+		"create": {
+			previous:    nil,
+			current:     testProduct,
+			outChanged:  []string{"attributes.pack", "attributes.uom", "owner", "state"},
+			outPrevious: Attributes{},
+		},
+		"update": {
+			previous:    testProduct,
+			current:     &Product{Gtin: testGtin1, Attributes: Attributes{"uom": "lbs", "name": "wings"}, State: "ACTIVE", Owner: "02aa"},
+			outChanged:  []string{"attributes.name", "attributes.pack", "attributes.uom"},
+			outPrevious: Attributes{"attributes.pack": int64(12), "attributes.uom": "cases"},
+		},
+		"setState": {
+			previous:    testProduct,
+			current:     &Product{Gtin: testGtin1, Attributes: testProduct.Attributes, State: "INACTIVE", Owner: "02aa", Reason: "RECALL"},
+			outChanged:  []string{"reason", "state"},
+			outPrevious: Attributes{"state": "ACTIVE"},
+		},
+		"delete": {
+			previous:    testProduct,
+			current:     nil,
+			outChanged:  []string{"attributes.pack", "attributes.uom", "owner", "state"},
+			outPrevious: Attributes{"attributes.pack": int64(12), "attributes.uom": "cases", "owner": "02aa", "state": "ACTIVE"},
+		},
+		"noChange": {
+			previous:    testProduct,
+			current:     testProduct.Copy(),
+			outChanged:  []string{},
+			outPrevious: Attributes{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		changed, previous := DiffProducts(test.previous, test.current)
+		assert.Equal(t, test.outChanged, changed)
+		assert.Equal(t, test.outPrevious, previous)
+	}
+}
+
+func TestSerializedHistories(t *testing.T) {
+	histories := map[string][]*ProductRevision{
+		testGtin1: {
+			{Revision: 1, Action: "create", Signer: "02aa", TransactionId: "txn1", Changed: []string{"state"}, Previous: Attributes{}},
+			{Revision: 2, Action: "update", Signer: "02bb", TransactionId: "txn2", Changed: []string{"attributes.pack"}, Previous: Attributes{"attributes.pack": int64(12)}},
+		},
+		testGtin2: {
+			{Revision: 1, Action: "create", Signer: "02aa", TransactionId: "txn3", Changed: []string{"state"}, Previous: Attributes{}},
+		},
+	}
+
+	deserialized, err := DeserializeHistories(SerializeHistories(histories))
+	assert.Nil(t, err)
+	assert.Equal(t, histories, deserialized)
+}
+
+func TestCopyProduct(t *testing.T) {
+	product := &Product{Gtin: testGtin1, Attributes: Attributes{"uom": "cases"}}
+	copied := product.Copy()
+	copied.Attributes["uom"] = "lbs"
+	assert.Equal(t, "cases", product.Attributes["uom"])
+}
@@ -0,0 +1,127 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// States of the default product lifecycle
+const (
+	StateActive       = "ACTIVE"
+	StateInactive     = "INACTIVE"
+	StateDiscontinued = "DISCONTINUED"
+)
+
+// LifecycleSetting is the key of the sawtooth setting that replaces the
+// default lifecycle. Its value is the lifecycle as JSON, e.g.
+//
+//	{"initial": "ACTIVE", "transitions": {"ACTIVE": ["INACTIVE"], "INACTIVE": ["ACTIVE"]}, "deletable": ["INACTIVE"]}
+const LifecycleSetting = "mdata.lifecycle"
+
+// Lifecycle defines the states a product can be in. Created products start in
+// the initial state, a set can only move a product along one of its
+// transitions and only products in a deletable state can be deleted. Every
+// state is a key of Transitions, final states have no transitions.
+type Lifecycle struct {
+	Initial     string              `json:"initial"`
+	Transitions map[string][]string `json:"transitions"`
+	Deletable   []string            `json:"deletable"`
+}
+
+// DefaultLifecycle is used until the LifecycleSetting is set. Discontinued
+// products can not be brought back.
+var DefaultLifecycle = Lifecycle{
+	Initial: StateActive,
+	Transitions: map[string][]string{
+		StateActive:       {StateInactive, StateDiscontinued},
+		StateInactive:     {StateActive, StateDiscontinued},
+		StateDiscontinued: {},
+	},
+	Deletable: []string{StateInactive},
+}
+
+var reasonCode = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// ParseLifecycle reads the value of the LifecycleSetting
+func ParseLifecycle(value string) (*Lifecycle, error) {
+	lifecycle := &Lifecycle{}
+	if err := json.Unmarshal([]byte(value), lifecycle); err != nil {
+		return nil, fmt.Errorf("Malformed lifecycle: %v", err)
+	}
+	if err := lifecycle.Check(); err != nil {
+		return nil, err
+	}
+	return lifecycle, nil
+}
+
+// Check verifies every state the lifecycle refers to is one of its states
+func (l *Lifecycle) Check() error {
+	if len(l.Transitions) < 1 {
+		return fmt.Errorf("Lifecycle must define at least one state")
+	}
+	for state, targets := range l.Transitions {
+		if len(state) < 1 || strings.ContainsAny(state, " ,") {
+			return fmt.Errorf("Invalid lifecycle state: '%v'", state)
+		}
+		for _, target := range targets {
+			if !l.IsState(target) {
+				return fmt.Errorf("Lifecycle transition %v to %v ends in an undefined state", state, target)
+			}
+		}
+	}
+	if !l.IsState(l.Initial) {
+		return fmt.Errorf("Lifecycle initial state '%v' is not defined", l.Initial)
+	}
+	for _, state := range l.Deletable {
+		if !l.IsState(state) {
+			return fmt.Errorf("Lifecycle deletable state '%v' is not defined", state)
+		}
+	}
+	return nil
+}
+
+func (l *Lifecycle) IsState(state string) bool {
+	_, ok := l.Transitions[state]
+	return ok
+}
+
+// States returns every state of the lifecycle, sorted
+func (l *Lifecycle) States() []string {
+	states := make([]string, 0, len(l.Transitions))
+	for state := range l.Transitions {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	return states
+}
+
+// CanTransition reports whether a product in state from can be set to state to
+func (l *Lifecycle) CanTransition(from string, to string) bool {
+	for _, target := range l.Transitions[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Lifecycle) IsDeletable(state string) bool {
+	for _, deletable := range l.Deletable {
+		if deletable == state {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateReason checks the reason code given with a state change, e.g.
+// RECALL or supplier.exit. It is optional.
+func ValidateReason(reason string) error {
+	if len(reason) > 0 && !reasonCode.MatchString(reason) {
+		return fmt.Errorf("Invalid reason code (1 to 64 letters, digits, '_', '.' or '-'), GOT: '%v'", reason)
+	}
+	return nil
+}
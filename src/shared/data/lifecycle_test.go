@@ -0,0 +1,59 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+
+	tests := map[string]struct {
+		from  string
+		to    string
+		valid bool
+	}{
+		"deactivate":       {from: StateActive, to: StateInactive, valid: true},
+		"reactivate":       {from: StateInactive, to: StateActive, valid: true},
+		"discontinue":      {from: StateInactive, to: StateDiscontinued, valid: true},
+		"undiscontinue":    {from: StateDiscontinued, to: StateActive, valid: false},
+		"sameState":        {from: StateActive, to: StateActive, valid: false},
+		"undefinedState":   {from: StateActive, to: "GONE", valid: false},
+		"undefinedCurrent": {from: "", to: StateActive, valid: false},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, test.valid, DefaultLifecycle.CanTransition(test.from, test.to))
+	}
+}
+
+func TestParseLifecycle(t *testing.T) {
+
+	tests := map[string]struct {
+		value string
+		valid bool
+	}{
+		"validLifecycle":     {value: `{"initial": "DRAFT", "transitions": {"DRAFT": ["PUBLISHED"], "PUBLISHED": []}, "deletable": ["DRAFT"]}`, valid: true},
+		"malformed":          {value: `DRAFT>PUBLISHED`, valid: false},
+		"noStates":           {value: `{"initial": "DRAFT"}`, valid: false},
+		"undefinedTarget":    {value: `{"initial": "DRAFT", "transitions": {"DRAFT": ["PUBLISHED"]}}`, valid: false},
+		"undefinedInitial":   {value: `{"initial": "NEW", "transitions": {"DRAFT": []}}`, valid: false},
+		"undefinedDeletable": {value: `{"initial": "DRAFT", "transitions": {"DRAFT": []}, "deletable": ["GONE"]}`, valid: false},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		lifecycle, err := ParseLifecycle(test.value)
+		assert.Equal(t, test.valid, err == nil)
+		if test.valid {
+			assert.Equal(t, []string{"DRAFT", "PUBLISHED"}, lifecycle.States())
+			assert.True(t, lifecycle.IsDeletable("DRAFT"))
+		}
+	}
+}
+
+func TestValidateReason(t *testing.T) {
+	assert.Nil(t, ValidateReason(""))
+	assert.Nil(t, ValidateReason("supplier.exit"))
+	assert.NotNil(t, ValidateReason("no longer sold"))
+}
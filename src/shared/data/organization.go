@@ -0,0 +1,134 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/organization_pb2"
+)
+
+type Organization struct {
+	Id              string   `json:"id" xml:"id" form:"id" query:"id"`
+	Name            string   `json:"name" xml:"name" form:"name" query:"name"`
+	Admins          []string `json:"admins" xml:"admins" form:"admins" query:"admins"`
+	CompanyPrefixes []string `json:"company_prefixes" xml:"company_prefixes" form:"company_prefixes" query:"company_prefixes"`
+}
+
+func (o *Organization) IsAdmin(publicKey string) bool {
+	for _, admin := range o.Admins {
+		if admin == publicKey {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Organization) GetJson() []byte {
+	b, err := json.Marshal(o)
+	if err != nil {
+		fmt.Printf("Error marshalling organization json, %v", err)
+		return nil
+	}
+	return b
+}
+
+func GetOrganizationMapJson(organizationMap map[string]*Organization) []byte {
+	b, err := json.Marshal(organizationMap)
+	if err != nil {
+		fmt.Printf("Error marshalling organization json, %v", err)
+		return nil
+	}
+	return b
+}
+
+func DeserializeOrganizations(data []byte) (map[string]*Organization, error) {
+	container := &organization_pb2.OrganizationContainer{}
+	if err := proto.Unmarshal(data, container); err != nil {
+		return nil, fmt.Errorf("Malformed organization data: %v", err)
+	}
+
+	organizations := make(map[string]*Organization)
+	for _, entry := range container.GetEntries() {
+		organizations[entry.GetId()] = &Organization{
+			Id:              entry.GetId(),
+			Name:            entry.GetName(),
+			Admins:          entry.GetAdmins(),
+			CompanyPrefixes: entry.GetCompanyPrefixes(),
+		}
+	}
+	return organizations, nil
+}
+
+// SerializeOrganizations encodes organizations as an OrganizationContainer,
+// sorted by id with sorted admins and company prefixes.
+func SerializeOrganizations(organizations []*Organization) []byte {
+	sorted := make([]*Organization, len(organizations))
+	copy(sorted, organizations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Id < sorted[j].Id
+	})
+
+	container := &organization_pb2.OrganizationContainer{}
+	for _, organization := range sorted {
+		container.Entries = append(container.Entries, &organization_pb2.Organization{
+			Id:              organization.Id,
+			Name:            organization.Name,
+			Admins:          sortedCopy(organization.Admins),
+			CompanyPrefixes: sortedCopy(organization.CompanyPrefixes),
+		})
+	}
+
+	b, err := proto.Marshal(container)
+	if err != nil {
+		fmt.Printf("Error marshalling organization container, %v", err)
+		return nil
+	}
+	return b
+}
+
+// DeserializeCompanyPrefixes returns the organization id of every company
+// prefix stored at an address
+func DeserializeCompanyPrefixes(data []byte) (map[string]string, error) {
+	container := &organization_pb2.CompanyPrefixContainer{}
+	if err := proto.Unmarshal(data, container); err != nil {
+		return nil, fmt.Errorf("Malformed company prefix data: %v", err)
+	}
+
+	prefixes := make(map[string]string)
+	for _, entry := range container.GetEntries() {
+		prefixes[entry.GetPrefix()] = entry.GetOrganizationId()
+	}
+	return prefixes, nil
+}
+
+func SerializeCompanyPrefixes(prefixes map[string]string) []byte {
+	keys := make([]string, 0, len(prefixes))
+	for prefix := range prefixes {
+		keys = append(keys, prefix)
+	}
+	sort.Strings(keys)
+
+	container := &organization_pb2.CompanyPrefixContainer{}
+	for _, prefix := range keys {
+		container.Entries = append(container.Entries, &organization_pb2.CompanyPrefix{
+			Prefix:         prefix,
+			OrganizationId: prefixes[prefix],
+		})
+	}
+
+	b, err := proto.Marshal(container)
+	if err != nil {
+		fmt.Printf("Error marshalling company prefix container, %v", err)
+		return nil
+	}
+	return b
+}
+
+func sortedCopy(values []string) []string {
+	sorted := make([]string, len(values))
+	copy(sorted, values)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,45 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testOrganization Organization = Organization{
+	Id:              "acme",
+	Name:            "Acme Foods",
+	Admins:          []string{"03bb", "02aa"},
+	CompanyPrefixes: []string{"0012345", "0098765"},
+}
+
+var testOrganization2 Organization = Organization{
+	Id:              "globex",
+	Name:            "Globex, Inc.",
+	Admins:          []string{"02cc"},
+	CompanyPrefixes: []string{},
+}
+
+func TestSerializedOrganization(t *testing.T) {
+	serialized := SerializeOrganizations([]*Organization{&testOrganization2, &testOrganization})
+	assert.Equal(t, serialized, SerializeOrganizations([]*Organization{&testOrganization, &testOrganization2}))
+
+	deserialized, err := DeserializeOrganizations(serialized)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(deserialized))
+	assert.Equal(t, []string{"02aa", "03bb"}, deserialized["acme"].Admins)
+	assert.Equal(t, testOrganization.Name, deserialized["acme"].Name)
+	assert.Equal(t, testOrganization.CompanyPrefixes, deserialized["acme"].CompanyPrefixes)
+	assert.Equal(t, testOrganization2.Name, deserialized["globex"].Name)
+}
+
+func TestIsAdmin(t *testing.T) {
+	assert.True(t, testOrganization.IsAdmin("02aa"))
+	assert.False(t, testOrganization.IsAdmin("02cc"))
+}
+
+func TestSerializedCompanyPrefixes(t *testing.T) {
+	prefixes := map[string]string{"0012345": "acme", "0098765": "acme"}
+	deserialized, err := DeserializeCompanyPrefixes(SerializeCompanyPrefixes(prefixes))
+	assert.Nil(t, err)
+	assert.Equal(t, prefixes, deserialized)
+}
@@ -5,11 +5,28 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/payload_pb2"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/product_pb2"
 )
 
 type Attributes map[string]interface{}
 
+// Keys returns the attribute keys in sorted order
+func (self Attributes) Keys() []string {
+	keys := make([]string, 0, len(self))
+	for k := range self {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Serialize writes the attributes as comma separated key=value pairs, sorted by key
 func (self Attributes) Serialize() []byte {
 	var b bytes.Buffer
 
@@ -17,36 +34,123 @@ func (self Attributes) Serialize() []byte {
 		return []byte(nil)
 	}
 
-	var i int = 0
-	for k, v := range self {
-		b.WriteString(fmt.Sprintf("%v=%v", k, v))
-		i += 1
-		if i < len(self) {
+	for i, k := range self.Keys() {
+		b.WriteString(fmt.Sprintf("%v=%v", k, self[k]))
+		if i+1 < len(self) {
 			b.WriteString(",")
 		}
 	}
 	return b.Bytes()
 }
 
-func DeserializeAttributes(a []string) Attributes {
+// Strings returns the attributes as the strings the client sends, as decoded
+// from JSON or state: strings are kept, numbers and booleans are written out. Other
+// values can not be sent as attributes.
+func (self Attributes) Strings() (map[string]string, error) {
+	strs := make(map[string]string, len(self))
+	for _, k := range self.Keys() {
+		str, ok := AttributeString(self[k])
+		if !ok {
+			return nil, fmt.Errorf("Attribute '%v' must be a string, number or boolean", k)
+		}
+		strs[k] = str
+	}
+	return strs, nil
+}
+
+// AttributeString writes out a single attribute value the way Strings does,
+// reporting false for values that are not a string, number or boolean
+func AttributeString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+func DeserializeAttributes(a []string) (Attributes, error) {
 	A := Attributes{}
 	for _, str := range a {
 		if str != "" {
 			for _, k_v_str := range strings.Split(str, ",") {
-				parts := strings.Split(k_v_str, "=")
+				parts := strings.SplitN(k_v_str, "=", 2)
+				if len(parts) != 2 {
+					return nil, fmt.Errorf("Malformed attribute, expected key=value: '%v'", k_v_str)
+				}
 				k, v := parts[0], parts[1]
 				A[k] = v
 			}
 		}
 	}
 
-	return A
+	return A, nil
+}
+
+// ToProto converts the attributes into typed payload attributes, ordered by key
+// so that equal attribute maps always encode to the same payload bytes.
+func (self Attributes) ToProto() []*payload_pb2.Attribute {
+	keys := self.Keys()
+
+	attrs := make([]*payload_pb2.Attribute, 0, len(keys))
+	for _, k := range keys {
+		attr := &payload_pb2.Attribute{Key: k}
+		switch v := self[k].(type) {
+		case bool:
+			attr.Value = &payload_pb2.Attribute_BoolValue{BoolValue: v}
+		case int:
+			attr.Value = &payload_pb2.Attribute_IntValue{IntValue: int64(v)}
+		case int64:
+			attr.Value = &payload_pb2.Attribute_IntValue{IntValue: v}
+		case float64:
+			attr.Value = &payload_pb2.Attribute_NumberValue{NumberValue: v}
+		default:
+			attr.Value = &payload_pb2.Attribute_StringValue{StringValue: fmt.Sprintf("%v", v)}
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs
+}
+
+func AttributesFromProto(attrs []*payload_pb2.Attribute) (Attributes, error) {
+	A := Attributes{}
+	for _, attr := range attrs {
+		if attr.GetKey() == "" {
+			return nil, errors.New("Attribute key is required")
+		}
+		if _, exists := A[attr.GetKey()]; exists {
+			return nil, fmt.Errorf("Duplicate attribute: '%v'", attr.GetKey())
+		}
+		switch v := attr.GetValue().(type) {
+		case *payload_pb2.Attribute_StringValue:
+			A[attr.GetKey()] = v.StringValue
+		case *payload_pb2.Attribute_IntValue:
+			A[attr.GetKey()] = v.IntValue
+		case *payload_pb2.Attribute_NumberValue:
+			A[attr.GetKey()] = v.NumberValue
+		case *payload_pb2.Attribute_BoolValue:
+			A[attr.GetKey()] = v.BoolValue
+		default:
+			return nil, fmt.Errorf("Attribute '%v' has no value", attr.GetKey())
+		}
+	}
+	return A, nil
 }
 
 type Product struct {
 	Gtin       string     `json:"gtin" sml:"gtin" form:"gtin" query:"gtin"`
 	Attributes Attributes `json:"attributes" xml:"attributes" form:"attributes" query:"attributes"`
 	State      string     `json:"state" xml:"state" form:"state" query:"state"`
+	Owner      string     `json:"owner" xml:"owner" form:"owner" query:"owner"`
+	Reason     string     `json:"reason,omitempty" xml:"reason" form:"reason" query:"reason"`
+	Revision   uint64     `json:"revision" xml:"revision" form:"revision" query:"revision"`
 }
 
 func (p *Product) GetJson() []byte {
@@ -67,38 +171,91 @@ func GetProductMapJson(productMap map[string]*Product) []byte {
 	return b
 }
 
+// Deserialize decodes the products stored at a state address. Data written
+// before the ProductContainer encoding was introduced is read with the legacy
+// pipe delimited decoder.
 func Deserialize(data []byte) (map[string]*Product, error) {
+	if isLegacy(data) {
+		return deserializeLegacy(data)
+	}
+
+	container := &product_pb2.ProductContainer{}
+	if err := proto.Unmarshal(data, container); err != nil {
+		return nil, fmt.Errorf("Malformed product data: %v", err)
+	}
+
+	products := make(map[string]*Product)
+	for _, entry := range container.GetEntries() {
+		attributes, err := AttributesFromProto(entry.GetAttributes())
+		if err != nil {
+			return nil, fmt.Errorf("Malformed product data: %v", err)
+		}
+		products[entry.GetGtin()] = &Product{
+			Gtin:       entry.GetGtin(),
+			Attributes: attributes,
+			State:      entry.GetState(),
+			Owner:      entry.GetOwner(),
+			Reason:     entry.GetReason(),
+			Revision:   entry.GetRevision(),
+		}
+	}
+	return products, nil
+}
+
+// Serialize encodes products as a ProductContainer. Products are sorted by
+// GTIN and attributes by key, so equal products always produce equal bytes.
+func Serialize(products []*Product) []byte {
+	sorted := make([]*Product, len(products))
+	copy(sorted, products)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Gtin < sorted[j].Gtin
+	})
+
+	container := &product_pb2.ProductContainer{}
+	for _, product := range sorted {
+		container.Entries = append(container.Entries, &product_pb2.Product{
+			Gtin:       product.Gtin,
+			Attributes: product.Attributes.ToProto(),
+			State:      product.State,
+			Owner:      product.Owner,
+			Reason:     product.Reason,
+			Revision:   product.Revision,
+		})
+	}
+
+	b, err := proto.Marshal(container)
+	if err != nil {
+		fmt.Printf("Error marshalling product container, %v", err)
+		return nil
+	}
+	return b
+}
+
+// isLegacy reports whether data uses the pipe delimited encoding,
+// "gtin,key=value,...,STATE|gtin,...". Legacy records start with a GTIN digit,
+// while an encoded ProductContainer starts with the tag of its entries field.
+func isLegacy(data []byte) bool {
+	return len(data) == 0 || (data[0] >= '0' && data[0] <= '9')
+}
+
+func deserializeLegacy(data []byte) (map[string]*Product, error) {
 	products := make(map[string]*Product)
 	for _, str := range strings.Split(string(data), "|") {
 		parts := strings.Split(string(str), ",")
 		if len(parts) < 3 { //Product must have at least three serialized attributes (even if Product.Attributes is empty)
 			return nil, errors.New(fmt.Sprintf("Malformed product data: '%v'", string(data)))
 		}
-		attrs := parts[1 : len(parts)-1]
+		attrs, err := DeserializeAttributes(parts[1 : len(parts)-1])
+		if err != nil {
+			return nil, fmt.Errorf("Malformed product data: %v", err)
+		}
 
 		product := &Product{
 			Gtin:       parts[0],
-			Attributes: DeserializeAttributes(attrs),
+			Attributes: attrs,
 			State:      parts[len(parts)-1],
 		}
 		products[parts[0]] = product
 	}
 	return products, nil
 }
-
-func Serialize(products []*Product) []byte {
-	var buffer bytes.Buffer
-
-	for i, product := range products {
-		//00001234567890,uom=cases,weight=200,ACTIVE|
-		buffer.WriteString(product.Gtin)
-		buffer.WriteString(",")
-		buffer.Write(product.Attributes.Serialize())
-		buffer.WriteString(",")
-		buffer.WriteString(product.State)
-		if i+1 != len(products) {
-			buffer.WriteString("|")
-		}
-	}
-	return buffer.Bytes()
-}
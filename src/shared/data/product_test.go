@@ -1,8 +1,8 @@
 package data
 
 import (
+	"encoding/json"
 	"errors"
-	"fmt"
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"sort"
@@ -63,6 +63,7 @@ func TestDeserializedAttributes(t *testing.T) {
 	tests := map[string]struct {
 		in              []string
 		outDeserialized Attributes
+		outErr          error
 	}{
 		"nilAttribute": {
 			in:              []string{},
@@ -76,12 +77,51 @@ func TestDeserializedAttributes(t *testing.T) {
 			in:              []string{"uom=lbs,weight=300"},
 			outDeserialized: Attributes{"uom": "lbs", "weight": "300"},
 		},
+		"missingSeparator": {
+			in:              []string{"uom=lbs,weight"},
+			outDeserialized: Attributes(nil),
+			outErr:          errors.New("Malformed attribute"),
+		},
 	}
 
 	for name, test := range tests {
 		t.Logf("Running test case: %s", name)
-		deserialized := DeserializeAttributes(test.in)
+		deserialized, err := DeserializeAttributes(test.in)
 		assert.Equal(t, test.outDeserialized, deserialized)
+		assert.Equal(t, reflect.TypeOf(test.outErr), reflect.TypeOf(err))
+	}
+}
+
+func TestAttributeStrings(t *testing.T) {
+
+	tests := map[string]struct {
+		attr       Attributes
+		outStrings map[string]string
+		outErr     string
+	}{
+		"nilAttribute": {
+			attr:       testAttributesEmpty,
+			outStrings: map[string]string{},
+		},
+		"nativeAttributes": {
+			attr:       Attributes{"name": "wings, hot:spicy", "weight": json.Number("2.50"), "count": float64(12), "cases": int64(3), "organic": true},
+			outStrings: map[string]string{"name": "wings, hot:spicy", "weight": "2.50", "count": "12", "cases": "3", "organic": "true"},
+		},
+		"objectAttribute": {
+			attr:   Attributes{"size": map[string]interface{}{"width": "3"}},
+			outErr: "Attribute 'size' must be a string, number or boolean",
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		strs, err := test.attr.Strings()
+		if test.outErr != "" {
+			assert.EqualError(t, err, test.outErr)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.outStrings, strs)
 	}
 }
 
@@ -106,37 +146,39 @@ var testProductSliceMulti []*Product = []*Product{
 	&testProduct2,
 }
 
+var testProductSpecialChars Product = Product{
+	Gtin:       testGtin2,
+	Attributes: Attributes{"name": "wings, hot=spicy|large", "weight": int64(300), "organic": true},
+	State:      testState,
+	Owner:      "02a1b2c3d4e5f60718293a4b5c6d7e8f9010a1b2c3d4e5f60718293a4b5c6d7e8f",
+}
+
 func TestSerializedProduct(t *testing.T) {
 
 	tests := map[string]struct {
-		in            []*Product
-		outSerialized []byte
+		in         []*Product
+		inReversed []*Product
 	}{
-		"nilProduct": {
-			in:            testProductSliceEmpty,
-			outSerialized: []byte(nil),
-		},
 		"onProduct": {
-			in:            testProductSliceOne,
-			outSerialized: []byte(fmt.Sprintf("%v,%v,%v", testProduct.Gtin, string(testProduct.Attributes.Serialize()), testProduct.State)),
+			in:         testProductSliceOne,
+			inReversed: testProductSliceOne,
 		},
 		"multiProduct": {
-			in: testProductSliceMulti,
-			outSerialized: []byte(
-				fmt.Sprintf("%v,%v,%v|%v,%v,%v",
-					testProduct.Gtin,
-					string(testProduct.Attributes.Serialize()),
-					testProduct.State,
-					testProduct2.Gtin,
-					string(testProduct2.Attributes.Serialize()),
-					testProduct2.State)),
+			in:         testProductSliceMulti,
+			inReversed: []*Product{&testProduct2, &testProduct},
 		},
 	}
 
 	for name, test := range tests {
 		t.Logf("Running test case: %s", name)
 		serialized := Serialize(test.in)
-		assert.Equal(t, test.outSerialized, serialized)
+		assert.NotEmpty(t, serialized)
+		// Product order must not change the encoding
+		assert.Equal(t, serialized, Serialize(test.inReversed))
+		// Attribute map iteration order must not change the encoding
+		for i := 0; i < 20; i++ {
+			assert.Equal(t, serialized, Serialize(test.in))
+		}
 	}
 }
 
@@ -167,6 +209,50 @@ func TestDeserializedProduct(t *testing.T) {
 			},
 			outErr: nil,
 		},
+		"specialChars": {
+			in: Serialize([]*Product{&testProductSpecialChars}),
+			outDeserialized: map[string]*Product{
+				testGtin2: &testProductSpecialChars,
+			},
+			outErr: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		deserialized, err := Deserialize(test.in)
+		assert.Equal(t, test.outDeserialized, deserialized)
+		assert.Equal(t, reflect.TypeOf(test.outErr), reflect.TypeOf(err))
+	}
+}
+
+func TestDeserializedLegacyProduct(t *testing.T) {
+
+	tests := map[string]struct {
+		in              []byte
+		outDeserialized map[string]*Product
+		outErr          error
+	}{
+		"onProduct": {
+			in: []byte("11111111111111,,ACTIVE"),
+			outDeserialized: map[string]*Product{
+				testGtin1: &testProduct,
+			},
+			outErr: nil,
+		},
+		"multiProduct": {
+			in: []byte("11111111111111,,ACTIVE|55555555555555,weight=300,uom=lbs,ACTIVE"),
+			outDeserialized: map[string]*Product{
+				testGtin1: &testProduct,
+				testGtin2: &testProduct2,
+			},
+			outErr: nil,
+		},
+		"malformedAttribute": {
+			in:              []byte("55555555555555,uom=lbs,weight,ACTIVE"),
+			outDeserialized: map[string]*Product(nil),
+			outErr:          errors.New("Malformed product"),
+		},
 	}
 
 	for name, test := range tests {
@@ -0,0 +1,344 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/tross-tyson/mdata_go/src/shared/protobuf/schema_pb2"
+)
+
+// ProductSchemaName is the name of the schema product attributes are checked
+// against. Until it is created attributes are free-form.
+const ProductSchemaName = "product"
+
+// Data types of a property definition
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeDecimal = "decimal"
+	TypeEnum    = "enum"
+	TypeBoolean = "boolean"
+	TypeDate    = "date"
+	TypeMeasure = "measure"
+)
+
+var dataTypes = map[string]schema_pb2.PropertyDefinition_DataType{
+	TypeString:  schema_pb2.PropertyDefinition_STRING,
+	TypeInteger: schema_pb2.PropertyDefinition_INTEGER,
+	TypeDecimal: schema_pb2.PropertyDefinition_DECIMAL,
+	TypeEnum:    schema_pb2.PropertyDefinition_ENUM,
+	TypeBoolean: schema_pb2.PropertyDefinition_BOOLEAN,
+	TypeDate:    schema_pb2.PropertyDefinition_DATE,
+	TypeMeasure: schema_pb2.PropertyDefinition_MEASURE,
+}
+
+// DateFormat is the layout of date attributes
+const DateFormat = "2006-01-02"
+
+type PropertyDefinition struct {
+	Name        string   `json:"name" xml:"name" form:"name" query:"name"`
+	DataType    string   `json:"data_type" xml:"data_type" form:"data_type" query:"data_type"`
+	Required    bool     `json:"required" xml:"required" form:"required" query:"required"`
+	EnumOptions []string `json:"enum_options,omitempty" xml:"enum_options" form:"enum_options" query:"enum_options"`
+	Units       []string `json:"units,omitempty" xml:"units" form:"units" query:"units"`
+	Description string   `json:"description,omitempty" xml:"description" form:"description" query:"description"`
+}
+
+type Schema struct {
+	Name       string               `json:"name" xml:"name" form:"name" query:"name"`
+	Owner      string               `json:"owner" xml:"owner" form:"owner" query:"owner"`
+	Properties []PropertyDefinition `json:"properties" xml:"properties" form:"properties" query:"properties"`
+}
+
+// SchemaError lists every way a set of attributes breaks a schema
+type SchemaError struct {
+	Schema     string
+	Violations []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("Attributes do not match schema %v: %v", e.Schema, strings.Join(e.Violations, "; "))
+}
+
+func (s *Schema) GetJson() []byte {
+	b, err := json.Marshal(s)
+	if err != nil {
+		fmt.Printf("Error marshalling schema json, %v", err)
+		return nil
+	}
+	return b
+}
+
+func GetSchemaMapJson(schemaMap map[string]*Schema) []byte {
+	b, err := json.Marshal(schemaMap)
+	if err != nil {
+		fmt.Printf("Error marshalling schema json, %v", err)
+		return nil
+	}
+	return b
+}
+
+// CheckDefinition verifies the schema's own properties are well formed
+func (s *Schema) CheckDefinition() error {
+	if len(s.Name) < 1 {
+		return fmt.Errorf("Schema name is required")
+	}
+	if len(s.Properties) < 1 {
+		return fmt.Errorf("Schema %v must define at least one property", s.Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, property := range s.Properties {
+		if len(property.Name) < 1 {
+			return fmt.Errorf("Property name is required")
+		}
+		if seen[property.Name] {
+			return fmt.Errorf("Duplicate property: '%v'", property.Name)
+		}
+		seen[property.Name] = true
+
+		if _, ok := dataTypes[property.DataType]; !ok {
+			return fmt.Errorf("Property '%v' has an invalid data type '%v'", property.Name, property.DataType)
+		}
+		if property.DataType == TypeEnum && len(property.EnumOptions) < 1 {
+			return fmt.Errorf("Enum property '%v' requires enum options", property.Name)
+		}
+		if property.DataType != TypeEnum && len(property.EnumOptions) > 0 {
+			return fmt.Errorf("Property '%v' has enum options but is not an enum", property.Name)
+		}
+		if property.DataType != TypeMeasure && len(property.Units) > 0 {
+			return fmt.Errorf("Property '%v' has units but is not a measure", property.Name)
+		}
+	}
+	return nil
+}
+
+// Validate checks attributes against the schema and returns them converted
+// to the types it defines, so "12" becomes an integer for an integer property.
+// The error is a *SchemaError listing every violation, sorted by attribute.
+func (s *Schema) Validate(attributes Attributes) (Attributes, error) {
+	properties := make(map[string]PropertyDefinition)
+	for _, property := range s.Properties {
+		properties[property.Name] = property
+	}
+
+	var violations []string
+	converted := Attributes{}
+	for _, key := range attributes.Keys() {
+		property, ok := properties[key]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("%v: not defined in the schema", key))
+			continue
+		}
+		value, err := property.convert(attributes[key])
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%v: %v", key, err))
+			continue
+		}
+		converted[key] = value
+	}
+	for _, property := range s.Properties {
+		if _, ok := attributes[property.Name]; property.Required && !ok {
+			violations = append(violations, fmt.Sprintf("%v: required", property.Name))
+		}
+	}
+
+	if len(violations) > 0 {
+		sort.Strings(violations)
+		return nil, &SchemaError{Schema: s.Name, Violations: violations}
+	}
+	return converted, nil
+}
+
+// convert returns value as the property's data type. Strings are parsed, since
+// attributes given on the command line are always strings.
+func (p *PropertyDefinition) convert(value interface{}) (interface{}, error) {
+	str, isString := value.(string)
+
+	switch p.DataType {
+	case TypeString:
+		if !isString {
+			return nil, fmt.Errorf("must be a string")
+		}
+		return str, nil
+	case TypeInteger:
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case int:
+			return int64(v), nil
+		case string:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+		return nil, fmt.Errorf("must be an integer")
+	case TypeDecimal:
+		switch v := value.(type) {
+		case float64:
+			return checkFinite(v)
+		case int64:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return checkFinite(f)
+			}
+		}
+		return nil, fmt.Errorf("must be a decimal")
+	case TypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if v == "true" || v == "false" {
+				return v == "true", nil
+			}
+		}
+		return nil, fmt.Errorf("must be true or false")
+	case TypeEnum:
+		for _, option := range p.EnumOptions {
+			if isString && str == option {
+				return str, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %v", strings.Join(p.EnumOptions, ", "))
+	case TypeDate:
+		if isString {
+			if _, err := time.Parse(DateFormat, str); err == nil {
+				return str, nil
+			}
+		}
+		return nil, fmt.Errorf("must be a date, YYYY-MM-DD")
+	case TypeMeasure:
+		return p.convertMeasure(str, isString)
+	}
+	return nil, fmt.Errorf("has an unknown data type '%v'", p.DataType)
+}
+
+// convertMeasure accepts "<decimal> <unit>" and writes the decimal in its
+// shortest form
+func (p *PropertyDefinition) convertMeasure(str string, isString bool) (interface{}, error) {
+	invalid := fmt.Errorf("must be a decimal and a unit, e.g. '12.5 kg'")
+	if len(p.Units) > 0 {
+		invalid = fmt.Errorf("must be a decimal and one of the units %v, e.g. '12.5 %v'", strings.Join(p.Units, ", "), p.Units[0])
+	}
+	if !isString {
+		return nil, invalid
+	}
+
+	fields := strings.Fields(str)
+	if len(fields) != 2 {
+		return nil, invalid
+	}
+	amount, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return nil, invalid
+	}
+	if len(p.Units) > 0 {
+		known := false
+		for _, unit := range p.Units {
+			known = known || unit == fields[1]
+		}
+		if !known {
+			return nil, invalid
+		}
+	}
+	return strconv.FormatFloat(amount, 'f', -1, 64) + " " + fields[1], nil
+}
+
+func checkFinite(f float64) (interface{}, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("must be a finite decimal")
+	}
+	return f, nil
+}
+
+// PropertiesToProto converts property definitions into their protobuf form,
+// sorted by name.
+func PropertiesToProto(properties []PropertyDefinition) []*schema_pb2.PropertyDefinition {
+	sorted := make([]PropertyDefinition, len(properties))
+	copy(sorted, properties)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	definitions := make([]*schema_pb2.PropertyDefinition, 0, len(sorted))
+	for _, property := range sorted {
+		definitions = append(definitions, &schema_pb2.PropertyDefinition{
+			Name:        property.Name,
+			DataType:    dataTypes[property.DataType],
+			Required:    property.Required,
+			EnumOptions: property.EnumOptions,
+			Units:       property.Units,
+			Description: property.Description,
+		})
+	}
+	return definitions
+}
+
+func PropertiesFromProto(definitions []*schema_pb2.PropertyDefinition) []PropertyDefinition {
+	properties := make([]PropertyDefinition, 0, len(definitions))
+	for _, definition := range definitions {
+		properties = append(properties, PropertyDefinition{
+			Name:        definition.GetName(),
+			DataType:    strings.ToLower(definition.GetDataType().String()),
+			Required:    definition.GetRequired(),
+			EnumOptions: definition.GetEnumOptions(),
+			Units:       definition.GetUnits(),
+			Description: definition.GetDescription(),
+		})
+	}
+	return properties
+}
+
+// DeserializeSchemas returns every schema stored at an address, keyed by name
+func DeserializeSchemas(data []byte) (map[string]*Schema, error) {
+	container := &schema_pb2.SchemaContainer{}
+	if err := proto.Unmarshal(data, container); err != nil {
+		return nil, fmt.Errorf("Malformed schema data: %v", err)
+	}
+
+	schemas := make(map[string]*Schema)
+	for _, entry := range container.GetEntries() {
+		schemas[entry.GetName()] = &Schema{
+			Name:       entry.GetName(),
+			Owner:      entry.GetOwner(),
+			Properties: PropertiesFromProto(entry.GetProperties()),
+		}
+	}
+	return schemas, nil
+}
+
+// SerializeSchemas encodes schemas as a SchemaContainer, sorted by name with
+// sorted properties.
+func SerializeSchemas(schemas []*Schema) []byte {
+	sorted := make([]*Schema, len(schemas))
+	copy(sorted, schemas)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	container := &schema_pb2.SchemaContainer{}
+	for _, schema := range sorted {
+		container.Entries = append(container.Entries, &schema_pb2.Schema{
+			Name:       schema.Name,
+			Owner:      schema.Owner,
+			Properties: PropertiesToProto(schema.Properties),
+		})
+	}
+
+	b, err := proto.Marshal(container)
+	if err != nil {
+		fmt.Printf("Error marshalling schema container, %v", err)
+		return nil
+	}
+	return b
+}
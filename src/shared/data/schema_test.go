@@ -0,0 +1,107 @@
+package data
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testSchema Schema = Schema{
+	Name:  ProductSchemaName,
+	Owner: "acme",
+	Properties: []PropertyDefinition{
+		{Name: "uom", DataType: TypeEnum, Required: true, EnumOptions: []string{"cases", "lbs"}},
+		{Name: "name", DataType: TypeString},
+		{Name: "pack", DataType: TypeInteger},
+		{Name: "price", DataType: TypeDecimal},
+		{Name: "kosher", DataType: TypeBoolean},
+		{Name: "launch", DataType: TypeDate},
+		{Name: "weight", DataType: TypeMeasure, Units: []string{"kg", "lb"}},
+	},
+}
+
+func TestValidateAttributes(t *testing.T) {
+
+	tests := map[string]struct {
+		attributes    Attributes
+		outAttributes Attributes
+		outError      *SchemaError
+	}{
+		"convertedAttributes": {
+			attributes: Attributes{"uom": "cases", "name": "wings", "pack": "12", "price": "3.50",
+				"kosher": "true", "launch": "2019-06-01", "weight": "12.50 kg"},
+			outAttributes: Attributes{"uom": "cases", "name": "wings", "pack": int64(12), "price": 3.5,
+				"kosher": true, "launch": "2019-06-01", "weight": "12.5 kg"},
+			outError: nil,
+		},
+		"typedAttributes": {
+			attributes:    Attributes{"uom": "lbs", "pack": int64(6), "price": int64(4)},
+			outAttributes: Attributes{"uom": "lbs", "pack": int64(6), "price": float64(4)},
+			outError:      nil,
+		},
+		"violations": {
+			attributes: Attributes{"UOM": "Case", "pack": "1.5", "kosher": "yes", "launch": "06/01/2019", "weight": "12 oz"},
+			outError: &SchemaError{Schema: ProductSchemaName, Violations: []string{
+				"UOM: not defined in the schema",
+				"kosher: must be true or false",
+				"launch: must be a date, YYYY-MM-DD",
+				"pack: must be an integer",
+				"uom: required",
+				"weight: must be a decimal and one of the units kg, lb, e.g. '12.5 kg'",
+			}},
+		},
+		"enumViolation": {
+			attributes: Attributes{"uom": "Case"},
+			outError: &SchemaError{Schema: ProductSchemaName, Violations: []string{
+				"uom: must be one of cases, lbs",
+			}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		attributes, err := testSchema.Validate(test.attributes)
+		if test.outError == nil {
+			assert.Nil(t, err)
+			assert.Equal(t, test.outAttributes, attributes)
+		} else {
+			assert.Equal(t, test.outError, err)
+		}
+	}
+}
+
+func TestCheckDefinition(t *testing.T) {
+
+	tests := map[string]struct {
+		schema Schema
+		valid  bool
+	}{
+		"validSchema":    {schema: testSchema, valid: true},
+		"noName":         {schema: Schema{Properties: testSchema.Properties}, valid: false},
+		"noProperties":   {schema: Schema{Name: "product"}, valid: false},
+		"invalidType":    {schema: Schema{Name: "product", Properties: []PropertyDefinition{{Name: "uom", DataType: "text"}}}, valid: false},
+		"enumNoOptions":  {schema: Schema{Name: "product", Properties: []PropertyDefinition{{Name: "uom", DataType: TypeEnum}}}, valid: false},
+		"duplicateNames": {schema: Schema{Name: "product", Properties: []PropertyDefinition{{Name: "a", DataType: TypeString}, {Name: "a", DataType: TypeDate}}}, valid: false},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		assert.Equal(t, test.valid, test.schema.CheckDefinition() == nil)
+	}
+}
+
+func TestSerializedSchema(t *testing.T) {
+	deserialized, err := DeserializeSchemas(SerializeSchemas([]*Schema{&testSchema}))
+	assert.Nil(t, err)
+	schema := deserialized[ProductSchemaName]
+	assert.Equal(t, "acme", schema.Owner)
+	assert.Equal(t, len(testSchema.Properties), len(schema.Properties))
+
+	// Properties are sorted by name
+	assert.Equal(t, "kosher", schema.Properties[0].Name)
+	assert.Equal(t, TypeBoolean, schema.Properties[0].DataType)
+
+	// Serialized schemas validate the same attributes
+	attributes, err := schema.Validate(Attributes{"uom": "cases", "weight": "1 lb"})
+	assert.Nil(t, err)
+	assert.Equal(t, Attributes{"uom": "cases", "weight": "1 lb"}, attributes)
+}
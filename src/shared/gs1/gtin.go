@@ -0,0 +1,102 @@
+// Package gs1 validates GS1 identification keys.
+package gs1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GTIN lengths accepted by NormalizeGtin. Every shorter GTIN is left padded
+// with zeros to GTIN-14, the form used as the product key in state.
+var gtinLengths = []int{8, 12, 13, 14}
+
+const Gtin14Length = 14
+
+// NormalizeGtin validates a GTIN-8, GTIN-12, GTIN-13 or GTIN-14, including
+// its mod-10 check digit, and returns it as a zero padded GTIN-14.
+func NormalizeGtin(gtin string) (string, error) {
+	if gtin == "" {
+		return "", fmt.Errorf("GTIN is required")
+	}
+
+	for _, c := range gtin {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("Invalid GTIN '%v': must contain only digits", gtin)
+		}
+	}
+
+	validLength := false
+	for _, length := range gtinLengths {
+		if len(gtin) == length {
+			validLength = true
+		}
+	}
+	if !validLength {
+		return "", fmt.Errorf("Invalid GTIN '%v': must be 8, 12, 13 or 14 digits, got %v", gtin, len(gtin))
+	}
+
+	expected := CheckDigit(gtin[:len(gtin)-1])
+	actual := int(gtin[len(gtin)-1] - '0')
+	if expected != actual {
+		return "", fmt.Errorf("Invalid GTIN '%v': check digit is %v, expected %v", gtin, actual, expected)
+	}
+
+	return strings.Repeat("0", Gtin14Length-len(gtin)) + gtin, nil
+}
+
+// IsGtin14 reports whether gtin is already a valid, normalized GTIN-14
+func IsGtin14(gtin string) bool {
+	normalized, err := NormalizeGtin(gtin)
+	return err == nil && normalized == gtin
+}
+
+// CheckDigit computes the GS1 mod-10 check digit for the digits of a GTIN
+// without its check digit. Starting from the rightmost digit, digits are
+// weighted 3, 1, 3, 1, ...
+func CheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			sum += digit * 3
+		} else {
+			sum += digit
+		}
+	}
+	return (10 - sum%10) % 10
+}
+
+// GS1 company prefixes are 4 to 12 digits long
+const (
+	MinCompanyPrefixLength = 4
+	MaxCompanyPrefixLength = 12
+)
+
+// ValidateCompanyPrefix checks that prefix could be a GS1 company prefix
+func ValidateCompanyPrefix(prefix string) error {
+	for _, c := range prefix {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("Invalid company prefix '%v': must contain only digits", prefix)
+		}
+	}
+	if len(prefix) < MinCompanyPrefixLength || len(prefix) > MaxCompanyPrefixLength {
+		return fmt.Errorf("Invalid company prefix '%v': must be %v to %v digits, got %v",
+			prefix, MinCompanyPrefixLength, MaxCompanyPrefixLength, len(prefix))
+	}
+	return nil
+}
+
+// CompanyPrefixCandidates returns every company prefix a GTIN-14 could have
+// been assigned from, longest first. The company prefix follows the indicator
+// digit, so a GTIN-12 padded to GTIN-14 is matched by a prefix with one
+// leading zero.
+func CompanyPrefixCandidates(gtin14 string) []string {
+	candidates := []string{}
+	if len(gtin14) != Gtin14Length {
+		return candidates
+	}
+	for length := MaxCompanyPrefixLength; length >= MinCompanyPrefixLength; length-- {
+		candidates = append(candidates, gtin14[1:1+length])
+	}
+	return candidates
+}
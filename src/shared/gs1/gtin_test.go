@@ -0,0 +1,90 @@
+package gs1
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+var invalidGtinError = errors.New("Invalid GTIN")
+
+func TestNormalizeGtin(t *testing.T) {
+	tests := map[string]struct {
+		in     string
+		out    string
+		outErr error
+	}{
+		"gtin8": {
+			in:  "96385074",
+			out: "00000096385074",
+		},
+		"gtin12": {
+			in:  "012345678905",
+			out: "00012345678905",
+		},
+		"gtin13": {
+			in:  "4006381333931",
+			out: "04006381333931",
+		},
+		"gtin14": {
+			in:  "00012345600012",
+			out: "00012345600012",
+		},
+		"empty": {
+			in:     "",
+			outErr: invalidGtinError,
+		},
+		"badCheckDigit": {
+			in:     "00012345600013",
+			outErr: invalidGtinError,
+		},
+		"negative": {
+			in:     "-1234567890123",
+			outErr: invalidGtinError,
+		},
+		"letters": {
+			in:     "0001234560001A",
+			outErr: invalidGtinError,
+		},
+		"badLength": {
+			in:     "0123456789",
+			outErr: invalidGtinError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Logf("Running test case: %s", name)
+		out, err := NormalizeGtin(test.in)
+		assert.Equal(t, test.out, out)
+		assert.Equal(t, reflect.TypeOf(test.outErr), reflect.TypeOf(err))
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	assert.Equal(t, 2, CheckDigit("0001234560001"))
+	assert.Equal(t, 5, CheckDigit("01234567890"))
+	assert.Equal(t, 4, CheckDigit("9638507"))
+}
+
+func TestIsGtin14(t *testing.T) {
+	assert.True(t, IsGtin14("00012345600012"))
+	assert.False(t, IsGtin14("012345678905"))
+	assert.False(t, IsGtin14("00012345600013"))
+}
+
+func TestValidateCompanyPrefix(t *testing.T) {
+	assert.Nil(t, ValidateCompanyPrefix("0012345"))
+	assert.NotNil(t, ValidateCompanyPrefix("123"))
+	assert.NotNil(t, ValidateCompanyPrefix("1234567890123"))
+	assert.NotNil(t, ValidateCompanyPrefix("12a45"))
+}
+
+func TestCompanyPrefixCandidates(t *testing.T) {
+	candidates := CompanyPrefixCandidates("00012345600012")
+	assert.Equal(t, 9, len(candidates))
+	assert.Equal(t, "001234560001", candidates[0])
+	assert.Equal(t, "0012", candidates[len(candidates)-1])
+	assert.Contains(t, candidates, "0012345")
+	assert.Empty(t, CompanyPrefixCandidates("012345678905"))
+}
@@ -0,0 +1,9 @@
+// Package protobuf holds the Go code generated from the mdata protocol buffer
+// definitions in the top level protos directory. Run `go generate` in this
+// directory after changing a .proto file.
+package protobuf
+
+//go:generate protoc -I ../../../protos --go_out=paths=source_relative:payload_pb2 ../../../protos/payload.proto
+//go:generate protoc -I ../../../protos --go_out=paths=source_relative:product_pb2 ../../../protos/product.proto
+//go:generate protoc -I ../../../protos --go_out=paths=source_relative:organization_pb2 ../../../protos/organization.proto
+//go:generate protoc -I ../../../protos --go_out=paths=source_relative:schema_pb2 ../../../protos/schema.proto
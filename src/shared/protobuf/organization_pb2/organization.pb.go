@@ -0,0 +1,355 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: organization.proto
+
+package organization_pb2
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Organization is a consortium member and the GS1 company prefixes it
+// assigns GTINs from.
+type Organization struct {
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Public keys allowed to manage the organization, hex encoded and sorted
+	Admins []string `protobuf:"bytes,3,rep,name=admins,proto3" json:"admins,omitempty"`
+	// GS1 company prefixes, sorted
+	CompanyPrefixes      []string `protobuf:"bytes,4,rep,name=company_prefixes,json=companyPrefixes,proto3" json:"company_prefixes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Organization) Reset()         { *m = Organization{} }
+func (m *Organization) String() string { return proto.CompactTextString(m) }
+func (*Organization) ProtoMessage()    {}
+func (*Organization) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d10c68ef159b9ed, []int{0}
+}
+
+func (m *Organization) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Organization.Unmarshal(m, b)
+}
+func (m *Organization) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Organization.Marshal(b, m, deterministic)
+}
+func (m *Organization) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Organization.Merge(m, src)
+}
+func (m *Organization) XXX_Size() int {
+	return xxx_messageInfo_Organization.Size(m)
+}
+func (m *Organization) XXX_DiscardUnknown() {
+	xxx_messageInfo_Organization.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Organization proto.InternalMessageInfo
+
+func (m *Organization) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Organization) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Organization) GetAdmins() []string {
+	if m != nil {
+		return m.Admins
+	}
+	return nil
+}
+
+func (m *Organization) GetCompanyPrefixes() []string {
+	if m != nil {
+		return m.CompanyPrefixes
+	}
+	return nil
+}
+
+type OrganizationContainer struct {
+	Entries              []*Organization `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *OrganizationContainer) Reset()         { *m = OrganizationContainer{} }
+func (m *OrganizationContainer) String() string { return proto.CompactTextString(m) }
+func (*OrganizationContainer) ProtoMessage()    {}
+func (*OrganizationContainer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d10c68ef159b9ed, []int{1}
+}
+
+func (m *OrganizationContainer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrganizationContainer.Unmarshal(m, b)
+}
+func (m *OrganizationContainer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrganizationContainer.Marshal(b, m, deterministic)
+}
+func (m *OrganizationContainer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrganizationContainer.Merge(m, src)
+}
+func (m *OrganizationContainer) XXX_Size() int {
+	return xxx_messageInfo_OrganizationContainer.Size(m)
+}
+func (m *OrganizationContainer) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrganizationContainer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrganizationContainer proto.InternalMessageInfo
+
+func (m *OrganizationContainer) GetEntries() []*Organization {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// CompanyPrefix indexes a GS1 company prefix to the organization that owns it
+type CompanyPrefix struct {
+	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	OrganizationId       string   `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompanyPrefix) Reset()         { *m = CompanyPrefix{} }
+func (m *CompanyPrefix) String() string { return proto.CompactTextString(m) }
+func (*CompanyPrefix) ProtoMessage()    {}
+func (*CompanyPrefix) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d10c68ef159b9ed, []int{2}
+}
+
+func (m *CompanyPrefix) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompanyPrefix.Unmarshal(m, b)
+}
+func (m *CompanyPrefix) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompanyPrefix.Marshal(b, m, deterministic)
+}
+func (m *CompanyPrefix) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompanyPrefix.Merge(m, src)
+}
+func (m *CompanyPrefix) XXX_Size() int {
+	return xxx_messageInfo_CompanyPrefix.Size(m)
+}
+func (m *CompanyPrefix) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompanyPrefix.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompanyPrefix proto.InternalMessageInfo
+
+func (m *CompanyPrefix) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *CompanyPrefix) GetOrganizationId() string {
+	if m != nil {
+		return m.OrganizationId
+	}
+	return ""
+}
+
+type CompanyPrefixContainer struct {
+	Entries              []*CompanyPrefix `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CompanyPrefixContainer) Reset()         { *m = CompanyPrefixContainer{} }
+func (m *CompanyPrefixContainer) String() string { return proto.CompactTextString(m) }
+func (*CompanyPrefixContainer) ProtoMessage()    {}
+func (*CompanyPrefixContainer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d10c68ef159b9ed, []int{3}
+}
+
+func (m *CompanyPrefixContainer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompanyPrefixContainer.Unmarshal(m, b)
+}
+func (m *CompanyPrefixContainer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompanyPrefixContainer.Marshal(b, m, deterministic)
+}
+func (m *CompanyPrefixContainer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompanyPrefixContainer.Merge(m, src)
+}
+func (m *CompanyPrefixContainer) XXX_Size() int {
+	return xxx_messageInfo_CompanyPrefixContainer.Size(m)
+}
+func (m *CompanyPrefixContainer) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompanyPrefixContainer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompanyPrefixContainer proto.InternalMessageInfo
+
+func (m *CompanyPrefixContainer) GetEntries() []*CompanyPrefix {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// Agent is a public key allowed to act for an organization. Roles are the
+// product actions it may sign, e.g. product.create.
+type Agent struct {
+	PublicKey      string `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	OrganizationId string `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Active         bool   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	// Sorted
+	Roles                []string `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Agent) Reset()         { *m = Agent{} }
+func (m *Agent) String() string { return proto.CompactTextString(m) }
+func (*Agent) ProtoMessage()    {}
+func (*Agent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d10c68ef159b9ed, []int{4}
+}
+
+func (m *Agent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Agent.Unmarshal(m, b)
+}
+func (m *Agent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Agent.Marshal(b, m, deterministic)
+}
+func (m *Agent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Agent.Merge(m, src)
+}
+func (m *Agent) XXX_Size() int {
+	return xxx_messageInfo_Agent.Size(m)
+}
+func (m *Agent) XXX_DiscardUnknown() {
+	xxx_messageInfo_Agent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Agent proto.InternalMessageInfo
+
+func (m *Agent) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *Agent) GetOrganizationId() string {
+	if m != nil {
+		return m.OrganizationId
+	}
+	return ""
+}
+
+func (m *Agent) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *Agent) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+type AgentContainer struct {
+	Entries              []*Agent `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentContainer) Reset()         { *m = AgentContainer{} }
+func (m *AgentContainer) String() string { return proto.CompactTextString(m) }
+func (*AgentContainer) ProtoMessage()    {}
+func (*AgentContainer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d10c68ef159b9ed, []int{5}
+}
+
+func (m *AgentContainer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentContainer.Unmarshal(m, b)
+}
+func (m *AgentContainer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentContainer.Marshal(b, m, deterministic)
+}
+func (m *AgentContainer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentContainer.Merge(m, src)
+}
+func (m *AgentContainer) XXX_Size() int {
+	return xxx_messageInfo_AgentContainer.Size(m)
+}
+func (m *AgentContainer) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentContainer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentContainer proto.InternalMessageInfo
+
+func (m *AgentContainer) GetEntries() []*Agent {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Organization)(nil), "Organization")
+	proto.RegisterType((*OrganizationContainer)(nil), "OrganizationContainer")
+	proto.RegisterType((*CompanyPrefix)(nil), "CompanyPrefix")
+	proto.RegisterType((*CompanyPrefixContainer)(nil), "CompanyPrefixContainer")
+	proto.RegisterType((*Agent)(nil), "Agent")
+	proto.RegisterType((*AgentContainer)(nil), "AgentContainer")
+}
+
+func init() { proto.RegisterFile("organization.proto", fileDescriptor_8d10c68ef159b9ed) }
+
+var fileDescriptor_8d10c68ef159b9ed = []byte{
+	// 336 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x41, 0x4f, 0xe3, 0x30,
+	0x10, 0x85, 0x95, 0xa6, 0xed, 0x6e, 0x67, 0xb7, 0x29, 0xb2, 0xa0, 0xca, 0x05, 0x29, 0xca, 0xa5,
+	0xe1, 0x40, 0x22, 0x95, 0x3f, 0x00, 0x2d, 0x42, 0x42, 0x1c, 0xa8, 0x72, 0xe4, 0x12, 0x39, 0xb1,
+	0x9b, 0x5a, 0x34, 0x76, 0x64, 0x3b, 0x88, 0x20, 0xf1, 0xdf, 0x11, 0xae, 0x69, 0xd3, 0x9e, 0xb8,
+	0xf9, 0xbd, 0x79, 0x9a, 0xf9, 0x66, 0x64, 0x40, 0x42, 0x96, 0x98, 0xb3, 0x0f, 0xac, 0x99, 0xe0,
+	0x71, 0x2d, 0x85, 0x16, 0x61, 0x03, 0xff, 0x9f, 0x3b, 0x2e, 0xf2, 0xa0, 0xc7, 0x88, 0xef, 0x04,
+	0x4e, 0x34, 0x4a, 0x7b, 0x8c, 0x20, 0x04, 0x7d, 0x8e, 0x2b, 0xea, 0xf7, 0x8c, 0x63, 0xde, 0x68,
+	0x0a, 0x43, 0x4c, 0x2a, 0xc6, 0x95, 0xef, 0x06, 0x6e, 0x34, 0x4a, 0xad, 0x42, 0x57, 0x70, 0x56,
+	0x88, 0xaa, 0xc6, 0xbc, 0xcd, 0x6a, 0x49, 0xd7, 0xec, 0x9d, 0x2a, 0xbf, 0x6f, 0x12, 0x13, 0xeb,
+	0xaf, 0xac, 0x1d, 0xde, 0xc2, 0x45, 0x77, 0xec, 0x52, 0x70, 0x8d, 0x19, 0xa7, 0x12, 0xcd, 0xe0,
+	0x0f, 0xe5, 0x5a, 0x32, 0xaa, 0x7c, 0x27, 0x70, 0xa3, 0x7f, 0xf3, 0x71, 0xdc, 0x0d, 0xa6, 0x3f,
+	0xd5, 0x70, 0x05, 0xe3, 0x65, 0xb7, 0xe9, 0x37, 0xd5, 0x6e, 0xaa, 0xa5, 0xb7, 0x0a, 0xcd, 0x60,
+	0xd2, 0xdd, 0x3b, 0x63, 0xc4, 0x2e, 0xe3, 0x75, 0xed, 0x47, 0x12, 0x2e, 0x60, 0x7a, 0xd4, 0xf1,
+	0x00, 0x15, 0x9d, 0x42, 0x79, 0xf1, 0x51, 0xf2, 0x40, 0xf5, 0x09, 0x83, 0xbb, 0x92, 0x72, 0x8d,
+	0x2e, 0x01, 0xea, 0x26, 0xdf, 0xb2, 0x22, 0x7b, 0xa5, 0xad, 0x25, 0x1a, 0xed, 0x9c, 0x27, 0xda,
+	0xfe, 0x1a, 0xca, 0xdc, 0xba, 0xd0, 0xec, 0x8d, 0xfa, 0x6e, 0xe0, 0x44, 0x7f, 0x53, 0xab, 0xd0,
+	0x39, 0x0c, 0xa4, 0xd8, 0xee, 0x0f, 0xbc, 0x13, 0xe1, 0x1c, 0x3c, 0x33, 0xfe, 0x80, 0x1e, 0x9c,
+	0xa2, 0x0f, 0x63, 0x93, 0xd8, 0x23, 0x2f, 0x1e, 0x5e, 0xee, 0x4b, 0xa6, 0x37, 0x4d, 0x1e, 0x17,
+	0xa2, 0x4a, 0xb4, 0x14, 0x4a, 0x5d, 0xeb, 0x56, 0x09, 0x9e, 0x54, 0x04, 0x6b, 0x9c, 0x95, 0x22,
+	0x51, 0xb2, 0x48, 0xd4, 0x06, 0x4b, 0x4a, 0x12, 0xf3, 0x6b, 0xf2, 0x66, 0x9d, 0x1c, 0xe1, 0xd7,
+	0xf9, 0x3c, 0x1f, 0x9a, 0xd2, 0xcd, 0xd7, 0x00, 0x3d, 0x52, 0xce, 0xab, 0x66, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,1434 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: payload.proto
+
+package payload_pb2
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	schema_pb2 "github.com/tross-tyson/mdata_go/src/shared/protobuf/schema_pb2"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// MdataPayload is the transaction payload of the mdata family, version 2.0.
+// Exactly one action is set per transaction.
+type MdataPayload struct {
+	// Types that are valid to be assigned to Action:
+	//	*MdataPayload_Create
+	//	*MdataPayload_Update
+	//	*MdataPayload_Set
+	//	*MdataPayload_Delete
+	//	*MdataPayload_Transfer
+	//	*MdataPayload_CreateOrganization
+	//	*MdataPayload_UpdateOrganization
+	//	*MdataPayload_AddOrganizationKey
+	//	*MdataPayload_RemoveOrganizationKey
+	//	*MdataPayload_CreateAgent
+	//	*MdataPayload_UpdateAgent
+	//	*MdataPayload_CreateSchema
+	//	*MdataPayload_UpdateSchema
+	//	*MdataPayload_Patch
+	//	*MdataPayload_Batch
+	Action               isMdataPayload_Action `protobuf_oneof:"action"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *MdataPayload) Reset()         { *m = MdataPayload{} }
+func (m *MdataPayload) String() string { return proto.CompactTextString(m) }
+func (*MdataPayload) ProtoMessage()    {}
+func (*MdataPayload) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{0}
+}
+
+func (m *MdataPayload) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MdataPayload.Unmarshal(m, b)
+}
+func (m *MdataPayload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MdataPayload.Marshal(b, m, deterministic)
+}
+func (m *MdataPayload) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MdataPayload.Merge(m, src)
+}
+func (m *MdataPayload) XXX_Size() int {
+	return xxx_messageInfo_MdataPayload.Size(m)
+}
+func (m *MdataPayload) XXX_DiscardUnknown() {
+	xxx_messageInfo_MdataPayload.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MdataPayload proto.InternalMessageInfo
+
+type isMdataPayload_Action interface {
+	isMdataPayload_Action()
+}
+
+type MdataPayload_Create struct {
+	Create *CreateProductAction `protobuf:"bytes,1,opt,name=create,proto3,oneof"`
+}
+
+type MdataPayload_Update struct {
+	Update *UpdateProductAction `protobuf:"bytes,2,opt,name=update,proto3,oneof"`
+}
+
+type MdataPayload_Set struct {
+	Set *SetProductStateAction `protobuf:"bytes,3,opt,name=set,proto3,oneof"`
+}
+
+type MdataPayload_Delete struct {
+	Delete *DeleteProductAction `protobuf:"bytes,4,opt,name=delete,proto3,oneof"`
+}
+
+type MdataPayload_Transfer struct {
+	Transfer *TransferProductAction `protobuf:"bytes,5,opt,name=transfer,proto3,oneof"`
+}
+
+type MdataPayload_CreateOrganization struct {
+	CreateOrganization *CreateOrganizationAction `protobuf:"bytes,6,opt,name=create_organization,json=createOrganization,proto3,oneof"`
+}
+
+type MdataPayload_UpdateOrganization struct {
+	UpdateOrganization *UpdateOrganizationAction `protobuf:"bytes,7,opt,name=update_organization,json=updateOrganization,proto3,oneof"`
+}
+
+type MdataPayload_AddOrganizationKey struct {
+	AddOrganizationKey *AddOrganizationKeyAction `protobuf:"bytes,8,opt,name=add_organization_key,json=addOrganizationKey,proto3,oneof"`
+}
+
+type MdataPayload_RemoveOrganizationKey struct {
+	RemoveOrganizationKey *RemoveOrganizationKeyAction `protobuf:"bytes,9,opt,name=remove_organization_key,json=removeOrganizationKey,proto3,oneof"`
+}
+
+type MdataPayload_CreateAgent struct {
+	CreateAgent *CreateAgentAction `protobuf:"bytes,10,opt,name=create_agent,json=createAgent,proto3,oneof"`
+}
+
+type MdataPayload_UpdateAgent struct {
+	UpdateAgent *UpdateAgentAction `protobuf:"bytes,11,opt,name=update_agent,json=updateAgent,proto3,oneof"`
+}
+
+type MdataPayload_CreateSchema struct {
+	CreateSchema *CreateSchemaAction `protobuf:"bytes,12,opt,name=create_schema,json=createSchema,proto3,oneof"`
+}
+
+type MdataPayload_UpdateSchema struct {
+	UpdateSchema *UpdateSchemaAction `protobuf:"bytes,13,opt,name=update_schema,json=updateSchema,proto3,oneof"`
+}
+
+type MdataPayload_Patch struct {
+	Patch *PatchProductAction `protobuf:"bytes,14,opt,name=patch,proto3,oneof"`
+}
+
+type MdataPayload_Batch struct {
+	Batch *BatchProductAction `protobuf:"bytes,15,opt,name=batch,proto3,oneof"`
+}
+
+func (*MdataPayload_Create) isMdataPayload_Action() {}
+
+func (*MdataPayload_Update) isMdataPayload_Action() {}
+
+func (*MdataPayload_Set) isMdataPayload_Action() {}
+
+func (*MdataPayload_Delete) isMdataPayload_Action() {}
+
+func (*MdataPayload_Transfer) isMdataPayload_Action() {}
+
+func (*MdataPayload_CreateOrganization) isMdataPayload_Action() {}
+
+func (*MdataPayload_UpdateOrganization) isMdataPayload_Action() {}
+
+func (*MdataPayload_AddOrganizationKey) isMdataPayload_Action() {}
+
+func (*MdataPayload_RemoveOrganizationKey) isMdataPayload_Action() {}
+
+func (*MdataPayload_CreateAgent) isMdataPayload_Action() {}
+
+func (*MdataPayload_UpdateAgent) isMdataPayload_Action() {}
+
+func (*MdataPayload_CreateSchema) isMdataPayload_Action() {}
+
+func (*MdataPayload_UpdateSchema) isMdataPayload_Action() {}
+
+func (*MdataPayload_Patch) isMdataPayload_Action() {}
+
+func (*MdataPayload_Batch) isMdataPayload_Action() {}
+
+func (m *MdataPayload) GetAction() isMdataPayload_Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetCreate() *CreateProductAction {
+	if x, ok := m.GetAction().(*MdataPayload_Create); ok {
+		return x.Create
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetUpdate() *UpdateProductAction {
+	if x, ok := m.GetAction().(*MdataPayload_Update); ok {
+		return x.Update
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetSet() *SetProductStateAction {
+	if x, ok := m.GetAction().(*MdataPayload_Set); ok {
+		return x.Set
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetDelete() *DeleteProductAction {
+	if x, ok := m.GetAction().(*MdataPayload_Delete); ok {
+		return x.Delete
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetTransfer() *TransferProductAction {
+	if x, ok := m.GetAction().(*MdataPayload_Transfer); ok {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetCreateOrganization() *CreateOrganizationAction {
+	if x, ok := m.GetAction().(*MdataPayload_CreateOrganization); ok {
+		return x.CreateOrganization
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetUpdateOrganization() *UpdateOrganizationAction {
+	if x, ok := m.GetAction().(*MdataPayload_UpdateOrganization); ok {
+		return x.UpdateOrganization
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetAddOrganizationKey() *AddOrganizationKeyAction {
+	if x, ok := m.GetAction().(*MdataPayload_AddOrganizationKey); ok {
+		return x.AddOrganizationKey
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetRemoveOrganizationKey() *RemoveOrganizationKeyAction {
+	if x, ok := m.GetAction().(*MdataPayload_RemoveOrganizationKey); ok {
+		return x.RemoveOrganizationKey
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetCreateAgent() *CreateAgentAction {
+	if x, ok := m.GetAction().(*MdataPayload_CreateAgent); ok {
+		return x.CreateAgent
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetUpdateAgent() *UpdateAgentAction {
+	if x, ok := m.GetAction().(*MdataPayload_UpdateAgent); ok {
+		return x.UpdateAgent
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetCreateSchema() *CreateSchemaAction {
+	if x, ok := m.GetAction().(*MdataPayload_CreateSchema); ok {
+		return x.CreateSchema
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetUpdateSchema() *UpdateSchemaAction {
+	if x, ok := m.GetAction().(*MdataPayload_UpdateSchema); ok {
+		return x.UpdateSchema
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetPatch() *PatchProductAction {
+	if x, ok := m.GetAction().(*MdataPayload_Patch); ok {
+		return x.Patch
+	}
+	return nil
+}
+
+func (m *MdataPayload) GetBatch() *BatchProductAction {
+	if x, ok := m.GetAction().(*MdataPayload_Batch); ok {
+		return x.Batch
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*MdataPayload) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*MdataPayload_Create)(nil),
+		(*MdataPayload_Update)(nil),
+		(*MdataPayload_Set)(nil),
+		(*MdataPayload_Delete)(nil),
+		(*MdataPayload_Transfer)(nil),
+		(*MdataPayload_CreateOrganization)(nil),
+		(*MdataPayload_UpdateOrganization)(nil),
+		(*MdataPayload_AddOrganizationKey)(nil),
+		(*MdataPayload_RemoveOrganizationKey)(nil),
+		(*MdataPayload_CreateAgent)(nil),
+		(*MdataPayload_UpdateAgent)(nil),
+		(*MdataPayload_CreateSchema)(nil),
+		(*MdataPayload_UpdateSchema)(nil),
+		(*MdataPayload_Patch)(nil),
+		(*MdataPayload_Batch)(nil),
+	}
+}
+
+// Attribute is a single typed product attribute.
+type Attribute struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Types that are valid to be assigned to Value:
+	//	*Attribute_StringValue
+	//	*Attribute_IntValue
+	//	*Attribute_NumberValue
+	//	*Attribute_BoolValue
+	Value                isAttribute_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Attribute) Reset()         { *m = Attribute{} }
+func (m *Attribute) String() string { return proto.CompactTextString(m) }
+func (*Attribute) ProtoMessage()    {}
+func (*Attribute) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{1}
+}
+
+func (m *Attribute) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Attribute.Unmarshal(m, b)
+}
+func (m *Attribute) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Attribute.Marshal(b, m, deterministic)
+}
+func (m *Attribute) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Attribute.Merge(m, src)
+}
+func (m *Attribute) XXX_Size() int {
+	return xxx_messageInfo_Attribute.Size(m)
+}
+func (m *Attribute) XXX_DiscardUnknown() {
+	xxx_messageInfo_Attribute.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Attribute proto.InternalMessageInfo
+
+func (m *Attribute) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type isAttribute_Value interface {
+	isAttribute_Value()
+}
+
+type Attribute_StringValue struct {
+	StringValue string `protobuf:"bytes,2,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type Attribute_IntValue struct {
+	IntValue int64 `protobuf:"zigzag64,3,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+type Attribute_NumberValue struct {
+	NumberValue float64 `protobuf:"fixed64,4,opt,name=number_value,json=numberValue,proto3,oneof"`
+}
+
+type Attribute_BoolValue struct {
+	BoolValue bool `protobuf:"varint,5,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+func (*Attribute_StringValue) isAttribute_Value() {}
+
+func (*Attribute_IntValue) isAttribute_Value() {}
+
+func (*Attribute_NumberValue) isAttribute_Value() {}
+
+func (*Attribute_BoolValue) isAttribute_Value() {}
+
+func (m *Attribute) GetValue() isAttribute_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Attribute) GetStringValue() string {
+	if x, ok := m.GetValue().(*Attribute_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (m *Attribute) GetIntValue() int64 {
+	if x, ok := m.GetValue().(*Attribute_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
+}
+
+func (m *Attribute) GetNumberValue() float64 {
+	if x, ok := m.GetValue().(*Attribute_NumberValue); ok {
+		return x.NumberValue
+	}
+	return 0
+}
+
+func (m *Attribute) GetBoolValue() bool {
+	if x, ok := m.GetValue().(*Attribute_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Attribute) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Attribute_StringValue)(nil),
+		(*Attribute_IntValue)(nil),
+		(*Attribute_NumberValue)(nil),
+		(*Attribute_BoolValue)(nil),
+	}
+}
+
+type CreateProductAction struct {
+	Gtin                 string       `protobuf:"bytes,1,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	Attributes           []*Attribute `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *CreateProductAction) Reset()         { *m = CreateProductAction{} }
+func (m *CreateProductAction) String() string { return proto.CompactTextString(m) }
+func (*CreateProductAction) ProtoMessage()    {}
+func (*CreateProductAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{2}
+}
+
+func (m *CreateProductAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateProductAction.Unmarshal(m, b)
+}
+func (m *CreateProductAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateProductAction.Marshal(b, m, deterministic)
+}
+func (m *CreateProductAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateProductAction.Merge(m, src)
+}
+func (m *CreateProductAction) XXX_Size() int {
+	return xxx_messageInfo_CreateProductAction.Size(m)
+}
+func (m *CreateProductAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateProductAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateProductAction proto.InternalMessageInfo
+
+func (m *CreateProductAction) GetGtin() string {
+	if m != nil {
+		return m.Gtin
+	}
+	return ""
+}
+
+func (m *CreateProductAction) GetAttributes() []*Attribute {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+type UpdateProductAction struct {
+	Gtin       string       `protobuf:"bytes,1,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	Attributes []*Attribute `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	// Revision the product must be at, 0 to skip the check
+	ExpectedRevision     uint64   `protobuf:"varint,3,opt,name=expected_revision,json=expectedRevision,proto3" json:"expected_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateProductAction) Reset()         { *m = UpdateProductAction{} }
+func (m *UpdateProductAction) String() string { return proto.CompactTextString(m) }
+func (*UpdateProductAction) ProtoMessage()    {}
+func (*UpdateProductAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{3}
+}
+
+func (m *UpdateProductAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateProductAction.Unmarshal(m, b)
+}
+func (m *UpdateProductAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateProductAction.Marshal(b, m, deterministic)
+}
+func (m *UpdateProductAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateProductAction.Merge(m, src)
+}
+func (m *UpdateProductAction) XXX_Size() int {
+	return xxx_messageInfo_UpdateProductAction.Size(m)
+}
+func (m *UpdateProductAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateProductAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateProductAction proto.InternalMessageInfo
+
+func (m *UpdateProductAction) GetGtin() string {
+	if m != nil {
+		return m.Gtin
+	}
+	return ""
+}
+
+func (m *UpdateProductAction) GetAttributes() []*Attribute {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *UpdateProductAction) GetExpectedRevision() uint64 {
+	if m != nil {
+		return m.ExpectedRevision
+	}
+	return 0
+}
+
+type SetProductStateAction struct {
+	Gtin  string `protobuf:"bytes,1,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// Optional reason code stored with the product, e.g. RECALL
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Revision the product must be at, 0 to skip the check
+	ExpectedRevision     uint64   `protobuf:"varint,4,opt,name=expected_revision,json=expectedRevision,proto3" json:"expected_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetProductStateAction) Reset()         { *m = SetProductStateAction{} }
+func (m *SetProductStateAction) String() string { return proto.CompactTextString(m) }
+func (*SetProductStateAction) ProtoMessage()    {}
+func (*SetProductStateAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{4}
+}
+
+func (m *SetProductStateAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetProductStateAction.Unmarshal(m, b)
+}
+func (m *SetProductStateAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetProductStateAction.Marshal(b, m, deterministic)
+}
+func (m *SetProductStateAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetProductStateAction.Merge(m, src)
+}
+func (m *SetProductStateAction) XXX_Size() int {
+	return xxx_messageInfo_SetProductStateAction.Size(m)
+}
+func (m *SetProductStateAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetProductStateAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetProductStateAction proto.InternalMessageInfo
+
+func (m *SetProductStateAction) GetGtin() string {
+	if m != nil {
+		return m.Gtin
+	}
+	return ""
+}
+
+func (m *SetProductStateAction) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *SetProductStateAction) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *SetProductStateAction) GetExpectedRevision() uint64 {
+	if m != nil {
+		return m.ExpectedRevision
+	}
+	return 0
+}
+
+type DeleteProductAction struct {
+	Gtin string `protobuf:"bytes,1,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	// Revision the product must be at, 0 to skip the check
+	ExpectedRevision     uint64   `protobuf:"varint,2,opt,name=expected_revision,json=expectedRevision,proto3" json:"expected_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteProductAction) Reset()         { *m = DeleteProductAction{} }
+func (m *DeleteProductAction) String() string { return proto.CompactTextString(m) }
+func (*DeleteProductAction) ProtoMessage()    {}
+func (*DeleteProductAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{5}
+}
+
+func (m *DeleteProductAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteProductAction.Unmarshal(m, b)
+}
+func (m *DeleteProductAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteProductAction.Marshal(b, m, deterministic)
+}
+func (m *DeleteProductAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteProductAction.Merge(m, src)
+}
+func (m *DeleteProductAction) XXX_Size() int {
+	return xxx_messageInfo_DeleteProductAction.Size(m)
+}
+func (m *DeleteProductAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteProductAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteProductAction proto.InternalMessageInfo
+
+func (m *DeleteProductAction) GetGtin() string {
+	if m != nil {
+		return m.Gtin
+	}
+	return ""
+}
+
+func (m *DeleteProductAction) GetExpectedRevision() uint64 {
+	if m != nil {
+		return m.ExpectedRevision
+	}
+	return 0
+}
+
+// TransferProductAction hands ownership of a product to another signer
+type TransferProductAction struct {
+	Gtin string `protobuf:"bytes,1,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	// Public key of the new owner, hex encoded
+	NewOwner string `protobuf:"bytes,2,opt,name=new_owner,json=newOwner,proto3" json:"new_owner,omitempty"`
+	// Revision the product must be at, 0 to skip the check
+	ExpectedRevision     uint64   `protobuf:"varint,3,opt,name=expected_revision,json=expectedRevision,proto3" json:"expected_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferProductAction) Reset()         { *m = TransferProductAction{} }
+func (m *TransferProductAction) String() string { return proto.CompactTextString(m) }
+func (*TransferProductAction) ProtoMessage()    {}
+func (*TransferProductAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{6}
+}
+
+func (m *TransferProductAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferProductAction.Unmarshal(m, b)
+}
+func (m *TransferProductAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferProductAction.Marshal(b, m, deterministic)
+}
+func (m *TransferProductAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferProductAction.Merge(m, src)
+}
+func (m *TransferProductAction) XXX_Size() int {
+	return xxx_messageInfo_TransferProductAction.Size(m)
+}
+func (m *TransferProductAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferProductAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferProductAction proto.InternalMessageInfo
+
+func (m *TransferProductAction) GetGtin() string {
+	if m != nil {
+		return m.Gtin
+	}
+	return ""
+}
+
+func (m *TransferProductAction) GetNewOwner() string {
+	if m != nil {
+		return m.NewOwner
+	}
+	return ""
+}
+
+func (m *TransferProductAction) GetExpectedRevision() uint64 {
+	if m != nil {
+		return m.ExpectedRevision
+	}
+	return 0
+}
+
+// PatchProductAction sets the given attributes and removes the unset keys,
+// leaving every other attribute of the product as it is
+type PatchProductAction struct {
+	Gtin       string       `protobuf:"bytes,1,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	Attributes []*Attribute `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	UnsetKeys  []string     `protobuf:"bytes,3,rep,name=unset_keys,json=unsetKeys,proto3" json:"unset_keys,omitempty"`
+	// Revision the product must be at, 0 to skip the check
+	ExpectedRevision     uint64   `protobuf:"varint,4,opt,name=expected_revision,json=expectedRevision,proto3" json:"expected_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PatchProductAction) Reset()         { *m = PatchProductAction{} }
+func (m *PatchProductAction) String() string { return proto.CompactTextString(m) }
+func (*PatchProductAction) ProtoMessage()    {}
+func (*PatchProductAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{7}
+}
+
+func (m *PatchProductAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PatchProductAction.Unmarshal(m, b)
+}
+func (m *PatchProductAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PatchProductAction.Marshal(b, m, deterministic)
+}
+func (m *PatchProductAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PatchProductAction.Merge(m, src)
+}
+func (m *PatchProductAction) XXX_Size() int {
+	return xxx_messageInfo_PatchProductAction.Size(m)
+}
+func (m *PatchProductAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_PatchProductAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PatchProductAction proto.InternalMessageInfo
+
+func (m *PatchProductAction) GetGtin() string {
+	if m != nil {
+		return m.Gtin
+	}
+	return ""
+}
+
+func (m *PatchProductAction) GetAttributes() []*Attribute {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *PatchProductAction) GetUnsetKeys() []string {
+	if m != nil {
+		return m.UnsetKeys
+	}
+	return nil
+}
+
+func (m *PatchProductAction) GetExpectedRevision() uint64 {
+	if m != nil {
+		return m.ExpectedRevision
+	}
+	return 0
+}
+
+// ProductOperation is one operation of a BatchProductAction
+type ProductOperation struct {
+	// Types that are valid to be assigned to Action:
+	//	*ProductOperation_Create
+	//	*ProductOperation_Update
+	//	*ProductOperation_Set
+	//	*ProductOperation_Delete
+	Action               isProductOperation_Action `protobuf_oneof:"action"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *ProductOperation) Reset()         { *m = ProductOperation{} }
+func (m *ProductOperation) String() string { return proto.CompactTextString(m) }
+func (*ProductOperation) ProtoMessage()    {}
+func (*ProductOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{8}
+}
+
+func (m *ProductOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductOperation.Unmarshal(m, b)
+}
+func (m *ProductOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductOperation.Marshal(b, m, deterministic)
+}
+func (m *ProductOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductOperation.Merge(m, src)
+}
+func (m *ProductOperation) XXX_Size() int {
+	return xxx_messageInfo_ProductOperation.Size(m)
+}
+func (m *ProductOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProductOperation proto.InternalMessageInfo
+
+type isProductOperation_Action interface {
+	isProductOperation_Action()
+}
+
+type ProductOperation_Create struct {
+	Create *CreateProductAction `protobuf:"bytes,1,opt,name=create,proto3,oneof"`
+}
+
+type ProductOperation_Update struct {
+	Update *UpdateProductAction `protobuf:"bytes,2,opt,name=update,proto3,oneof"`
+}
+
+type ProductOperation_Set struct {
+	Set *SetProductStateAction `protobuf:"bytes,3,opt,name=set,proto3,oneof"`
+}
+
+type ProductOperation_Delete struct {
+	Delete *DeleteProductAction `protobuf:"bytes,4,opt,name=delete,proto3,oneof"`
+}
+
+func (*ProductOperation_Create) isProductOperation_Action() {}
+
+func (*ProductOperation_Update) isProductOperation_Action() {}
+
+func (*ProductOperation_Set) isProductOperation_Action() {}
+
+func (*ProductOperation_Delete) isProductOperation_Action() {}
+
+func (m *ProductOperation) GetAction() isProductOperation_Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+func (m *ProductOperation) GetCreate() *CreateProductAction {
+	if x, ok := m.GetAction().(*ProductOperation_Create); ok {
+		return x.Create
+	}
+	return nil
+}
+
+func (m *ProductOperation) GetUpdate() *UpdateProductAction {
+	if x, ok := m.GetAction().(*ProductOperation_Update); ok {
+		return x.Update
+	}
+	return nil
+}
+
+func (m *ProductOperation) GetSet() *SetProductStateAction {
+	if x, ok := m.GetAction().(*ProductOperation_Set); ok {
+		return x.Set
+	}
+	return nil
+}
+
+func (m *ProductOperation) GetDelete() *DeleteProductAction {
+	if x, ok := m.GetAction().(*ProductOperation_Delete); ok {
+		return x.Delete
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ProductOperation) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ProductOperation_Create)(nil),
+		(*ProductOperation_Update)(nil),
+		(*ProductOperation_Set)(nil),
+		(*ProductOperation_Delete)(nil),
+	}
+}
+
+// BatchProductAction applies its operations in order. If any of them fails
+// the transaction is invalid and none of them is applied.
+type BatchProductAction struct {
+	Operations           []*ProductOperation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *BatchProductAction) Reset()         { *m = BatchProductAction{} }
+func (m *BatchProductAction) String() string { return proto.CompactTextString(m) }
+func (*BatchProductAction) ProtoMessage()    {}
+func (*BatchProductAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{9}
+}
+
+func (m *BatchProductAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchProductAction.Unmarshal(m, b)
+}
+func (m *BatchProductAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchProductAction.Marshal(b, m, deterministic)
+}
+func (m *BatchProductAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchProductAction.Merge(m, src)
+}
+func (m *BatchProductAction) XXX_Size() int {
+	return xxx_messageInfo_BatchProductAction.Size(m)
+}
+func (m *BatchProductAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchProductAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchProductAction proto.InternalMessageInfo
+
+func (m *BatchProductAction) GetOperations() []*ProductOperation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+// CreateOrganizationAction registers a new organization. The signer becomes
+// its first admin.
+type CreateOrganizationAction struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CompanyPrefixes      []string `protobuf:"bytes,3,rep,name=company_prefixes,json=companyPrefixes,proto3" json:"company_prefixes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateOrganizationAction) Reset()         { *m = CreateOrganizationAction{} }
+func (m *CreateOrganizationAction) String() string { return proto.CompactTextString(m) }
+func (*CreateOrganizationAction) ProtoMessage()    {}
+func (*CreateOrganizationAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{10}
+}
+
+func (m *CreateOrganizationAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateOrganizationAction.Unmarshal(m, b)
+}
+func (m *CreateOrganizationAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateOrganizationAction.Marshal(b, m, deterministic)
+}
+func (m *CreateOrganizationAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateOrganizationAction.Merge(m, src)
+}
+func (m *CreateOrganizationAction) XXX_Size() int {
+	return xxx_messageInfo_CreateOrganizationAction.Size(m)
+}
+func (m *CreateOrganizationAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateOrganizationAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateOrganizationAction proto.InternalMessageInfo
+
+func (m *CreateOrganizationAction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CreateOrganizationAction) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateOrganizationAction) GetCompanyPrefixes() []string {
+	if m != nil {
+		return m.CompanyPrefixes
+	}
+	return nil
+}
+
+// UpdateOrganizationAction replaces the name and company prefixes of an
+// organization
+type UpdateOrganizationAction struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CompanyPrefixes      []string `protobuf:"bytes,3,rep,name=company_prefixes,json=companyPrefixes,proto3" json:"company_prefixes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateOrganizationAction) Reset()         { *m = UpdateOrganizationAction{} }
+func (m *UpdateOrganizationAction) String() string { return proto.CompactTextString(m) }
+func (*UpdateOrganizationAction) ProtoMessage()    {}
+func (*UpdateOrganizationAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{11}
+}
+
+func (m *UpdateOrganizationAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateOrganizationAction.Unmarshal(m, b)
+}
+func (m *UpdateOrganizationAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateOrganizationAction.Marshal(b, m, deterministic)
+}
+func (m *UpdateOrganizationAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateOrganizationAction.Merge(m, src)
+}
+func (m *UpdateOrganizationAction) XXX_Size() int {
+	return xxx_messageInfo_UpdateOrganizationAction.Size(m)
+}
+func (m *UpdateOrganizationAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateOrganizationAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateOrganizationAction proto.InternalMessageInfo
+
+func (m *UpdateOrganizationAction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *UpdateOrganizationAction) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UpdateOrganizationAction) GetCompanyPrefixes() []string {
+	if m != nil {
+		return m.CompanyPrefixes
+	}
+	return nil
+}
+
+// AddOrganizationKeyAction adds an admin public key to an organization
+type AddOrganizationKeyAction struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublicKey            string   `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddOrganizationKeyAction) Reset()         { *m = AddOrganizationKeyAction{} }
+func (m *AddOrganizationKeyAction) String() string { return proto.CompactTextString(m) }
+func (*AddOrganizationKeyAction) ProtoMessage()    {}
+func (*AddOrganizationKeyAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{12}
+}
+
+func (m *AddOrganizationKeyAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddOrganizationKeyAction.Unmarshal(m, b)
+}
+func (m *AddOrganizationKeyAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddOrganizationKeyAction.Marshal(b, m, deterministic)
+}
+func (m *AddOrganizationKeyAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddOrganizationKeyAction.Merge(m, src)
+}
+func (m *AddOrganizationKeyAction) XXX_Size() int {
+	return xxx_messageInfo_AddOrganizationKeyAction.Size(m)
+}
+func (m *AddOrganizationKeyAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddOrganizationKeyAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddOrganizationKeyAction proto.InternalMessageInfo
+
+func (m *AddOrganizationKeyAction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AddOrganizationKeyAction) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+// RemoveOrganizationKeyAction removes an admin public key from an organization
+type RemoveOrganizationKeyAction struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublicKey            string   `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveOrganizationKeyAction) Reset()         { *m = RemoveOrganizationKeyAction{} }
+func (m *RemoveOrganizationKeyAction) String() string { return proto.CompactTextString(m) }
+func (*RemoveOrganizationKeyAction) ProtoMessage()    {}
+func (*RemoveOrganizationKeyAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{13}
+}
+
+func (m *RemoveOrganizationKeyAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveOrganizationKeyAction.Unmarshal(m, b)
+}
+func (m *RemoveOrganizationKeyAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveOrganizationKeyAction.Marshal(b, m, deterministic)
+}
+func (m *RemoveOrganizationKeyAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveOrganizationKeyAction.Merge(m, src)
+}
+func (m *RemoveOrganizationKeyAction) XXX_Size() int {
+	return xxx_messageInfo_RemoveOrganizationKeyAction.Size(m)
+}
+func (m *RemoveOrganizationKeyAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveOrganizationKeyAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveOrganizationKeyAction proto.InternalMessageInfo
+
+func (m *RemoveOrganizationKeyAction) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RemoveOrganizationKeyAction) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+// CreateAgentAction lets a public key act for an organization with the given
+// roles. Only admins of the organization can add agents.
+type CreateAgentAction struct {
+	PublicKey            string   `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	OrganizationId       string   `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Active               bool     `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	Roles                []string `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAgentAction) Reset()         { *m = CreateAgentAction{} }
+func (m *CreateAgentAction) String() string { return proto.CompactTextString(m) }
+func (*CreateAgentAction) ProtoMessage()    {}
+func (*CreateAgentAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{14}
+}
+
+func (m *CreateAgentAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAgentAction.Unmarshal(m, b)
+}
+func (m *CreateAgentAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAgentAction.Marshal(b, m, deterministic)
+}
+func (m *CreateAgentAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAgentAction.Merge(m, src)
+}
+func (m *CreateAgentAction) XXX_Size() int {
+	return xxx_messageInfo_CreateAgentAction.Size(m)
+}
+func (m *CreateAgentAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAgentAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAgentAction proto.InternalMessageInfo
+
+func (m *CreateAgentAction) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *CreateAgentAction) GetOrganizationId() string {
+	if m != nil {
+		return m.OrganizationId
+	}
+	return ""
+}
+
+func (m *CreateAgentAction) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *CreateAgentAction) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+// UpdateAgentAction replaces the active flag and roles of an agent
+type UpdateAgentAction struct {
+	PublicKey            string   `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Active               bool     `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Roles                []string `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateAgentAction) Reset()         { *m = UpdateAgentAction{} }
+func (m *UpdateAgentAction) String() string { return proto.CompactTextString(m) }
+func (*UpdateAgentAction) ProtoMessage()    {}
+func (*UpdateAgentAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{15}
+}
+
+func (m *UpdateAgentAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateAgentAction.Unmarshal(m, b)
+}
+func (m *UpdateAgentAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateAgentAction.Marshal(b, m, deterministic)
+}
+func (m *UpdateAgentAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateAgentAction.Merge(m, src)
+}
+func (m *UpdateAgentAction) XXX_Size() int {
+	return xxx_messageInfo_UpdateAgentAction.Size(m)
+}
+func (m *UpdateAgentAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateAgentAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateAgentAction proto.InternalMessageInfo
+
+func (m *UpdateAgentAction) GetPublicKey() string {
+	if m != nil {
+		return m.PublicKey
+	}
+	return ""
+}
+
+func (m *UpdateAgentAction) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *UpdateAgentAction) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+// CreateSchemaAction registers a schema owned by an organization. The schema
+// named "product" is the one product attributes are checked against.
+type CreateSchemaAction struct {
+	Name                 string                           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	OrganizationId       string                           `protobuf:"bytes,2,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Properties           []*schema_pb2.PropertyDefinition `protobuf:"bytes,3,rep,name=properties,proto3" json:"properties,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
+	XXX_unrecognized     []byte                           `json:"-"`
+	XXX_sizecache        int32                            `json:"-"`
+}
+
+func (m *CreateSchemaAction) Reset()         { *m = CreateSchemaAction{} }
+func (m *CreateSchemaAction) String() string { return proto.CompactTextString(m) }
+func (*CreateSchemaAction) ProtoMessage()    {}
+func (*CreateSchemaAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{16}
+}
+
+func (m *CreateSchemaAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSchemaAction.Unmarshal(m, b)
+}
+func (m *CreateSchemaAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSchemaAction.Marshal(b, m, deterministic)
+}
+func (m *CreateSchemaAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSchemaAction.Merge(m, src)
+}
+func (m *CreateSchemaAction) XXX_Size() int {
+	return xxx_messageInfo_CreateSchemaAction.Size(m)
+}
+func (m *CreateSchemaAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSchemaAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSchemaAction proto.InternalMessageInfo
+
+func (m *CreateSchemaAction) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateSchemaAction) GetOrganizationId() string {
+	if m != nil {
+		return m.OrganizationId
+	}
+	return ""
+}
+
+func (m *CreateSchemaAction) GetProperties() []*schema_pb2.PropertyDefinition {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
+// UpdateSchemaAction replaces the properties of a schema
+type UpdateSchemaAction struct {
+	Name                 string                           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Properties           []*schema_pb2.PropertyDefinition `protobuf:"bytes,2,rep,name=properties,proto3" json:"properties,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
+	XXX_unrecognized     []byte                           `json:"-"`
+	XXX_sizecache        int32                            `json:"-"`
+}
+
+func (m *UpdateSchemaAction) Reset()         { *m = UpdateSchemaAction{} }
+func (m *UpdateSchemaAction) String() string { return proto.CompactTextString(m) }
+func (*UpdateSchemaAction) ProtoMessage()    {}
+func (*UpdateSchemaAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_678c914f1bee6d56, []int{17}
+}
+
+func (m *UpdateSchemaAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateSchemaAction.Unmarshal(m, b)
+}
+func (m *UpdateSchemaAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateSchemaAction.Marshal(b, m, deterministic)
+}
+func (m *UpdateSchemaAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateSchemaAction.Merge(m, src)
+}
+func (m *UpdateSchemaAction) XXX_Size() int {
+	return xxx_messageInfo_UpdateSchemaAction.Size(m)
+}
+func (m *UpdateSchemaAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateSchemaAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateSchemaAction proto.InternalMessageInfo
+
+func (m *UpdateSchemaAction) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UpdateSchemaAction) GetProperties() []*schema_pb2.PropertyDefinition {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*MdataPayload)(nil), "MdataPayload")
+	proto.RegisterType((*Attribute)(nil), "Attribute")
+	proto.RegisterType((*CreateProductAction)(nil), "CreateProductAction")
+	proto.RegisterType((*UpdateProductAction)(nil), "UpdateProductAction")
+	proto.RegisterType((*SetProductStateAction)(nil), "SetProductStateAction")
+	proto.RegisterType((*DeleteProductAction)(nil), "DeleteProductAction")
+	proto.RegisterType((*TransferProductAction)(nil), "TransferProductAction")
+	proto.RegisterType((*PatchProductAction)(nil), "PatchProductAction")
+	proto.RegisterType((*ProductOperation)(nil), "ProductOperation")
+	proto.RegisterType((*BatchProductAction)(nil), "BatchProductAction")
+	proto.RegisterType((*CreateOrganizationAction)(nil), "CreateOrganizationAction")
+	proto.RegisterType((*UpdateOrganizationAction)(nil), "UpdateOrganizationAction")
+	proto.RegisterType((*AddOrganizationKeyAction)(nil), "AddOrganizationKeyAction")
+	proto.RegisterType((*RemoveOrganizationKeyAction)(nil), "RemoveOrganizationKeyAction")
+	proto.RegisterType((*CreateAgentAction)(nil), "CreateAgentAction")
+	proto.RegisterType((*UpdateAgentAction)(nil), "UpdateAgentAction")
+	proto.RegisterType((*CreateSchemaAction)(nil), "CreateSchemaAction")
+	proto.RegisterType((*UpdateSchemaAction)(nil), "UpdateSchemaAction")
+}
+
+func init() { proto.RegisterFile("payload.proto", fileDescriptor_678c914f1bee6d56) }
+
+var fileDescriptor_678c914f1bee6d56 = []byte{
+	// 974 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x56, 0x5f, 0x6f, 0xe3, 0x44,
+	0x10, 0x3f, 0x27, 0x69, 0x1a, 0x4f, 0xd3, 0x7f, 0x9b, 0xb6, 0x18, 0x8e, 0x8a, 0xca, 0xf7, 0x40,
+	0xb9, 0x13, 0x89, 0xe8, 0x21, 0x21, 0xf1, 0x82, 0x52, 0x4e, 0xe2, 0x4e, 0xbd, 0x53, 0x2b, 0x97,
+	0xeb, 0x03, 0x12, 0x0a, 0x6b, 0x7b, 0x9b, 0xae, 0x48, 0x6c, 0x6b, 0x77, 0xdd, 0x5e, 0x78, 0xe4,
+	0xe1, 0x24, 0x1e, 0xf9, 0x06, 0x7c, 0x04, 0x3e, 0x0e, 0x1f, 0x07, 0xed, 0xee, 0x24, 0x71, 0x62,
+	0x07, 0xae, 0x3a, 0x5e, 0x78, 0xf3, 0xfc, 0xe6, 0x37, 0xbf, 0xd9, 0x9d, 0xdd, 0x9d, 0x31, 0x6c,
+	0x66, 0x74, 0x32, 0x4a, 0x69, 0xdc, 0xcd, 0x44, 0xaa, 0xd2, 0x8f, 0xda, 0x32, 0xba, 0x61, 0x63,
+	0x6a, 0x2d, 0xff, 0xf7, 0x75, 0x68, 0xbf, 0x8a, 0xa9, 0xa2, 0x17, 0x96, 0x44, 0xba, 0xd0, 0x8c,
+	0x04, 0xa3, 0x8a, 0x79, 0xce, 0x91, 0x73, 0xbc, 0x71, 0xb2, 0xd7, 0xfd, 0xd6, 0x98, 0x17, 0x22,
+	0x8d, 0xf3, 0x48, 0xf5, 0x23, 0xc5, 0xd3, 0xe4, 0xf9, 0x83, 0x00, 0x59, 0x9a, 0x9f, 0x67, 0xb1,
+	0xe6, 0xd7, 0x90, 0xff, 0x3a, 0x8b, 0xab, 0xf8, 0x96, 0x45, 0x1e, 0x43, 0x5d, 0x32, 0xe5, 0xd5,
+	0x0d, 0xf9, 0xa0, 0x7b, 0xc9, 0x14, 0x32, 0x2f, 0x15, 0x55, 0x6c, 0x46, 0xd7, 0x24, 0xad, 0x1d,
+	0xb3, 0x11, 0x53, 0xcc, 0x6b, 0xa0, 0xf6, 0x33, 0x63, 0x96, 0xb4, 0x2d, 0x8b, 0x7c, 0x09, 0x2d,
+	0x25, 0x68, 0x22, 0xaf, 0x99, 0xf0, 0xd6, 0x30, 0xc1, 0xf7, 0x08, 0x2c, 0xc7, 0xcc, 0x98, 0xe4,
+	0x25, 0x74, 0xec, 0x5e, 0x06, 0xa9, 0x18, 0xd2, 0x84, 0xff, 0x42, 0x35, 0xc5, 0x6b, 0x1a, 0x81,
+	0x0f, 0x71, 0xfb, 0xe7, 0x05, 0xd7, 0x4c, 0x83, 0x44, 0x25, 0x9f, 0x56, 0xb3, 0x3b, 0x5d, 0x54,
+	0x5b, 0x47, 0x35, 0x5b, 0x9c, 0x6a, 0xb5, 0xbc, 0xe4, 0x23, 0xaf, 0x60, 0x8f, 0xc6, 0xf1, 0x82,
+	0xd4, 0xe0, 0x67, 0x36, 0xf1, 0x5a, 0x28, 0xd7, 0x8f, 0xe3, 0x22, 0xff, 0x8c, 0x4d, 0xe6, 0x72,
+	0xb4, 0xe4, 0x23, 0x57, 0xf0, 0x81, 0x60, 0xe3, 0xf4, 0x96, 0x95, 0x15, 0x5d, 0xa3, 0xf8, 0x71,
+	0x37, 0x30, 0xfe, 0x55, 0xa2, 0xfb, 0xa2, 0xca, 0x4d, 0xbe, 0x82, 0x36, 0x96, 0x90, 0x0e, 0x59,
+	0xa2, 0x3c, 0x30, 0x62, 0x04, 0x6b, 0xd7, 0xd7, 0xd8, 0x4c, 0x62, 0x23, 0x9a, 0x83, 0x3a, 0x10,
+	0xab, 0x65, 0x03, 0x37, 0x30, 0xd0, 0x96, 0x69, 0x29, 0x30, 0x9f, 0x83, 0xe4, 0x6b, 0xd8, 0xc4,
+	0x8c, 0xf6, 0x3a, 0x7b, 0x6d, 0x13, 0xd9, 0xc1, 0x94, 0x97, 0x06, 0x9c, 0x85, 0xb6, 0xa3, 0x02,
+	0xaa, 0x63, 0x31, 0x29, 0xc6, 0x6e, 0x62, 0xac, 0xcd, 0xba, 0x1c, 0x9b, 0x17, 0x50, 0xf2, 0x04,
+	0xd6, 0x32, 0xaa, 0xa2, 0x1b, 0x6f, 0x0b, 0x63, 0x2e, 0xb4, 0xb5, 0x7c, 0xb9, 0x2c, 0x47, 0x93,
+	0x43, 0x43, 0xde, 0x46, 0xf2, 0x69, 0x25, 0xd9, 0x70, 0x4e, 0x5b, 0xd0, 0xa4, 0x06, 0xf2, 0xff,
+	0x74, 0xc0, 0xed, 0x2b, 0x25, 0x78, 0x98, 0x2b, 0x46, 0x76, 0xa0, 0xae, 0xcf, 0x47, 0xbf, 0x46,
+	0x37, 0xd0, 0x9f, 0xe4, 0x11, 0xb4, 0xa5, 0x12, 0x3c, 0x19, 0x0e, 0x6e, 0xe9, 0x28, 0xb7, 0x0f,
+	0xcf, 0xd5, 0x05, 0xb2, 0xe8, 0x95, 0x06, 0xc9, 0x21, 0xb8, 0x3c, 0x51, 0xc8, 0xd0, 0xaf, 0x8d,
+	0xe8, 0x4b, 0xcf, 0x13, 0x65, 0xdd, 0x8f, 0xa0, 0x9d, 0xe4, 0xe3, 0x90, 0x09, 0x64, 0xe8, 0x07,
+	0xe6, 0x68, 0x0d, 0x8b, 0x5a, 0xd2, 0x27, 0x00, 0x61, 0x9a, 0x8e, 0x90, 0xa2, 0x5f, 0x54, 0xeb,
+	0xf9, 0x83, 0xc0, 0xd5, 0x98, 0x21, 0x9c, 0xae, 0xc3, 0x9a, 0xf1, 0xf9, 0xaf, 0xa1, 0x53, 0xd1,
+	0x26, 0x08, 0x81, 0xc6, 0x50, 0xf1, 0x04, 0x17, 0x6f, 0xbe, 0xc9, 0x63, 0x00, 0x3a, 0xdd, 0x9c,
+	0xf4, 0x6a, 0x47, 0xf5, 0xe3, 0x8d, 0x13, 0xe8, 0xce, 0xf6, 0x1b, 0x14, 0xbc, 0xfe, 0xaf, 0x0e,
+	0x74, 0x2a, 0xda, 0xc9, 0xfb, 0xea, 0x92, 0x27, 0xb0, 0xcb, 0xde, 0x64, 0x2c, 0x52, 0x2c, 0x1e,
+	0x08, 0x76, 0xcb, 0xa5, 0x7e, 0xa2, 0xba, 0x48, 0x8d, 0x60, 0x67, 0xea, 0x08, 0x10, 0xf7, 0xdf,
+	0x3a, 0xb0, 0x5f, 0xd9, 0xa6, 0x2a, 0x97, 0xb1, 0x07, 0x6b, 0x52, 0x4d, 0xdb, 0xa1, 0x1b, 0x58,
+	0x83, 0x1c, 0x40, 0x53, 0x30, 0x2a, 0x31, 0x8b, 0x1b, 0xa0, 0x55, 0xbd, 0x90, 0xc6, 0x8a, 0x85,
+	0x5c, 0x41, 0xa7, 0xa2, 0xff, 0x55, 0xae, 0xa2, 0x52, 0xb7, 0xb6, 0x42, 0x37, 0x87, 0xfd, 0xca,
+	0x2e, 0x59, 0xa9, 0xfc, 0x10, 0xdc, 0x84, 0xdd, 0x0d, 0xd2, 0xbb, 0x84, 0x09, 0xdc, 0x63, 0x2b,
+	0x61, 0x77, 0xe7, 0xda, 0xbe, 0x5f, 0x5d, 0xff, 0x70, 0x80, 0x94, 0x5f, 0xcf, 0x7b, 0x9f, 0xed,
+	0x21, 0x40, 0x9e, 0x48, 0xa6, 0x74, 0x57, 0x93, 0x5e, 0xfd, 0xa8, 0x7e, 0xec, 0x06, 0xae, 0x41,
+	0xce, 0xd8, 0x44, 0xde, 0xaf, 0xe2, 0x7f, 0x39, 0xb0, 0x83, 0xab, 0x3b, 0xcf, 0x98, 0xb0, 0x3d,
+	0xf9, 0x7f, 0x3c, 0x21, 0x0b, 0x4d, 0xe6, 0x3b, 0x20, 0xe5, 0x6e, 0x44, 0xbe, 0x00, 0x48, 0xa7,
+	0x1b, 0x95, 0x9e, 0x63, 0x0a, 0xbd, 0xdb, 0x5d, 0x2e, 0x41, 0x50, 0x20, 0xf9, 0x1c, 0xbc, 0x55,
+	0x23, 0x92, 0x6c, 0x41, 0x8d, 0xc7, 0x78, 0x92, 0x35, 0x1e, 0xeb, 0xb3, 0x4d, 0xe8, 0x78, 0xfa,
+	0x36, 0xcc, 0x37, 0xf9, 0x0c, 0x76, 0xa2, 0x74, 0x9c, 0xd1, 0x64, 0x32, 0xc8, 0x04, 0xbb, 0xe6,
+	0x6f, 0xd8, 0xf4, 0xd4, 0xb6, 0x11, 0xbf, 0x40, 0x58, 0xa7, 0x5a, 0x35, 0x3f, 0xff, 0xeb, 0x54,
+	0x2f, 0xc0, 0x5b, 0x35, 0x5b, 0x4b, 0xa9, 0x0e, 0x01, 0xb2, 0x3c, 0x1c, 0xf1, 0xc8, 0x0c, 0x52,
+	0x9b, 0xd0, 0xb5, 0xc8, 0x19, 0x9b, 0xf8, 0x2f, 0xe1, 0xe1, 0x3f, 0x0c, 0xd5, 0xfb, 0xaa, 0xfd,
+	0xe6, 0xc0, 0x6e, 0x69, 0xac, 0x2e, 0x05, 0x39, 0x4b, 0x41, 0xe4, 0x53, 0xd8, 0x5e, 0x18, 0xf8,
+	0x3c, 0x46, 0xe1, 0xad, 0x22, 0xfc, 0x22, 0x26, 0x07, 0xf6, 0x7e, 0xdc, 0xda, 0x91, 0xd1, 0x0a,
+	0xd0, 0xd2, 0x5d, 0x4d, 0xa4, 0x23, 0x26, 0xbd, 0x86, 0x29, 0x97, 0x35, 0xfc, 0x9f, 0x60, 0xb7,
+	0x34, 0xa8, 0xff, 0x6d, 0x29, 0xf3, 0x0c, 0xb5, 0xea, 0x0c, 0xf5, 0x62, 0x86, 0xb7, 0x0e, 0x90,
+	0xf2, 0x44, 0x9f, 0x1d, 0xae, 0x53, 0x38, 0xdc, 0x77, 0xde, 0xe3, 0x53, 0x80, 0x4c, 0xe8, 0x0b,
+	0xac, 0x38, 0xa6, 0x33, 0x73, 0xdc, 0x42, 0x93, 0x67, 0xec, 0x9a, 0x27, 0xdc, 0xde, 0xf2, 0x39,
+	0xcd, 0xff, 0x11, 0x48, 0xf9, 0xef, 0xa0, 0x72, 0x1d, 0x8b, 0xf2, 0xb5, 0x77, 0x92, 0x3f, 0xed,
+	0xff, 0xf0, 0xcd, 0x90, 0xab, 0x9b, 0x3c, 0xec, 0x46, 0xe9, 0xb8, 0xa7, 0x44, 0x2a, 0xe5, 0xe7,
+	0x6a, 0x22, 0xd3, 0xa4, 0x37, 0x8e, 0xa9, 0xa2, 0x83, 0x61, 0xda, 0x93, 0x22, 0xea, 0xc9, 0x1b,
+	0x2a, 0x58, 0xdc, 0x33, 0xbf, 0xee, 0x61, 0x7e, 0xdd, 0xc3, 0x3f, 0xfb, 0x41, 0x16, 0x9e, 0x84,
+	0x4d, 0x83, 0x3e, 0xfd, 0x7b, 0x00, 0x0c, 0x4d, 0x47, 0xb0, 0xef, 0x0b, 0x00, 0x00,
+}
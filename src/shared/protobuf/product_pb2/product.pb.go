@@ -0,0 +1,362 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: product.proto
+
+package product_pb2
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	payload_pb2 "github.com/tross-tyson/mdata_go/src/shared/protobuf/payload_pb2"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Product is the state record of a single GTIN. Attributes are sorted by key
+// so that the same product always serializes to the same bytes.
+type Product struct {
+	Gtin       string                   `protobuf:"bytes,1,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	Attributes []*payload_pb2.Attribute `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	State      string                   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	// Public key of the signer allowed to change the product
+	Owner string `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`
+	// Reason code given with the last state change
+	Reason string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Revision of the product, increased by every change
+	Revision             uint64   `protobuf:"varint,6,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Product) Reset()         { *m = Product{} }
+func (m *Product) String() string { return proto.CompactTextString(m) }
+func (*Product) ProtoMessage()    {}
+func (*Product) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f0fd8b59378f44a5, []int{0}
+}
+
+func (m *Product) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Product.Unmarshal(m, b)
+}
+func (m *Product) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Product.Marshal(b, m, deterministic)
+}
+func (m *Product) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Product.Merge(m, src)
+}
+func (m *Product) XXX_Size() int {
+	return xxx_messageInfo_Product.Size(m)
+}
+func (m *Product) XXX_DiscardUnknown() {
+	xxx_messageInfo_Product.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Product proto.InternalMessageInfo
+
+func (m *Product) GetGtin() string {
+	if m != nil {
+		return m.Gtin
+	}
+	return ""
+}
+
+func (m *Product) GetAttributes() []*payload_pb2.Attribute {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+func (m *Product) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Product) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *Product) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Product) GetRevision() uint64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+// ProductContainer holds every product stored at one state address, sorted by
+// GTIN. More than one product only shares an address on a hash collision.
+type ProductContainer struct {
+	Entries              []*Product `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ProductContainer) Reset()         { *m = ProductContainer{} }
+func (m *ProductContainer) String() string { return proto.CompactTextString(m) }
+func (*ProductContainer) ProtoMessage()    {}
+func (*ProductContainer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f0fd8b59378f44a5, []int{1}
+}
+
+func (m *ProductContainer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductContainer.Unmarshal(m, b)
+}
+func (m *ProductContainer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductContainer.Marshal(b, m, deterministic)
+}
+func (m *ProductContainer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductContainer.Merge(m, src)
+}
+func (m *ProductContainer) XXX_Size() int {
+	return xxx_messageInfo_ProductContainer.Size(m)
+}
+func (m *ProductContainer) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductContainer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProductContainer proto.InternalMessageInfo
+
+func (m *ProductContainer) GetEntries() []*Product {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// ProductRevision records one change of a product
+type ProductRevision struct {
+	Revision uint64 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Action   string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// Public key of the signer of the change
+	Signer string `protobuf:"bytes,3,opt,name=signer,proto3" json:"signer,omitempty"`
+	// Id of the transaction, which leads to the block and its time
+	TransactionId string `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// Fields the change set or removed: state, owner, reason or
+	// attributes.<key>, sorted
+	ChangedFields []string `protobuf:"bytes,5,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"`
+	// Values of the changed fields before the change, keyed by field name.
+	// Fields that had no value are left out.
+	PreviousValues       []*payload_pb2.Attribute `protobuf:"bytes,6,rep,name=previous_values,json=previousValues,proto3" json:"previous_values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *ProductRevision) Reset()         { *m = ProductRevision{} }
+func (m *ProductRevision) String() string { return proto.CompactTextString(m) }
+func (*ProductRevision) ProtoMessage()    {}
+func (*ProductRevision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f0fd8b59378f44a5, []int{2}
+}
+
+func (m *ProductRevision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductRevision.Unmarshal(m, b)
+}
+func (m *ProductRevision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductRevision.Marshal(b, m, deterministic)
+}
+func (m *ProductRevision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductRevision.Merge(m, src)
+}
+func (m *ProductRevision) XXX_Size() int {
+	return xxx_messageInfo_ProductRevision.Size(m)
+}
+func (m *ProductRevision) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductRevision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProductRevision proto.InternalMessageInfo
+
+func (m *ProductRevision) GetRevision() uint64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *ProductRevision) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *ProductRevision) GetSigner() string {
+	if m != nil {
+		return m.Signer
+	}
+	return ""
+}
+
+func (m *ProductRevision) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *ProductRevision) GetChangedFields() []string {
+	if m != nil {
+		return m.ChangedFields
+	}
+	return nil
+}
+
+func (m *ProductRevision) GetPreviousValues() []*payload_pb2.Attribute {
+	if m != nil {
+		return m.PreviousValues
+	}
+	return nil
+}
+
+// ProductHistory holds every revision of one GTIN, oldest first. It is kept
+// after the product is deleted.
+type ProductHistory struct {
+	Gtin                 string             `protobuf:"bytes,1,opt,name=gtin,proto3" json:"gtin,omitempty"`
+	Revisions            []*ProductRevision `protobuf:"bytes,2,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ProductHistory) Reset()         { *m = ProductHistory{} }
+func (m *ProductHistory) String() string { return proto.CompactTextString(m) }
+func (*ProductHistory) ProtoMessage()    {}
+func (*ProductHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f0fd8b59378f44a5, []int{3}
+}
+
+func (m *ProductHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductHistory.Unmarshal(m, b)
+}
+func (m *ProductHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductHistory.Marshal(b, m, deterministic)
+}
+func (m *ProductHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductHistory.Merge(m, src)
+}
+func (m *ProductHistory) XXX_Size() int {
+	return xxx_messageInfo_ProductHistory.Size(m)
+}
+func (m *ProductHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProductHistory proto.InternalMessageInfo
+
+func (m *ProductHistory) GetGtin() string {
+	if m != nil {
+		return m.Gtin
+	}
+	return ""
+}
+
+func (m *ProductHistory) GetRevisions() []*ProductRevision {
+	if m != nil {
+		return m.Revisions
+	}
+	return nil
+}
+
+// ProductHistoryContainer holds the history of every GTIN stored at one state
+// address, sorted by GTIN
+type ProductHistoryContainer struct {
+	Entries              []*ProductHistory `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ProductHistoryContainer) Reset()         { *m = ProductHistoryContainer{} }
+func (m *ProductHistoryContainer) String() string { return proto.CompactTextString(m) }
+func (*ProductHistoryContainer) ProtoMessage()    {}
+func (*ProductHistoryContainer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f0fd8b59378f44a5, []int{4}
+}
+
+func (m *ProductHistoryContainer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProductHistoryContainer.Unmarshal(m, b)
+}
+func (m *ProductHistoryContainer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProductHistoryContainer.Marshal(b, m, deterministic)
+}
+func (m *ProductHistoryContainer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProductHistoryContainer.Merge(m, src)
+}
+func (m *ProductHistoryContainer) XXX_Size() int {
+	return xxx_messageInfo_ProductHistoryContainer.Size(m)
+}
+func (m *ProductHistoryContainer) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProductHistoryContainer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProductHistoryContainer proto.InternalMessageInfo
+
+func (m *ProductHistoryContainer) GetEntries() []*ProductHistory {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Product)(nil), "Product")
+	proto.RegisterType((*ProductContainer)(nil), "ProductContainer")
+	proto.RegisterType((*ProductRevision)(nil), "ProductRevision")
+	proto.RegisterType((*ProductHistory)(nil), "ProductHistory")
+	proto.RegisterType((*ProductHistoryContainer)(nil), "ProductHistoryContainer")
+}
+
+func init() { proto.RegisterFile("product.proto", fileDescriptor_f0fd8b59378f44a5) }
+
+var fileDescriptor_f0fd8b59378f44a5 = []byte{
+	// 395 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xdf, 0x6a, 0x14, 0x31,
+	0x14, 0xc6, 0x99, 0xee, 0x9f, 0xb6, 0x47, 0x76, 0xb7, 0x04, 0xd1, 0xd0, 0xab, 0x65, 0x40, 0x58,
+	0x05, 0x67, 0xa0, 0x05, 0x6f, 0xa5, 0x2a, 0xa2, 0x77, 0x32, 0x88, 0x17, 0xde, 0x0c, 0x99, 0x49,
+	0x3a, 0x1b, 0xd8, 0x26, 0x43, 0xce, 0x99, 0xca, 0x3e, 0x92, 0x6f, 0xe5, 0xa3, 0x48, 0xfe, 0x4c,
+	0xdb, 0x5d, 0xbd, 0xcb, 0xf7, 0x3b, 0x1f, 0x27, 0xe7, 0x7c, 0x09, 0x2c, 0x7a, 0x67, 0xe5, 0xd0,
+	0x52, 0xd1, 0x3b, 0x4b, 0xf6, 0x72, 0xd1, 0x8b, 0xfd, 0xce, 0x0a, 0x19, 0x65, 0xfe, 0x3b, 0x83,
+	0xd3, 0x6f, 0xd1, 0xc0, 0x18, 0x4c, 0x3b, 0xd2, 0x86, 0x67, 0xeb, 0x6c, 0x73, 0x5e, 0x85, 0x33,
+	0x7b, 0x03, 0x20, 0x88, 0x9c, 0x6e, 0x06, 0x52, 0xc8, 0x4f, 0xd6, 0x93, 0xcd, 0xb3, 0x2b, 0x28,
+	0x6e, 0x46, 0x54, 0x3d, 0xa9, 0xb2, 0xe7, 0x30, 0x43, 0x12, 0xa4, 0xf8, 0x24, 0x34, 0x88, 0xc2,
+	0x53, 0xfb, 0xcb, 0x28, 0xc7, 0xa7, 0x91, 0x06, 0xc1, 0x5e, 0xc0, 0xdc, 0x29, 0x81, 0xd6, 0xf0,
+	0x59, 0xc0, 0x49, 0xb1, 0x4b, 0x38, 0x73, 0xea, 0x5e, 0xa3, 0xb6, 0x86, 0xcf, 0xd7, 0xd9, 0x66,
+	0x5a, 0x3d, 0xe8, 0xfc, 0x1d, 0x5c, 0xa4, 0x51, 0x3f, 0x5a, 0x43, 0x42, 0xfb, 0x3e, 0x39, 0x9c,
+	0x2a, 0x43, 0x4e, 0x2b, 0xe4, 0x59, 0x18, 0xee, 0xac, 0x48, 0x9e, 0x6a, 0x2c, 0xe4, 0x7f, 0x32,
+	0x58, 0x8d, 0x30, 0xf5, 0x3a, 0xb8, 0x27, 0x3b, 0xbc, 0xc7, 0xcf, 0x26, 0x5a, 0xf2, 0x95, 0x93,
+	0x38, 0x5b, 0x54, 0x9e, 0xa3, 0xee, 0xfc, 0x2a, 0x71, 0xc1, 0xa4, 0xd8, 0x2b, 0x58, 0x92, 0x13,
+	0x06, 0xa3, 0xad, 0xd6, 0x32, 0xad, 0xba, 0x78, 0x42, 0xbf, 0x4a, 0x6f, 0x6b, 0xb7, 0xc2, 0x74,
+	0x4a, 0xd6, 0xb7, 0x5a, 0xed, 0x24, 0xf2, 0xd9, 0x7a, 0xe2, 0x6d, 0x89, 0x7e, 0x0e, 0x90, 0x5d,
+	0xc3, 0xaa, 0xf7, 0xa3, 0xd8, 0x01, 0xeb, 0x7b, 0xb1, 0x1b, 0x14, 0xf2, 0xf9, 0x3f, 0xb1, 0x2f,
+	0x47, 0xcb, 0x8f, 0xe0, 0xc8, 0xbf, 0xc3, 0x32, 0x6d, 0xf8, 0x45, 0x23, 0x59, 0xb7, 0xff, 0xef,
+	0x63, 0x16, 0x70, 0x3e, 0x2e, 0x39, 0xbe, 0xe5, 0x45, 0x71, 0x94, 0x4c, 0xf5, 0x68, 0xc9, 0x3f,
+	0xc1, 0xcb, 0xc3, 0xae, 0x8f, 0xb9, 0xbf, 0x3e, 0xce, 0x7d, 0x55, 0x1c, 0x5a, 0x1f, 0xe2, 0xff,
+	0x70, 0xf3, 0xf3, 0x7d, 0xa7, 0x69, 0x3b, 0x34, 0x45, 0x6b, 0xef, 0x4a, 0x72, 0x16, 0xf1, 0x2d,
+	0xed, 0xd1, 0x9a, 0xf2, 0x4e, 0x0a, 0x12, 0x75, 0x67, 0x4b, 0x74, 0x6d, 0x89, 0x5b, 0xe1, 0x94,
+	0x2c, 0xc3, 0xb7, 0x6c, 0x86, 0xdb, 0x32, 0x7d, 0xdb, 0xba, 0x6f, 0xae, 0x9a, 0x79, 0xa0, 0xd7,
+	0x7f, 0x07, 0x00, 0x21, 0x8a, 0x49, 0xdf, 0xcc, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,280 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: schema.proto
+
+package schema_pb2
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type PropertyDefinition_DataType int32
+
+const (
+	PropertyDefinition_STRING  PropertyDefinition_DataType = 0
+	PropertyDefinition_INTEGER PropertyDefinition_DataType = 1
+	PropertyDefinition_DECIMAL PropertyDefinition_DataType = 2
+	PropertyDefinition_ENUM    PropertyDefinition_DataType = 3
+	PropertyDefinition_BOOLEAN PropertyDefinition_DataType = 4
+	// Calendar date, YYYY-MM-DD
+	PropertyDefinition_DATE PropertyDefinition_DataType = 5
+	// Decimal value and unit, e.g. "12.5 kg"
+	PropertyDefinition_MEASURE PropertyDefinition_DataType = 6
+)
+
+var PropertyDefinition_DataType_name = map[int32]string{
+	0: "STRING",
+	1: "INTEGER",
+	2: "DECIMAL",
+	3: "ENUM",
+	4: "BOOLEAN",
+	5: "DATE",
+	6: "MEASURE",
+}
+
+var PropertyDefinition_DataType_value = map[string]int32{
+	"STRING":  0,
+	"INTEGER": 1,
+	"DECIMAL": 2,
+	"ENUM":    3,
+	"BOOLEAN": 4,
+	"DATE":    5,
+	"MEASURE": 6,
+}
+
+func (x PropertyDefinition_DataType) String() string {
+	return proto.EnumName(PropertyDefinition_DataType_name, int32(x))
+}
+
+func (PropertyDefinition_DataType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{0, 0}
+}
+
+// PropertyDefinition describes one product attribute a schema allows
+type PropertyDefinition struct {
+	Name     string                      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DataType PropertyDefinition_DataType `protobuf:"varint,2,opt,name=data_type,json=dataType,proto3,enum=PropertyDefinition_DataType" json:"data_type,omitempty"`
+	Required bool                        `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	// Allowed values of an ENUM
+	EnumOptions []string `protobuf:"bytes,4,rep,name=enum_options,json=enumOptions,proto3" json:"enum_options,omitempty"`
+	// Allowed units of a MEASURE, any unit if empty
+	Units                []string `protobuf:"bytes,5,rep,name=units,proto3" json:"units,omitempty"`
+	Description          string   `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PropertyDefinition) Reset()         { *m = PropertyDefinition{} }
+func (m *PropertyDefinition) String() string { return proto.CompactTextString(m) }
+func (*PropertyDefinition) ProtoMessage()    {}
+func (*PropertyDefinition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{0}
+}
+
+func (m *PropertyDefinition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PropertyDefinition.Unmarshal(m, b)
+}
+func (m *PropertyDefinition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PropertyDefinition.Marshal(b, m, deterministic)
+}
+func (m *PropertyDefinition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PropertyDefinition.Merge(m, src)
+}
+func (m *PropertyDefinition) XXX_Size() int {
+	return xxx_messageInfo_PropertyDefinition.Size(m)
+}
+func (m *PropertyDefinition) XXX_DiscardUnknown() {
+	xxx_messageInfo_PropertyDefinition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PropertyDefinition proto.InternalMessageInfo
+
+func (m *PropertyDefinition) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PropertyDefinition) GetDataType() PropertyDefinition_DataType {
+	if m != nil {
+		return m.DataType
+	}
+	return PropertyDefinition_STRING
+}
+
+func (m *PropertyDefinition) GetRequired() bool {
+	if m != nil {
+		return m.Required
+	}
+	return false
+}
+
+func (m *PropertyDefinition) GetEnumOptions() []string {
+	if m != nil {
+		return m.EnumOptions
+	}
+	return nil
+}
+
+func (m *PropertyDefinition) GetUnits() []string {
+	if m != nil {
+		return m.Units
+	}
+	return nil
+}
+
+func (m *PropertyDefinition) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+// Schema defines the attributes products may have. Properties are sorted by
+// name.
+type Schema struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Id of the organization allowed to change the schema
+	Owner                string                `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Properties           []*PropertyDefinition `protobuf:"bytes,3,rep,name=properties,proto3" json:"properties,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *Schema) Reset()         { *m = Schema{} }
+func (m *Schema) String() string { return proto.CompactTextString(m) }
+func (*Schema) ProtoMessage()    {}
+func (*Schema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{1}
+}
+
+func (m *Schema) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schema.Unmarshal(m, b)
+}
+func (m *Schema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schema.Marshal(b, m, deterministic)
+}
+func (m *Schema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schema.Merge(m, src)
+}
+func (m *Schema) XXX_Size() int {
+	return xxx_messageInfo_Schema.Size(m)
+}
+func (m *Schema) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schema proto.InternalMessageInfo
+
+func (m *Schema) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Schema) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *Schema) GetProperties() []*PropertyDefinition {
+	if m != nil {
+		return m.Properties
+	}
+	return nil
+}
+
+type SchemaContainer struct {
+	Entries              []*Schema `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SchemaContainer) Reset()         { *m = SchemaContainer{} }
+func (m *SchemaContainer) String() string { return proto.CompactTextString(m) }
+func (*SchemaContainer) ProtoMessage()    {}
+func (*SchemaContainer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{2}
+}
+
+func (m *SchemaContainer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SchemaContainer.Unmarshal(m, b)
+}
+func (m *SchemaContainer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SchemaContainer.Marshal(b, m, deterministic)
+}
+func (m *SchemaContainer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchemaContainer.Merge(m, src)
+}
+func (m *SchemaContainer) XXX_Size() int {
+	return xxx_messageInfo_SchemaContainer.Size(m)
+}
+func (m *SchemaContainer) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchemaContainer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SchemaContainer proto.InternalMessageInfo
+
+func (m *SchemaContainer) GetEntries() []*Schema {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("PropertyDefinition_DataType", PropertyDefinition_DataType_name, PropertyDefinition_DataType_value)
+	proto.RegisterType((*PropertyDefinition)(nil), "PropertyDefinition")
+	proto.RegisterType((*Schema)(nil), "Schema")
+	proto.RegisterType((*SchemaContainer)(nil), "SchemaContainer")
+}
+
+func init() { proto.RegisterFile("schema.proto", fileDescriptor_1c5fb4d8cc22d66a) }
+
+var fileDescriptor_1c5fb4d8cc22d66a = []byte{
+	// 390 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x31, 0x6f, 0xdb, 0x30,
+	0x10, 0x85, 0x2b, 0xcb, 0x96, 0xe5, 0x73, 0xd0, 0x0a, 0x6c, 0x06, 0xa1, 0xe8, 0xa0, 0x78, 0xd2,
+	0x52, 0x09, 0x70, 0xba, 0x74, 0x29, 0xea, 0xc4, 0x44, 0x60, 0x20, 0xb6, 0x0b, 0x5a, 0x59, 0x3a,
+	0xd4, 0xa0, 0x24, 0xc6, 0xe6, 0x20, 0x52, 0x25, 0x29, 0x14, 0xfa, 0x6b, 0xfd, 0x75, 0x05, 0xa9,
+	0xa6, 0x08, 0x90, 0x6c, 0xf7, 0x3e, 0xdd, 0x3d, 0x1d, 0x1e, 0x0f, 0x2e, 0x74, 0x75, 0x66, 0x0d,
+	0xcd, 0x5a, 0x25, 0x8d, 0x5c, 0xfc, 0x19, 0x01, 0xfa, 0xae, 0x64, 0xcb, 0x94, 0xe9, 0xd7, 0xec,
+	0x91, 0x0b, 0x6e, 0xb8, 0x14, 0x08, 0xc1, 0x58, 0xd0, 0x86, 0xc5, 0x5e, 0xe2, 0xa5, 0x33, 0xe2,
+	0x6a, 0xf4, 0x05, 0x66, 0x35, 0x35, 0xf4, 0x68, 0xfa, 0x96, 0xc5, 0xa3, 0xc4, 0x4b, 0xdf, 0x2e,
+	0x3f, 0x66, 0x2f, 0x67, 0xb3, 0x35, 0x35, 0xb4, 0xe8, 0x5b, 0x46, 0xc2, 0xfa, 0x5f, 0x85, 0x3e,
+	0x40, 0xa8, 0xd8, 0xaf, 0x8e, 0x2b, 0x56, 0xc7, 0x7e, 0xe2, 0xa5, 0x21, 0xf9, 0xaf, 0xd1, 0x15,
+	0x5c, 0x30, 0xd1, 0x35, 0x47, 0xd9, 0xda, 0x69, 0x1d, 0x8f, 0x13, 0x3f, 0x9d, 0x91, 0xb9, 0x65,
+	0xfb, 0x01, 0xa1, 0x4b, 0x98, 0x74, 0x82, 0x1b, 0x1d, 0x4f, 0xdc, 0xb7, 0x41, 0xa0, 0x04, 0xe6,
+	0x35, 0xd3, 0x95, 0xe2, 0xae, 0x2b, 0x0e, 0xdc, 0xaa, 0xcf, 0xd1, 0xe2, 0x27, 0x84, 0x4f, 0xcb,
+	0x20, 0x80, 0xe0, 0x50, 0x90, 0xcd, 0xee, 0x2e, 0x7a, 0x83, 0xe6, 0x30, 0xdd, 0xec, 0x0a, 0x7c,
+	0x87, 0x49, 0xe4, 0x59, 0xb1, 0xc6, 0xb7, 0x9b, 0xed, 0xea, 0x3e, 0x1a, 0xa1, 0x10, 0xc6, 0x78,
+	0xf7, 0xb0, 0x8d, 0x7c, 0x8b, 0x6f, 0xf6, 0xfb, 0x7b, 0xbc, 0xda, 0x45, 0x63, 0x8b, 0xd7, 0xab,
+	0x02, 0x47, 0x13, 0x8b, 0xb7, 0x78, 0x75, 0x78, 0x20, 0x38, 0x0a, 0x16, 0x27, 0x08, 0x0e, 0x2e,
+	0xcc, 0x57, 0xf3, 0xba, 0x84, 0x89, 0xfc, 0x2d, 0x98, 0x72, 0x59, 0xcd, 0xc8, 0x20, 0xd0, 0x35,
+	0x40, 0x3b, 0x64, 0xc6, 0x99, 0x8e, 0xfd, 0xc4, 0x4f, 0xe7, 0xcb, 0xf7, 0xaf, 0xc4, 0x48, 0x9e,
+	0xb5, 0x2d, 0x3e, 0xc3, 0xbb, 0xe1, 0x47, 0xb7, 0x52, 0x18, 0xca, 0xad, 0xcf, 0x15, 0x4c, 0x99,
+	0x30, 0xca, 0x9a, 0x78, 0xce, 0x64, 0x9a, 0x0d, 0x2d, 0xe4, 0x89, 0xdf, 0x7c, 0xfb, 0xf1, 0xf5,
+	0xc4, 0xcd, 0xb9, 0x2b, 0xb3, 0x4a, 0x36, 0xb9, 0x51, 0x52, 0xeb, 0x4f, 0xa6, 0xd7, 0x52, 0xe4,
+	0x8d, 0x7b, 0xc8, 0x93, 0xcc, 0xb5, 0xaa, 0x72, 0x7d, 0xa6, 0x8a, 0xd5, 0xb9, 0xbb, 0x87, 0xb2,
+	0x7b, 0xcc, 0x87, 0xfb, 0x38, 0xb6, 0xe5, 0xb2, 0x0c, 0x1c, 0xbc, 0xfe, 0x3b, 0x00, 0xc2, 0xee,
+	0xaf, 0xee, 0x34, 0x02, 0x00, 0x00,
+}